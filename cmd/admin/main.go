@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/signer"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/service"
+)
+
+// 合约管理操作命令行：管理员使用本地keystore（或开发私钥）签名后调用后端管理接口
+//
+//	admin propose-feed   [-chain 11155111] -token 0x.. -feed 0x..
+//	admin propose-upgrade [-chain 11155111] -impl 0x.. [-calldata 0x..]
+//	admin approve -id 1
+//	admin reject  -id 1 -reason "..."
+//	admin submit  -id 1
+//	admin list [-chain 11155111] [-status proposed]
+//	admin show -id 1
+const usage = `用法: admin <propose-feed|propose-upgrade|approve|reject|submit|list|show> [参数]
+
+公共参数:
+  -api             后端地址（默认 http://127.0.0.1:8080）
+  -keystore        管理员keystore文件
+  -passphrase-env  keystore口令环境变量（默认 ADMIN_PASSPHRASE）
+  -dev-key-env     开发私钥环境变量，设置后忽略keystore（仅用于开发环境）
+  -chain           目标链ID（提议时为空表示后端默认链，列表时为空表示全部链）
+`
+
+// client 管理接口客户端
+type client struct {
+	api    string
+	signer signer.MessageSigner
+	http   *http.Client
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	api := fs.String("api", "http://127.0.0.1:8080", "后端地址")
+	keystorePath := fs.String("keystore", "", "管理员keystore文件")
+	passphraseEnv := fs.String("passphrase-env", "ADMIN_PASSPHRASE", "keystore口令环境变量")
+	devKeyEnv := fs.String("dev-key-env", "", "开发私钥环境变量")
+	chainID := fs.Uint64("chain", 0, "目标链ID")
+	id := fs.Uint("id", 0, "管理操作ID")
+	token := fs.String("token", "", "币种地址（为空表示ETH）")
+	feed := fs.String("feed", "", "预言机地址")
+	impl := fs.String("impl", "", "新实现合约地址")
+	callData := fs.String("calldata", "", "升级后调用数据（hex）")
+	reason := fs.String("reason", "", "驳回原因")
+	status := fs.String("status", "", "按状态过滤")
+	fs.Parse(os.Args[2:])
+
+	c := &client{api: strings.TrimRight(*api, "/"), http: &http.Client{Timeout: 60 * time.Second}}
+
+	var err error
+	switch command {
+	case "list":
+		query := url.Values{"status": {*status}}
+		if *chainID != 0 {
+			query.Set("chain_id", strconv.FormatUint(*chainID, 10))
+		}
+		err = c.get("/api/admin/operations?" + query.Encode())
+	case "show":
+		err = c.get(fmt.Sprintf("/api/admin/operations/%d", *id))
+	case "propose-feed", "propose-upgrade", "approve", "reject", "submit":
+		if c.signer, err = loadSigner(*keystorePath, *passphraseEnv, *devKeyEnv); err != nil {
+			break
+		}
+		switch command {
+		case "propose-feed":
+			err = c.propose(*chainID, models.AdminOperationSetPriceFeed, models.AdminOperationParams{Token: *token, Feed: *feed})
+		case "propose-upgrade":
+			err = c.propose(*chainID, models.AdminOperationUpgrade, models.AdminOperationParams{Implementation: *impl, CallData: *callData})
+		case "approve":
+			err = c.act(*id, service.AdminActionApprove, nil)
+		case "reject":
+			err = c.act(*id, service.AdminActionReject, map[string]interface{}{"reason": *reason})
+		case "submit":
+			err = c.act(*id, service.AdminActionSubmit, nil)
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "错误:", err)
+		os.Exit(1)
+	}
+}
+
+// loadSigner 加载管理员签名器
+func loadSigner(keystorePath, passphraseEnv, devKeyEnv string) (signer.MessageSigner, error) {
+	var (
+		s   signer.Signer
+		err error
+	)
+	if devKeyEnv != "" {
+		s, err = signer.NewDevSigner(os.Getenv(devKeyEnv))
+	} else {
+		if keystorePath == "" {
+			return nil, fmt.Errorf("需要-keystore或-dev-key-env")
+		}
+		s, err = signer.NewKeystoreSigner(keystorePath, os.Getenv(passphraseEnv))
+	}
+	if err != nil {
+		return nil, err
+	}
+	ms, ok := s.(signer.MessageSigner)
+	if !ok {
+		return nil, fmt.Errorf("签名器不支持消息签名")
+	}
+	return ms, nil
+}
+
+// propose 提议管理操作，chainID为0表示后端默认链
+func (c *client) propose(chainID uint64, opType models.AdminOperationType, params models.AdminOperationParams) error {
+	params, err := service.NormalizeAdminParams(opType, params)
+	if err != nil {
+		return err
+	}
+	body, err := c.sign(service.AdminActionPropose, service.ProposalTarget(chainID, opType, params))
+	if err != nil {
+		return err
+	}
+	body["chain_id"] = chainID
+	body["type"] = opType
+	body["params"] = params
+	return c.post("/api/admin/operations", body)
+}
+
+// act 审批、驳回或提交管理操作
+func (c *client) act(id uint, action string, extra map[string]interface{}) error {
+	if id == 0 {
+		return fmt.Errorf("需要-id")
+	}
+	body, err := c.sign(action, service.OperationTarget(id))
+	if err != nil {
+		return err
+	}
+	for k, v := range extra {
+		body[k] = v
+	}
+	return c.post(fmt.Sprintf("/api/admin/operations/%d/%s", id, action), body)
+}
+
+// sign 签名管理消息，返回请求体中的签名字段
+func (c *client) sign(action, target string) (map[string]interface{}, error) {
+	issuedAt := time.Now().Unix()
+	sig, err := c.signer.SignMessage(context.Background(), []byte(service.AdminMessage(action, target, issuedAt)))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"admin":     c.signer.Address().Hex(),
+		"issued_at": issuedAt,
+		"signature": hexutil.Encode(sig),
+	}, nil
+}
+
+func (c *client) get(path string) error {
+	resp, err := c.http.Get(c.api + path)
+	if err != nil {
+		return err
+	}
+	return printResponse(resp)
+}
+
+func (c *client) post(path string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Post(c.api+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return printResponse(resp)
+}
+
+// printResponse 格式化输出响应，非2xx返回错误
+func printResponse(resp *http.Response) error {
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if json.Indent(&out, data, "", "  ") == nil {
+		data = out.Bytes()
+	}
+	fmt.Println(string(data))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("请求失败，状态码%d", resp.StatusCode)
+	}
+	return nil
+}
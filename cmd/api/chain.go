@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/ERC721"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/NFTAuction"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/archive"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/backfill"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/headercache"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/rpcpool"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/signer"
+	"github.com/ydh2333/NFTAuction-project/internal/keeper"
+	"github.com/ydh2333/NFTAuction-project/internal/monitor"
+	"github.com/ydh2333/NFTAuction-project/internal/service"
+)
+
+// chainRuntime 单条链的监听器及其依赖
+type chainRuntime struct {
+	cfg              config.BlockchainConfig
+	client           chainclient.ChainClient // 监听器共用的链上客户端
+	erc721Listener   *ERC721.ERC721Listener
+	auctionListeners []*NFTAuction.Listener // 每个拍卖合约部署一个
+	closers          []func() error
+}
+
+// setupChain 连接链上节点、校验链ID、初始化监听器并回填历史区块
+func setupChain(ctx context.Context, cfg config.BlockchainConfig) (*chainRuntime, error) {
+	chain := &chainRuntime{cfg: cfg}
+
+	// 监听器共用的链上客户端（WebSocket节点组，订阅断开时自动切换节点）
+	wsPool, err := rpcpool.Dial(cfg.PoolName("ws"), cfg.WSEndpoints(), cfg.RPCPool)
+	if err != nil {
+		return nil, fmt.Errorf("连接区块链节点失败: %w", err)
+	}
+	chain.closers = append(chain.closers, func() error { wsPool.Close(); return nil })
+
+	// 记录写入每条数据的链ID，未配置时以节点返回为准
+	chainID, err := wsPool.ChainID(ctx)
+	if err != nil {
+		chain.Close()
+		return nil, fmt.Errorf("获取链ID失败: %w", err)
+	}
+	if cfg.ChainID != 0 && cfg.ChainID != chainID.Uint64() {
+		chain.Close()
+		return nil, fmt.Errorf("节点链ID为%s，与配置的%d不一致", chainID, cfg.ChainID)
+	}
+	chain.cfg.ChainID = chainID.Uint64()
+
+	// 配置日志归档时记录收到的日志、区块头与合约调用
+	chain.client = wsPool
+	if cfg.LogArchive != "" {
+		recorder, err := archive.NewRecorder(wsPool, cfg.LogArchive)
+		if err != nil {
+			chain.Close()
+			return nil, fmt.Errorf("初始化日志归档失败: %w", err)
+		}
+		chain.closers = append(chain.closers, recorder.Close)
+		chain.client = recorder
+		log.Info().Str("chain", cfg.Label()).Str("path", cfg.LogArchive).Msg("已开启链上日志归档")
+	}
+
+	// 初始化erc721SafeMint监听器
+	chain.erc721Listener, err = ERC721.NewERC721ListenerWithClient(&chain.cfg, chain.client)
+	if err != nil {
+		chain.Close()
+		return nil, fmt.Errorf("初始化erc721SafeMint监听器失败: %w", err)
+	}
+
+	// 初始化auction链监听器（当前部署与仍需索引的旧部署）
+	chain.auctionListeners, err = NFTAuction.NewListenersWithClient(&chain.cfg, chain.client)
+	if err != nil {
+		chain.Close()
+		return nil, fmt.Errorf("初始化auction监听器失败: %w", err)
+	}
+
+	// 回填历史区块
+	if cfg.Backfill.Enabled {
+		engine := backfill.NewEngine(&chain.cfg, chain.client, chain.auctionListeners, chain.erc721Listener)
+		last, err := engine.Run(ctx)
+		if err != nil {
+			chain.Close()
+			return nil, fmt.Errorf("回填历史区块失败: %w", err)
+		}
+		// 监听器启动前还要初始化其他链与热度排行，期间出块的日志在订阅建立后从回填结束处补齐
+		chain.erc721Listener.SetResumeBlock(last + 1)
+		for _, listener := range chain.auctionListeners {
+			listener.SetResumeBlock(last + 1)
+		}
+	}
+	return chain, nil
+}
+
+// Start 启动监听器与区块头检查，初始化拍卖合约、管理操作审批、结算守护与热钱包监控（监听在后台运行）
+func (c *chainRuntime) Start(ctx context.Context) {
+	chainLog := log.With().Str("chain", c.cfg.Label()).Uint64("chain_id", c.cfg.ChainID).Logger()
+
+	// 启动ERC721监听器（后台协程，避免阻塞主线程）
+	go func() {
+		chainLog.Info().Msg("启动ERC721 safeMint监听器")
+		if err := c.erc721Listener.StartListeningSafeMint(ctx); err != nil {
+			chainLog.Error().Err(err).Msg("监听safeMint失败")
+		}
+	}()
+
+	for _, listener := range c.auctionListeners {
+		contractLog := chainLog.With().Str("contract", listener.ContractAddress().Hex()).Logger()
+		go func() {
+			contractLog.Info().Msg("启动NFTAuction监听器")
+			if err := listener.Start(ctx); err != nil {
+				contractLog.Error().Err(err).Msg("NFTAuction监听器退出")
+			}
+		}()
+	}
+
+	// 监听器共享的区块头缓存：定期检查最新区块，按父哈希发现链重组
+	go func() {
+		if err := headercache.For(c.client, c.cfg.ChainID, c.cfg.HeaderCache).Track(ctx); err != nil && ctx.Err() == nil {
+			chainLog.Error().Err(err).Msg("区块头缓存检查退出")
+		}
+	}()
+
+	// 初始化拍卖合约（交易构造接口与结算守护共用），签名器可选
+	var txSigner signer.Signer
+	if c.cfg.Signer.Type != "" {
+		var err error
+		txSigner, err = signer.FromConfig(&c.cfg.Signer)
+		if err != nil {
+			chainLog.Fatal().Err(err).Msg("初始化签名器失败")
+		}
+	}
+	if err := blockchain.InitAuctionContract(&c.cfg, txSigner); err != nil {
+		chainLog.Fatal().Err(err).Msg("初始化拍卖合约失败")
+	}
+	contract, _ := blockchain.AuctionOn(c.cfg.ChainID)
+
+	// 合约管理操作按链校验管理员签名与审批人数
+	service.InitAdminOperations(c.cfg.ChainID, c.cfg.Admin)
+
+	// 初始化到期拍卖结算守护（需要后端签名器），旧部署上的拍卖同样需要结算
+	if c.cfg.Keeper.Enabled {
+		if txSigner == nil {
+			chainLog.Fatal().Msg("结算守护需要配置签名器")
+		}
+		for _, deployment := range blockchain.AuctionDeploymentsOn(c.cfg.ChainID) {
+			auctionKeeper := keeper.NewAuctionKeeper(deployment, c.cfg.Keeper)
+			go func() {
+				if err := auctionKeeper.Start(ctx); err != nil {
+					chainLog.Error().Err(err).Str("contract", deployment.Address().Hex()).Msg("拍卖结算守护退出")
+				}
+			}()
+		}
+	}
+
+	// 初始化热钱包监控（需要后端签名器）
+	if c.cfg.Monitor.Enabled {
+		if txSigner == nil {
+			chainLog.Fatal().Msg("热钱包监控需要配置签名器")
+		}
+		walletMonitor := monitor.InitWalletMonitor(contract.Client(), c.cfg.ChainID, txSigner.Address(), c.cfg.Monitor)
+		go func() {
+			if err := walletMonitor.Start(ctx); err != nil {
+				chainLog.Error().Err(err).Msg("热钱包监控退出")
+			}
+		}()
+	}
+}
+
+// Close 关闭日志归档与节点连接
+func (c *chainRuntime) Close() {
+	for i := len(c.closers) - 1; i >= 0; i-- {
+		if err := c.closers[i](); err != nil {
+			log.Warn().Err(err).Str("chain", c.cfg.Label()).Msg("关闭链上客户端失败")
+		}
+	}
+	c.closers = nil
+}
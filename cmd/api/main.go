@@ -11,11 +11,10 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/ydh2333/NFTAuction-project/config"
 	"github.com/ydh2333/NFTAuction-project/internal/api/routes"
-	"github.com/ydh2333/NFTAuction-project/internal/blockchain/ERC721"
-	"github.com/ydh2333/NFTAuction-project/internal/blockchain/NFTAuction"
 	"github.com/ydh2333/NFTAuction-project/internal/models"
 	"github.com/ydh2333/NFTAuction-project/internal/redis"
 	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/internal/service"
 	"github.com/ydh2333/NFTAuction-project/utils/logger"
 )
 
@@ -32,19 +31,6 @@ func main() {
 	// 4. 初始化redis
 	redis.Init(&cfg.Redis)
 
-	// 5. 初始化热度排行，从Mysql中获取
-	var bids []models.Bid
-	bids, err := repository.NewBidRepository().GetBidAll()
-	if err != nil {
-		log.Error().Err(err).Msg("获取所有出价记录失败")
-		return
-	}
-	if err := redis.InitHotRankFromDB(bids); err != nil {
-		log.Error().Err(err).Msg("初始化Redis热度排行失败")
-		return
-	}
-	log.Info().Msg("初始化Redis热度排行成功")
-
 	// 4. 创建全局上下文,统一管理所有监听器
 	ctx, cancel := context.WithCancel(context.Background())
 	// 确保程序退出时执行cancel，且只执行一次
@@ -53,39 +39,42 @@ func main() {
 		log.Info().Msg("全局上下文已关闭，所有监听器停止")
 	}()
 
-	// 5. 初始化erc721SafeMint监听器
-	erc721Listener, err := ERC721.NewERC721Listener(&cfg.Blockchain)
-	if err != nil {
-		log.Fatal().Err(err).Msg("初始化erc721SafeMint监听器失败")
-	}
-
-	// 启动ERC721监听器（后台协程，避免阻塞主线程）
-	go func() {
-		log.Info().Msg("启动ERC721 safeMint监听器")
-		if err := erc721Listener.StartListeningSafeMint(ctx); err != nil {
-			log.Error().Err(err).Msg("监听safeMint失败")
+	// 5. 逐条链连接节点、初始化监听器并回填历史区块（监听器订阅后从回填结束处补齐期间出块的日志）
+	var chains []*chainRuntime
+	for _, chainCfg := range cfg.ChainConfigs() {
+		chain, err := setupChain(ctx, chainCfg)
+		if err != nil {
+			log.Fatal().Err(err).Str("chain", chainCfg.Label()).Msg("初始化链失败")
 		}
-	}()
+		defer chain.Close()
+		chains = append(chains, chain)
+	}
 
-	// 6. 初始化auction链监听器
-	auctionListener, err := NFTAuction.NewListener(&cfg.Blockchain)
+	// 初始化热度排行，从Mysql中获取（包含各链回填的出价）
+	var bids []models.Bid
+	bids, err := repository.NewBidRepository().GetBidAll()
 	if err != nil {
-		log.Fatal().Err(err).Msg("初始化auction监听器失败")
+		log.Error().Err(err).Msg("获取所有出价记录失败")
+		return
 	}
+	if err := redis.InitHotRankFromDB(bids); err != nil {
+		log.Error().Err(err).Msg("初始化Redis热度排行失败")
+		return
+	}
+	log.Info().Msg("初始化Redis热度排行成功")
 
-	go func() {
-		log.Info().Msg("启动NFTAuction监听器")
-		if err := auctionListener.Start(ctx); err != nil {
-			log.Error().Err(err).Msg("NFTAuction监听器退出")
-		}
-	}()
+	// 6. 启动各链的监听器、结算守护与热钱包监控
+	for _, chain := range chains {
+		chain.Start(ctx)
+	}
+	service.ResumeAdminOperations()
 
 	// 7. 初始化Gin
 	gin.SetMode(gin.ReleaseMode) // 生产环境使用ReleaseMode
 	r := gin.Default()
 
 	// 8. 注册路由
-	routes.InitRoutes(r)
+	routes.InitRoutes(r, os.Getenv(cfg.Server.AdminTokenEnv))
 
 	// 9. 启动HTTP服务
 	srv := &http.Server{
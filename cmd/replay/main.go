@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/ERC721"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/NFTAuction"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/archive"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/redis"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/utils/logger"
+)
+
+// 回放日志归档：按链上顺序把归档中的日志逐条交给NFTAuction（每个拍卖合约部署一个）/ERC721监听器的处理函数，重建数据库
+// 区块时间、tokenURI与合约快照由归档应答，不访问RPC节点（NFT元数据仍从tokenURI指向的地址获取）。
+//
+//	go run ./cmd/replay -archive ./data/chain-logs.jsonl [-chain sepolia] [-dsn "user:pass@tcp(host:3306)/nft_auction_rebuild?..."] [-force]
+//
+// 归档只包含一条链的日志，-chain按链名称或链ID选择对应的链配置（默认第一条链），该链须配置ChainID。
+// Redis拍卖热度排行在API服务启动时会从MySQL重建，回放期间写入的热度无需单独处理。
+func main() {
+	archivePath := flag.String("archive", "", "日志归档文件（JSONL）")
+	dsn := flag.String("dsn", "", "目标数据库DSN，为空时使用配置中的mysql.dsn")
+	force := flag.Bool("force", false, "目标数据库已有拍卖数据时仍然回放")
+	chain := flag.String("chain", "", "归档所属链的名称或链ID，为空时使用第一条链")
+	flag.Parse()
+	if *archivePath == "" {
+		fmt.Fprintln(os.Stderr, "用法: replay -archive <归档文件> [-chain <链>] [-dsn <目标数据库>] [-force]")
+		os.Exit(2)
+	}
+
+	cfg := config.LoadConfig()
+	logger.InitLogger()
+	chainCfg, err := selectChain(cfg, *chain)
+	if err != nil {
+		log.Fatal().Err(err).Msg("选择链配置失败")
+	}
+	if *dsn != "" {
+		cfg.MySQL.DSN = *dsn
+	}
+	repository.InitDB(&cfg.MySQL)
+	defer repository.CloseDB()
+	redis.Init(&cfg.Redis)
+	defer redis.CloseRedis()
+
+	if !*force {
+		if err := requireEmpty(); err != nil {
+			log.Fatal().Err(err).Msg("目标数据库不是空库，如确认要回放请加-force")
+		}
+	}
+
+	logArchive, err := archive.Load(*archivePath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("加载日志归档失败")
+	}
+
+	auctionListeners, err := NFTAuction.NewListenersWithClient(chainCfg, logArchive)
+	if err != nil {
+		log.Fatal().Err(err).Msg("初始化auction监听器失败")
+	}
+	erc721Listener, err := ERC721.NewERC721ListenerWithClient(chainCfg, logArchive)
+	if err != nil {
+		log.Fatal().Err(err).Msg("初始化erc721监听器失败")
+	}
+
+	ctx := context.Background()
+	logs := logArchive.Logs()
+	failed := 0
+	for _, lg := range logs {
+		for _, auctionListener := range auctionListeners {
+			if err := auctionListener.HandleLog(lg); err != nil {
+				failed++
+				log.Error().Err(err).Uint64("block", lg.BlockNumber).Str("tx_hash", lg.TxHash.Hex()).Msg("回放拍卖事件失败")
+			}
+		}
+		if err := erc721Listener.HandleLog(ctx, lg); err != nil {
+			failed++
+			log.Error().Err(err).Uint64("block", lg.BlockNumber).Str("tx_hash", lg.TxHash.Hex()).Msg("回放ERC721事件失败")
+		}
+	}
+
+	log.Info().Int("logs", len(logs)).Int("failed", failed).Msg("日志归档回放完成")
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// requireEmpty 校验目标数据库中没有NFT、拍卖与出价数据
+func requireEmpty() error {
+	for name, model := range map[string]interface{}{"nfts": &models.NFT{}, "auctions": &models.Auction{}, "bids": &models.Bid{}} {
+		var count int64
+		if err := repository.DB.Model(model).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("表%s已有%d条记录", name, count)
+		}
+	}
+	return nil
+}
+
+// selectChain 按名称或链ID选择链配置，name为空时返回第一条链；回放写入的记录需要链ID
+func selectChain(cfg *config.Config, name string) (*config.BlockchainConfig, error) {
+	chains := cfg.ChainConfigs()
+	for i := range chains {
+		chain := &chains[i]
+		if name != "" && chain.Name != name && strconv.FormatUint(chain.ChainID, 10) != name {
+			continue
+		}
+		if chain.ChainID == 0 {
+			return nil, fmt.Errorf("链%s未配置ChainID", chain.Label())
+		}
+		return chain, nil
+	}
+	return nil, fmt.Errorf("未找到链%s", name)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/signer"
+	"github.com/ydh2333/NFTAuction-project/utils/logger"
+)
+
+// 本地远程签名服务：使用keystore或开发私钥提供与远程签名器相同的HTTP接口，
+// 用于开发环境替代真实签名服务（后端配置 Signer.Type=remote, Signer.RemoteURL=http://127.0.0.1:8555）
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8555", "签名服务监听地址")
+	signerType := flag.String("type", "", "本地签名器类型（keystore/dev），默认使用配置文件")
+	flag.Parse()
+
+	// 1. 初始化配置
+	cfg := config.LoadConfig()
+
+	// 2. 初始化日志
+	logger.InitLogger()
+
+	// 3. 创建本地签名器（远程签名器不能再包装为远程服务）
+	signerCfg := cfg.Blockchain.Signer
+	if *signerType != "" {
+		signerCfg.Type = *signerType
+	}
+	if signerCfg.Type == signer.TypeRemote {
+		log.Fatal().Msg("本地签名服务需要keystore或dev类型的签名器")
+	}
+	localSigner, err := signer.FromConfig(&signerCfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("初始化签名器失败")
+	}
+
+	// 4. 启动HTTP服务
+	handler, err := signer.NewRemoteHandler(localSigner, os.Getenv(signerCfg.RemoteTokenEnv))
+	if err != nil {
+		log.Fatal().Err(err).Str("env", signerCfg.RemoteTokenEnv).Msg("初始化签名服务失败")
+	}
+	log.Info().Str("addr", *addr).Str("address", localSigner.Address().Hex()).Msg("签名服务启动")
+	if err := http.ListenAndServe(*addr, handler); err != nil {
+		log.Fatal().Err(err).Msg("签名服务退出")
+	}
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -12,15 +14,25 @@ import (
 type Config struct {
 	Server     ServerConfig
 	MySQL      MySQLConfig
-	Blockchain BlockchainConfig
+	Blockchain BlockchainConfig   // 单链配置；配置Chains时作为各链的公共默认值
+	Chains     []BlockchainConfig // 多链配置，每条链只需填写与Blockchain不同的字段
 	Redis      RedisConfig
 }
 
+// ChainConfigs 需要索引的全部链，未配置Chains时只有Blockchain一条链
+func (c *Config) ChainConfigs() []BlockchainConfig {
+	if len(c.Chains) > 0 {
+		return c.Chains
+	}
+	return []BlockchainConfig{c.Blockchain}
+}
+
 // ServerConfig 服务配置
 type ServerConfig struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	Port          string
+	ReadTimeout   time.Duration
+	WriteTimeout  time.Duration
+	AdminTokenEnv string // 运维与合约管理接口访问令牌所在环境变量
 }
 
 // MySQLConfig 数据库配置
@@ -29,6 +41,8 @@ type MySQLConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+	LegacyChainID   uint64 // 支持多链前写入的数据所在链ID，迁移时回填chain_id为0的记录，未配置时使用Blockchain.ChainID
+	LegacyContract  string // 支持多个拍卖合约前写入的拍卖数据所在合约，迁移时回填为空的contract_address，未配置时使用Blockchain.ContractAddr
 }
 
 // RedisConfig Redis配置
@@ -41,13 +55,185 @@ type RedisConfig struct {
 
 // BlockchainConfig 区块链配置
 type BlockchainConfig struct {
-	RPCEndpoint        string        // 区块链节点RPC地址
-	WSRpcEndpoint      string        // 区块链节点WebSocket RPC地址
-	ContractAddr       string        // 已部署的拍卖合约地址
-	ERC721ContractAddr string        // 已部署的ERC721合约地址
-	PrivateKey         string        // 后端操作合约的私钥
-	StartBlock         uint64        // 起始块号
-	PollInterval       time.Duration // 区块轮询间隔
+	ChainID            uint64           // 链ID，0表示启动时从节点获取（配置后会与节点返回的链ID核对）
+	Name               string           // 链名称（日志、节点组名称），为空时使用链ID
+	RPCEndpoint        string           // 区块链节点RPC地址（未配置RPCEndpoints时使用）
+	WSRpcEndpoint      string           // 区块链节点WebSocket RPC地址（未配置WSRpcEndpoints时使用）
+	RPCEndpoints       []EndpointConfig // 多个HTTP RPC节点，按权重与健康状况分配请求
+	WSRpcEndpoints     []EndpointConfig // 多个WebSocket RPC节点，订阅断开时自动切换
+	RPCPool            RPCPoolConfig    // 多节点健康检查与故障切换配置
+	MetadataGuard      GuardConfig      // NFT元数据网关（按域名）的限流与熔断
+	ContractAddr       string           // 已部署的拍卖合约地址（当前部署，新拍卖在此创建）
+	ERC721ContractAddr string           // 已部署的ERC721合约地址
+	Signer             SignerConfig     // 后端操作合约的签名器（不在配置中保存明文私钥）
+	StartBlock         uint64           // 起始块号
+	PollInterval       time.Duration    // 区块轮询间隔
+
+	AuctionABIVersions []ABIVersionConfig      // 拍卖合约各实现版本的ABI（可升级合约）
+	AuctionContracts   []AuctionContractConfig // 仍需索引与结算的其他拍卖合约部署（如重新部署前的旧合约）
+	ERC721Artifact     string                  // ERC721合约编译产物路径（为空时使用内置ABI）
+	LogArchive         string                  // 监听器收到的原始日志归档（JSONL，追加写入），为空时不记录
+
+	TxManager TxManagerConfig // 后端发送交易的管理配置
+	Keeper    KeeperConfig    // 到期拍卖结算守护配置
+	Monitor   MonitorConfig   // 后端签名地址（热钱包）监控配置
+	Admin     AdminConfig     // 合约管理操作审批配置
+	Backfill  BackfillConfig  // 历史区块回填配置
+
+	HeaderCache HeaderCacheConfig // 区块头缓存与链重组检测配置
+}
+
+// HeaderCacheConfig 区块头缓存配置
+type HeaderCacheConfig struct {
+	Size          int           // 进程内LRU缓存的区块数
+	RedisTTL      time.Duration // Redis中区块头的过期时间
+	MaxReorgDepth uint64        // 检测到重组时向前回溯比对的最大区块数
+	TrackInterval time.Duration // 轮询最新区块头检测重组的间隔，0表示只在监听到日志时检测
+}
+
+// BackfillConfig 历史区块回填配置
+type BackfillConfig struct {
+	Enabled         bool   // 是否在启动监听前回填历史区块
+	Workers         int    // 并发拉取日志的协程数
+	RangeSize       uint64 // 每次eth_getLogs查询的区块数（节点返回结果过多时自动缩小）
+	MinRangeSize    uint64 // 自动缩小查询范围的下限
+	HeaderBatchSize int    // 批量查询区块头时每个JSON-RPC批次的请求数
+	Confirmations   uint64 // 只回填到最新区块减去该确认数，之后的区块交给实时监听
+}
+
+// EndpointConfig RPC节点配置
+type EndpointConfig struct {
+	URL    string // 节点地址
+	Weight int    // 负载均衡权重，0按1处理
+}
+
+// RPCPoolConfig 多节点健康检查配置
+type RPCPoolConfig struct {
+	HealthInterval time.Duration // 健康检查间隔
+	CheckTimeout   time.Duration // 单次健康检查超时
+	MaxHeadLag     uint64        // 最新区块落后于最高节点超过该数量视为不健康
+	MaxErrorRate   float64       // 一个检查周期内请求错误率超过该值视为不健康（0~1）
+	MinRequests    int           // 计算错误率所需的最少请求数
+	MaxLatency     time.Duration // 平均延迟超过该值视为不健康，0表示不检查
+	Guard          GuardConfig   // 每个节点的限流与熔断
+}
+
+// GuardConfig 上游依赖的令牌桶限流与熔断配置
+type GuardConfig struct {
+	RatePerSecond    float64       // 每秒补充的令牌数，0表示不限流
+	Burst            int           // 令牌桶容量
+	MaxWait          time.Duration // 等待令牌的最长时间，超过则直接拒绝
+	FailureThreshold int           // 连续失败达到该次数时熔断，0表示不熔断
+	OpenTimeout      time.Duration // 熔断持续时间，之后进入半开状态试探
+	HalfOpenRequests int           // 半开状态允许的试探请求数
+}
+
+// Label 链在日志与节点组名称中的标识
+func (c *BlockchainConfig) Label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	if c.ChainID != 0 {
+		return strconv.FormatUint(c.ChainID, 10)
+	}
+	return "default"
+}
+
+// PoolName 该链某类节点组（http/ws）的名称，单链且未命名时保持原名称
+func (c *BlockchainConfig) PoolName(kind string) string {
+	if c.Name == "" && c.ChainID == 0 {
+		return kind
+	}
+	return c.Label() + "/" + kind
+}
+
+// HTTPEndpoints HTTP RPC节点列表，未配置RPCEndpoints时使用RPCEndpoint
+func (c *BlockchainConfig) HTTPEndpoints() []EndpointConfig {
+	if len(c.RPCEndpoints) > 0 {
+		return c.RPCEndpoints
+	}
+	return []EndpointConfig{{URL: c.RPCEndpoint, Weight: 1}}
+}
+
+// WSEndpoints WebSocket RPC节点列表，未配置WSRpcEndpoints时使用WSRpcEndpoint
+func (c *BlockchainConfig) WSEndpoints() []EndpointConfig {
+	if len(c.WSRpcEndpoints) > 0 {
+		return c.WSRpcEndpoints
+	}
+	return []EndpointConfig{{URL: c.WSRpcEndpoint, Weight: 1}}
+}
+
+// AuctionDeployments 需要索引的全部拍卖合约部署，第一个为当前部署（ContractAddr/StartBlock/AuctionABIVersions）
+func (c *BlockchainConfig) AuctionDeployments() []AuctionContractConfig {
+	deployments := make([]AuctionContractConfig, 0, 1+len(c.AuctionContracts))
+	deployments = append(deployments, AuctionContractConfig{
+		Address:     c.ContractAddr,
+		StartBlock:  c.StartBlock,
+		ABIVersions: c.AuctionABIVersions,
+	})
+	return append(deployments, c.AuctionContracts...)
+}
+
+// AdminConfig 合约管理操作审批配置
+type AdminConfig struct {
+	Addresses         []string      // 有权提议/审批的管理员地址
+	RequiredApprovals int           // 提交前所需审批人数（含提议人）
+	SignatureTTL      time.Duration // 管理员签名有效期
+	FeedMaxStaleness  time.Duration // 预言机报价最长未更新时间
+}
+
+// MonitorConfig 热钱包监控配置
+type MonitorConfig struct {
+	Enabled       bool          // 是否启用
+	Interval      time.Duration // 轮询间隔
+	MinBalanceETH float64       // 余额告警阈值（ETH）
+	MaxPendingAge time.Duration // 交易待上链超过该时长告警
+	MaxNonceGap   uint64        // 待上链nonce数量超过该值告警，0表示不检查
+	AlertInterval time.Duration // 同一告警的最小重复间隔
+	WebhookURL    string        // 告警Webhook地址，为空时仅写日志
+}
+
+// KeeperConfig 到期拍卖结算守护配置
+type KeeperConfig struct {
+	Enabled     bool          // 是否启用
+	Interval    time.Duration // 扫描间隔
+	BatchSize   int           // 每次扫描的拍卖数量
+	MaxInflight int           // 同时等待上链的endAuction交易上限
+}
+
+// SignerConfig 签名器配置
+type SignerConfig struct {
+	Type           string // keystore / dev / remote
+	KeystorePath   string // keystore文件路径
+	PassphraseEnv  string // keystore口令所在环境变量
+	PassphraseFile string // keystore口令文件（环境变量未设置时读取）
+	DevKeyEnv      string // 开发私钥所在环境变量（仅开发环境）
+	RemoteURL      string // 远程签名服务地址
+	RemoteTokenEnv string // 远程签名服务访问令牌所在环境变量
+}
+
+// TxManagerConfig 交易管理配置
+type TxManagerConfig struct {
+	ReceiptTimeout time.Duration // 等待回执超时时间
+	PollInterval   time.Duration // 回执轮询间隔
+	BumpInterval   time.Duration // 交易未上链多久后加价替换
+	BumpPercent    int64         // 每次加价百分比（节点要求至少10%）
+	MaxBumps       int           // 最大加价次数
+	MaxFeeCapGwei  int64         // 燃气费上限（gwei），0表示不限制
+}
+
+// AuctionContractConfig 拍卖合约的一个部署（合约地址不同，拍卖ID各自从0开始）
+type AuctionContractConfig struct {
+	Address     string             // 合约地址
+	StartBlock  uint64             // 起始块号
+	ABIVersions []ABIVersionConfig // 该部署各实现版本的ABI
+}
+
+// ABIVersionConfig 合约实现版本的ABI配置
+type ABIVersionConfig struct {
+	Implementation string // 实现合约地址
+	Artifact       string // Hardhat/Foundry编译产物JSON路径
+	FromBlock      uint64 // 该实现合约生效的起始区块
 }
 
 // LoadConfig 加载配置
@@ -74,12 +260,99 @@ func LoadConfig() *Config {
 	viper.SetDefault("mysql.maxOpenConns", 100)
 	viper.SetDefault("mysql.maxIdleConns", 20)
 	viper.SetDefault("mysql.connMaxLifetime", 30*time.Minute)
+	viper.SetDefault("blockchain.txManager.receiptTimeout", 5*time.Minute)
+	viper.SetDefault("blockchain.txManager.pollInterval", 2*time.Second)
+	viper.SetDefault("blockchain.txManager.bumpInterval", 45*time.Second)
+	viper.SetDefault("blockchain.txManager.bumpPercent", 15)
+	viper.SetDefault("blockchain.txManager.maxBumps", 5)
+	viper.SetDefault("blockchain.monitor.interval", time.Minute)
+	viper.SetDefault("blockchain.monitor.maxPendingAge", 10*time.Minute)
+	viper.SetDefault("blockchain.monitor.alertInterval", 30*time.Minute)
+	viper.SetDefault("blockchain.admin.requiredApprovals", 2)
+	viper.SetDefault("blockchain.admin.signatureTTL", 10*time.Minute)
+	viper.SetDefault("blockchain.admin.feedMaxStaleness", 24*time.Hour)
+	viper.SetDefault("blockchain.backfill.workers", 4)
+	viper.SetDefault("blockchain.backfill.rangeSize", 2000)
+	viper.SetDefault("blockchain.backfill.minRangeSize", 10)
+	viper.SetDefault("blockchain.backfill.headerBatchSize", 100)
+	viper.SetDefault("blockchain.backfill.confirmations", 12)
+	viper.SetDefault("blockchain.headerCache.size", 4096)
+	viper.SetDefault("blockchain.headerCache.redisTTL", 24*time.Hour)
+	viper.SetDefault("blockchain.headerCache.maxReorgDepth", 64)
+	viper.SetDefault("blockchain.headerCache.trackInterval", 12*time.Second)
+	viper.SetDefault("blockchain.rpcPool.healthInterval", 15*time.Second)
+	viper.SetDefault("blockchain.rpcPool.checkTimeout", 5*time.Second)
+	viper.SetDefault("blockchain.rpcPool.maxHeadLag", 5)
+	viper.SetDefault("blockchain.rpcPool.maxErrorRate", 0.5)
+	viper.SetDefault("blockchain.rpcPool.minRequests", 10)
+	viper.SetDefault("blockchain.rpcPool.maxLatency", 3*time.Second)
+	viper.SetDefault("blockchain.rpcPool.guard.ratePerSecond", 25)
+	viper.SetDefault("blockchain.rpcPool.guard.burst", 50)
+	viper.SetDefault("blockchain.rpcPool.guard.maxWait", 2*time.Second)
+	viper.SetDefault("blockchain.rpcPool.guard.failureThreshold", 5)
+	viper.SetDefault("blockchain.rpcPool.guard.openTimeout", 30*time.Second)
+	viper.SetDefault("blockchain.rpcPool.guard.halfOpenRequests", 1)
+	viper.SetDefault("blockchain.metadataGuard.ratePerSecond", 5)
+	viper.SetDefault("blockchain.metadataGuard.burst", 10)
+	viper.SetDefault("blockchain.metadataGuard.maxWait", 2*time.Second)
+	viper.SetDefault("blockchain.metadataGuard.failureThreshold", 5)
+	viper.SetDefault("blockchain.metadataGuard.openTimeout", time.Minute)
+	viper.SetDefault("blockchain.metadataGuard.halfOpenRequests", 1)
 
 	var cfg Config
 	if err := viper.Unmarshal(&cfg); err != nil {
 		log.Fatal().Err(err).Msg("配置解析失败")
 		os.Exit(1)
 	}
+	chains, err := loadChains()
+	if err != nil {
+		log.Fatal().Err(err).Msg("多链配置解析失败")
+		os.Exit(1)
+	}
+	cfg.Chains = chains
+	if cfg.MySQL.LegacyChainID == 0 {
+		cfg.MySQL.LegacyChainID = cfg.Blockchain.ChainID
+	}
+	if cfg.MySQL.LegacyContract == "" {
+		cfg.MySQL.LegacyContract = cfg.Blockchain.ContractAddr
+	}
 
 	return &cfg
 }
+
+// loadChains 解析chains列表：每条链以blockchain（含默认值）为基础，覆盖该链单独配置的字段
+func loadChains() ([]BlockchainConfig, error) {
+	items, ok := viper.Get("chains").([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, nil
+	}
+	base, _ := viper.AllSettings()["blockchain"].(map[string]interface{})
+
+	chains := make([]BlockchainConfig, 0, len(items))
+	seen := make(map[uint64]bool, len(items))
+	for i, item := range items {
+		override, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("chains[%d]格式错误", i)
+		}
+		v := viper.New()
+		if err := v.MergeConfigMap(base); err != nil {
+			return nil, err
+		}
+		if err := v.MergeConfigMap(override); err != nil {
+			return nil, err
+		}
+		var chain BlockchainConfig
+		if err := v.Unmarshal(&chain); err != nil {
+			return nil, fmt.Errorf("chains[%d]: %w", i, err)
+		}
+		if chain.ChainID != 0 {
+			if seen[chain.ChainID] {
+				return nil, fmt.Errorf("chains[%d]: 链ID %d重复", i, chain.ChainID)
+			}
+			seen[chain.ChainID] = true
+		}
+		chains = append(chains, chain)
+	}
+	return chains, nil
+}
@@ -0,0 +1,183 @@
+package handles
+
+import (
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/service"
+	"github.com/ydh2333/NFTAuction-project/utils"
+)
+
+type AdminOperationHandler struct {
+	adminOperationService service.AdminOperationService
+}
+
+func NewAdminOperationHandler() *AdminOperationHandler {
+	return &AdminOperationHandler{
+		adminOperationService: service.NewAdminOperationService(),
+	}
+}
+
+// adminSignatureRequest 管理员签名，消息格式见service.AdminMessage
+type adminSignatureRequest struct {
+	Admin     string `json:"admin" binding:"required"`
+	IssuedAt  int64  `json:"issued_at" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+type proposeOperationRequest struct {
+	adminSignatureRequest
+	ChainID uint64                      `json:"chain_id"` // 为空表示默认链
+	Type    models.AdminOperationType   `json:"type" binding:"required"`
+	Params  models.AdminOperationParams `json:"params"`
+}
+
+type rejectOperationRequest struct {
+	adminSignatureRequest
+	Reason string `json:"reason"`
+}
+
+// toSignature 解析管理员签名
+func (r adminSignatureRequest) toSignature() (service.AdminSignature, bool) {
+	admin, ok := parseAddress(r.Admin)
+	if !ok {
+		return service.AdminSignature{}, false
+	}
+	sig, err := hexutil.Decode(r.Signature)
+	if err != nil {
+		return service.AdminSignature{}, false
+	}
+	return service.AdminSignature{Admin: admin, IssuedAt: r.IssuedAt, Signature: sig}, true
+}
+
+// ProposeOperation 提议setPriceETHFeed或upgradeToAndCall操作
+func (h *AdminOperationHandler) ProposeOperation(c *gin.Context) {
+	var req proposeOperationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, 400, "参数错误")
+		return
+	}
+	sig, ok := req.toSignature()
+	if !ok {
+		utils.SendError(c, 400, "签名参数格式错误")
+		return
+	}
+
+	op, err := h.adminOperationService.Propose(c.Request.Context(), req.ChainID, req.Type, req.Params, sig)
+	if err != nil {
+		sendServiceError(c, err, "提议管理操作失败")
+		return
+	}
+	utils.SendSuccess(c, "提议管理操作成功", op)
+}
+
+// ApproveOperation 审批管理操作
+func (h *AdminOperationHandler) ApproveOperation(c *gin.Context) {
+	id, sig, ok := h.bindOperation(c, &adminSignatureRequest{})
+	if !ok {
+		return
+	}
+	op, err := h.adminOperationService.Approve(id, sig)
+	if err != nil {
+		sendServiceError(c, err, "审批管理操作失败")
+		return
+	}
+	utils.SendSuccess(c, "审批管理操作成功", op)
+}
+
+// RejectOperation 驳回管理操作
+func (h *AdminOperationHandler) RejectOperation(c *gin.Context) {
+	var req rejectOperationRequest
+	id, sig, ok := h.bindOperation(c, &req)
+	if !ok {
+		return
+	}
+	op, err := h.adminOperationService.Reject(id, req.Reason, sig)
+	if err != nil {
+		sendServiceError(c, err, "驳回管理操作失败")
+		return
+	}
+	utils.SendSuccess(c, "驳回管理操作成功", op)
+}
+
+// SubmitOperation 提交已审批的管理操作上链
+func (h *AdminOperationHandler) SubmitOperation(c *gin.Context) {
+	id, sig, ok := h.bindOperation(c, &adminSignatureRequest{})
+	if !ok {
+		return
+	}
+	op, err := h.adminOperationService.Submit(c.Request.Context(), id, sig)
+	if err != nil {
+		sendServiceError(c, err, "提交管理操作失败")
+		return
+	}
+	utils.SendSuccess(c, "提交管理操作成功", op)
+}
+
+// GetOperation 查询管理操作及审计记录
+func (h *AdminOperationHandler) GetOperation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendError(c, 400, "操作ID格式错误")
+		return
+	}
+	op, err := h.adminOperationService.GetOperation(uint(id))
+	if err != nil {
+		sendServiceError(c, err, "获取管理操作失败")
+		return
+	}
+	utils.SendSuccess(c, "获取管理操作成功", op)
+}
+
+// ListOperations 查询管理操作列表，参数：chain_id、status、page、size
+func (h *AdminOperationHandler) ListOperations(c *gin.Context) {
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	ops, total, err := h.adminOperationService.ListOperations(chainID, models.AdminOperationStatus(c.Query("status")), utils.PageParams{Page: page, Size: size})
+	if err != nil {
+		utils.SendError(c, 500, "获取管理操作列表失败")
+		return
+	}
+	utils.SendSuccess(c, "获取管理操作列表成功", gin.H{
+		"list":  ops,
+		"total": total,
+	})
+}
+
+// signedRequest 包含管理员签名的请求
+type signedRequest interface {
+	toSignature() (service.AdminSignature, bool)
+}
+
+// bindOperation 解析路径中的操作ID与请求体中的管理员签名
+func (h *AdminOperationHandler) bindOperation(c *gin.Context, req signedRequest) (uint, service.AdminSignature, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendError(c, 400, "操作ID格式错误")
+		return 0, service.AdminSignature{}, false
+	}
+	if err := c.ShouldBindJSON(req); err != nil {
+		utils.SendError(c, 400, "参数错误")
+		return 0, service.AdminSignature{}, false
+	}
+	sig, ok := req.toSignature()
+	if !ok {
+		utils.SendError(c, 400, "签名参数格式错误")
+		return 0, service.AdminSignature{}, false
+	}
+	return uint(id), sig, true
+}
@@ -18,12 +18,52 @@ func NewAuctionDetailHandler() *AuctionDetailHandler {
 	}
 }
 
+// GetAuctionDetail 拍卖出价记录，参数：chain_id（可选，为空时为默认链）、contract_address（可选，为空时为该链当前部署的拍卖合约）
 func (a *AuctionDetailHandler) GetAuctionDetail(c *gin.Context) {
 	auctionId, _ := strconv.Atoi(c.Param("id"))
-	bids, err := a.auctiondetailService.GetAuctionDetail(uint(auctionId))
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
+	contract, ok := parseOptionalAddress(c.Query("contract_address"))
+	if !ok {
+		utils.SendError(c, 400, "拍卖合约地址格式错误")
+		return
+	}
+	bids, err := a.auctiondetailService.GetAuctionDetail(chainID, contract, uint64(auctionId))
 	if err != nil {
 		utils.SendError(c, 500, "获取拍卖详情失败")
 		return
 	}
 	utils.SendSuccess(c, "获取拍卖详情成功", bids)
 }
+
+// GetAuctionResult 拍卖成交结果（获胜者、成交价、美元价值、结算交易与NFT转移确认），参数同GetAuctionDetail
+func (a *AuctionDetailHandler) GetAuctionResult(c *gin.Context) {
+	auctionId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendError(c, 400, "拍卖ID格式错误")
+		return
+	}
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
+	contract, ok := parseOptionalAddress(c.Query("contract_address"))
+	if !ok {
+		utils.SendError(c, 400, "拍卖合约地址格式错误")
+		return
+	}
+	result, err := a.auctiondetailService.GetAuctionResult(chainID, contract, auctionId)
+	if err != nil {
+		utils.SendError(c, 500, "获取拍卖成交结果失败")
+		return
+	}
+	if result == nil {
+		utils.SendError(c, 404, "拍卖未成交")
+		return
+	}
+	utils.SendSuccess(c, "获取拍卖成交结果成功", result)
+}
@@ -0,0 +1,67 @@
+package handles
+
+import (
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/ydh2333/NFTAuction-project/internal/service"
+	"github.com/ydh2333/NFTAuction-project/utils"
+)
+
+type BidSimulateHandler struct {
+	bidSimulateService service.BidSimulateService
+}
+
+func NewBidSimulateHandler() *BidSimulateHandler {
+	return &BidSimulateHandler{
+		bidSimulateService: service.NewBidSimulateService(),
+	}
+}
+
+type bidSimulateRequest struct {
+	ChainID      uint64 `json:"chain_id"`         // 为空表示默认链
+	Contract     string `json:"contract_address"` // 拍卖合约地址，为空表示该链当前部署
+	Bidder       string `json:"bidder" binding:"required"`
+	Amount       string `json:"amount" binding:"required"` // 十进制字符串
+	TokenAddress string `json:"token_address"`             // 为空表示ETH
+}
+
+// SimulateBid 出价预检：以出价者身份eth_call执行placeBid，返回回滚原因与最低可接受出价
+func (h *BidSimulateHandler) SimulateBid(c *gin.Context) {
+	auctionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendError(c, 400, "拍卖ID格式错误")
+		return
+	}
+	var req bidSimulateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, 400, "参数错误")
+		return
+	}
+	bidder, ok1 := parseAddress(req.Bidder)
+	amount, ok2 := parseUint256(req.Amount)
+	tokenAddress, ok3 := common.Address{}, true
+	if req.TokenAddress != "" {
+		tokenAddress, ok3 = parseAddress(req.TokenAddress)
+	}
+	contract, ok4 := parseOptionalAddress(req.Contract)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		utils.SendError(c, 400, "参数格式错误")
+		return
+	}
+
+	result, err := h.bidSimulateService.SimulateBid(c.Request.Context(), service.BidSimulateParams{
+		ChainID:      req.ChainID,
+		Contract:     contract,
+		AuctionID:    auctionID,
+		Bidder:       bidder,
+		Amount:       amount,
+		TokenAddress: tokenAddress,
+	})
+	if err != nil {
+		sendServiceError(c, err, "模拟出价失败")
+		return
+	}
+	utils.SendSuccess(c, "模拟出价成功", result)
+}
@@ -0,0 +1,57 @@
+package handles
+
+import (
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/ydh2333/NFTAuction-project/internal/service"
+	"github.com/ydh2333/NFTAuction-project/utils"
+)
+
+type ContractHistoryHandler struct {
+	contractHistoryService service.ContractHistoryService
+}
+
+func NewContractHistoryHandler() *ContractHistoryHandler {
+	return &ContractHistoryHandler{
+		contractHistoryService: service.NewContractHistoryService(),
+	}
+}
+
+// GetContractHistory 查询拍卖合约升级/初始化历史
+// 参数：chain_id（可选）、contract（可选，代理合约地址）、page、size
+func (h *ContractHistoryHandler) GetContractHistory(c *gin.Context) {
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
+	contractAddress := c.Query("contract")
+	if contractAddress != "" {
+		if !common.IsHexAddress(contractAddress) {
+			utils.SendError(c, 400, "合约地址格式错误")
+			return
+		}
+		contractAddress = common.HexToAddress(contractAddress).Hex()
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	histories, total, err := h.contractHistoryService.GetContractHistory(chainID, contractAddress, utils.PageParams{Page: page, Size: size})
+	if err != nil {
+		utils.SendError(c, 500, "获取合约历史失败")
+		return
+	}
+	utils.SendSuccess(c, "获取合约历史成功", gin.H{
+		"list":  histories,
+		"total": total,
+	})
+}
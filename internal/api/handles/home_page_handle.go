@@ -17,9 +17,15 @@ func NewHomePageHandler() *HomePageHandler {
 	}
 }
 
+// PlatformStatistics 平台统计，参数：chain_id（可选，为空时统计全部链）
 func (h *HomePageHandler) PlatformStatistics(c *gin.Context) {
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
 
-	auctionCount, bidCount := h.homePageService.PlatformStatistics()
+	auctionCount, bidCount := h.homePageService.PlatformStatistics(chainID)
 
 	utils.SendSuccess(c, "统计数据获取成功", gin.H{
 		"auctionCount": auctionCount,
@@ -51,8 +57,14 @@ func (h *HomePageHandler) SearchAuctionsList(c *gin.Context) {
 	utils.SendSuccess(c, "搜索拍卖成功", AuctionDetails)
 }
 
+// GetTop5HotAuctions 热门拍卖，参数：chain_id（可选，为空时在全部链中排行）
 func (h *HomePageHandler) GetTop5HotAuctions(c *gin.Context) {
-	auctionDetails, err := h.homePageService.GetTop5HotAuctions()
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
+	auctionDetails, err := h.homePageService.GetTop5HotAuctions(chainID)
 	if err != nil {
 		utils.SendError(c, 500, "获取热门拍卖失败")
 		return
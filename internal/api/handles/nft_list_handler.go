@@ -1,6 +1,7 @@
 package handles
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	"github.com/ydh2333/NFTAuction-project/internal/service"
 	"github.com/ydh2333/NFTAuction-project/utils"
@@ -18,10 +19,51 @@ func NewNFTListHandler() *NFTListHandler {
 
 func (a *NFTListHandler) GetNFTList(c *gin.Context) {
 	address := c.Param("address")
-	nftList, err := a.nftListService.GetNFTList(address)
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
+	nftList, err := a.nftListService.GetNFTList(chainID, address)
 	if err != nil {
 		utils.SendError(c, 500, "获取NFT列表失败")
 		return
 	}
 	utils.SendSuccess(c, "获取NFT列表成功", nftList)
 }
+
+// GetCancelledAuctions 用户创建的已取消拍卖（不出现在NFT列表的拍卖状态中），参数：chain_id（可选，为空时查询全部链）
+func (a *NFTListHandler) GetCancelledAuctions(c *gin.Context) {
+	address := c.Param("address")
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
+	auctions, err := a.nftListService.GetCancelledAuctions(chainID, address)
+	if err != nil {
+		utils.SendError(c, 500, "获取已取消拍卖失败")
+		return
+	}
+	utils.SendSuccess(c, "获取已取消拍卖成功", auctions)
+}
+
+// GetAuctionResults 用户卖出或拍得的成交结果（含获胜者、成交价、美元价值与NFT转移确认），参数：chain_id（可选，为空时查询全部链）
+func (a *NFTListHandler) GetAuctionResults(c *gin.Context) {
+	address := c.Param("address")
+	if !common.IsHexAddress(address) {
+		utils.SendError(c, 400, "地址格式错误")
+		return
+	}
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
+	results, err := a.nftListService.GetAuctionResults(chainID, common.HexToAddress(address).Hex())
+	if err != nil {
+		utils.SendError(c, 500, "获取成交记录失败")
+		return
+	}
+	utils.SendSuccess(c, "获取成交记录成功", results)
+}
@@ -0,0 +1,48 @@
+package handles
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydh2333/NFTAuction-project/internal/service"
+	"github.com/ydh2333/NFTAuction-project/utils"
+)
+
+type OpsHandler struct {
+	opsService service.OpsService
+}
+
+func NewOpsHandler() *OpsHandler {
+	return &OpsHandler{
+		opsService: service.NewOpsService(),
+	}
+}
+
+// GetWalletStatus 查询后端签名地址余额、nonce差值与待上链交易，参数：chain_id（可选，默认链）
+func (h *OpsHandler) GetWalletStatus(c *gin.Context) {
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
+	status, err := h.opsService.GetWalletStatus(chainID)
+	if err != nil {
+		if errors.Is(err, service.ErrMonitorDisabled) {
+			utils.SendError(c, 503, err.Error())
+			return
+		}
+		utils.SendError(c, 500, "获取热钱包状态失败")
+		return
+	}
+	utils.SendSuccess(c, "获取热钱包状态成功", status)
+}
+
+// GetRPCStats 查询各RPC节点的健康状态、最新区块、延迟与错误统计
+func (h *OpsHandler) GetRPCStats(c *gin.Context) {
+	utils.SendSuccess(c, "获取RPC节点状态成功", h.opsService.GetRPCStats())
+}
+
+// GetUpstreams 查询各上游的熔断状态（closed/open/half_open）、令牌余量与拒绝次数
+func (h *OpsHandler) GetUpstreams(c *gin.Context) {
+	utils.SendSuccess(c, "获取上游状态成功", h.opsService.GetUpstreams())
+}
@@ -0,0 +1,109 @@
+package handles
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/internal/service"
+	"github.com/ydh2333/NFTAuction-project/utils"
+	"gorm.io/gorm"
+)
+
+type TransactionHandler struct {
+	transactionService service.TransactionService
+}
+
+func NewTransactionHandler() *TransactionHandler {
+	return &TransactionHandler{
+		transactionService: service.NewTransactionService(),
+	}
+}
+
+// GetTransaction 根据哈希查询后端发送的交易（支持被替换的历史哈希）
+func (h *TransactionHandler) GetTransaction(c *gin.Context) {
+	raw, err := hexutil.Decode(c.Param("hash"))
+	if err != nil || len(raw) != common.HashLength {
+		utils.SendError(c, 400, "交易哈希格式错误")
+		return
+	}
+
+	tx, err := h.transactionService.GetTransaction(common.BytesToHash(raw).Hex())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.SendError(c, 404, "交易不存在")
+			return
+		}
+		utils.SendError(c, 500, "获取交易失败")
+		return
+	}
+	utils.SendSuccess(c, "获取交易成功", tx)
+}
+
+// ListTransactions 查询后端发送的交易列表
+// 参数：chain_id、purpose、auction_id、status、from、to（目标合约）、since、until（RFC3339）、page、size
+func (h *TransactionHandler) ListTransactions(c *gin.Context) {
+	chainID, ok := parseChainID(c)
+	if !ok {
+		utils.SendError(c, 400, "链ID格式错误")
+		return
+	}
+	filter := repository.TransactionFilter{
+		ChainID: chainID,
+		Purpose: c.Query("purpose"),
+		Status:  models.TransactionStatus(c.Query("status")),
+	}
+	if v := c.Query("auction_id"); v != "" {
+		auctionID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			utils.SendError(c, 400, "拍卖ID格式错误")
+			return
+		}
+		filter.AuctionID = auctionID
+	}
+	if v := c.Query("from"); v != "" {
+		if !common.IsHexAddress(v) {
+			utils.SendError(c, 400, "发送地址格式错误")
+			return
+		}
+		filter.FromAddress = common.HexToAddress(v).Hex()
+	}
+	if v := c.Query("to"); v != "" {
+		if !common.IsHexAddress(v) {
+			utils.SendError(c, 400, "目标合约地址格式错误")
+			return
+		}
+		filter.ToAddress = common.HexToAddress(v).Hex()
+	}
+	for key, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := c.Query(key); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				utils.SendError(c, 400, key+"时间格式错误")
+				return
+			}
+			*target = t
+		}
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	result, err := h.transactionService.ListTransactions(filter, utils.PageParams{Page: page, Size: size})
+	if err != nil {
+		utils.SendError(c, 500, "获取交易列表失败")
+		return
+	}
+	utils.SendSuccess(c, "获取交易列表成功", result)
+}
@@ -0,0 +1,184 @@
+package handles
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/ydh2333/NFTAuction-project/internal/service"
+	"github.com/ydh2333/NFTAuction-project/utils"
+	"github.com/ydh2333/NFTAuction-project/utils/logger"
+)
+
+type TxBuilderHandler struct {
+	txBuilderService service.TxBuilderService
+}
+
+func NewTxBuilderHandler() *TxBuilderHandler {
+	return &TxBuilderHandler{
+		txBuilderService: service.NewTxBuilderService(),
+	}
+}
+
+// 金额与ID均为十进制字符串，避免uint256精度丢失
+type createAuctionTxRequest struct {
+	ChainID     uint64 `json:"chain_id"` // 为空表示默认链
+	Seller      string `json:"seller" binding:"required"`
+	Duration    string `json:"duration" binding:"required"` // 秒
+	StartPrice  string `json:"start_price" binding:"required"`
+	NFTContract string `json:"nft_contract" binding:"required"`
+	NFTID       string `json:"nft_id" binding:"required"`
+}
+
+type placeBidTxRequest struct {
+	ChainID      uint64 `json:"chain_id"`         // 为空表示默认链
+	Contract     string `json:"contract_address"` // 拍卖合约地址，为空表示该链当前部署
+	Bidder       string `json:"bidder" binding:"required"`
+	AuctionID    uint64 `json:"auction_id" binding:"required"`
+	Amount       string `json:"amount" binding:"required"`
+	TokenAddress string `json:"token_address"` // 为空表示ETH
+}
+
+type endAuctionTxRequest struct {
+	ChainID   uint64 `json:"chain_id"`         // 为空表示默认链
+	Contract  string `json:"contract_address"` // 拍卖合约地址，为空表示该链当前部署
+	From      string `json:"from" binding:"required"`
+	AuctionID uint64 `json:"auction_id" binding:"required"`
+}
+
+// BuildCreateAuction 构造待签名的createAuction交易
+func (h *TxBuilderHandler) BuildCreateAuction(c *gin.Context) {
+	var req createAuctionTxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, 400, "参数错误")
+		return
+	}
+	seller, ok1 := parseAddress(req.Seller)
+	nftContract, ok2 := parseAddress(req.NFTContract)
+	duration, ok3 := parseUint256(req.Duration)
+	startPrice, ok4 := parseUint256(req.StartPrice)
+	nftID, ok5 := parseUint256(req.NFTID)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 {
+		utils.SendError(c, 400, "参数格式错误")
+		return
+	}
+
+	bundle, err := h.txBuilderService.BuildCreateAuction(c.Request.Context(), service.CreateAuctionTxParams{
+		ChainID:     req.ChainID,
+		Seller:      seller,
+		Duration:    duration,
+		StartPrice:  startPrice,
+		NFTContract: nftContract,
+		NFTID:       nftID,
+	})
+	sendTxBundle(c, bundle, err)
+}
+
+// BuildPlaceBid 构造待签名的placeBid交易（含ERC20授权前置交易）
+func (h *TxBuilderHandler) BuildPlaceBid(c *gin.Context) {
+	var req placeBidTxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, 400, "参数错误")
+		return
+	}
+	bidder, ok1 := parseAddress(req.Bidder)
+	amount, ok2 := parseUint256(req.Amount)
+	tokenAddress, ok3 := common.Address{}, true
+	if req.TokenAddress != "" {
+		tokenAddress, ok3 = parseAddress(req.TokenAddress)
+	}
+	contract, ok4 := parseOptionalAddress(req.Contract)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		utils.SendError(c, 400, "参数格式错误")
+		return
+	}
+
+	bundle, err := h.txBuilderService.BuildPlaceBid(c.Request.Context(), service.PlaceBidTxParams{
+		ChainID:      req.ChainID,
+		Contract:     contract,
+		Bidder:       bidder,
+		AuctionID:    req.AuctionID,
+		Amount:       amount,
+		TokenAddress: tokenAddress,
+	})
+	sendTxBundle(c, bundle, err)
+}
+
+// BuildEndAuction 构造待签名的endAuction交易
+func (h *TxBuilderHandler) BuildEndAuction(c *gin.Context) {
+	var req endAuctionTxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, 400, "参数错误")
+		return
+	}
+	from, ok1 := parseAddress(req.From)
+	contract, ok2 := parseOptionalAddress(req.Contract)
+	if !ok1 || !ok2 {
+		utils.SendError(c, 400, "参数格式错误")
+		return
+	}
+
+	bundle, err := h.txBuilderService.BuildEndAuction(c.Request.Context(), service.EndAuctionTxParams{
+		ChainID:   req.ChainID,
+		Contract:  contract,
+		From:      from,
+		AuctionID: req.AuctionID,
+	})
+	sendTxBundle(c, bundle, err)
+}
+
+// sendTxBundle 返回交易构造结果，校验失败返回400
+func sendTxBundle(c *gin.Context, bundle *service.TxBundle, err error) {
+	if err != nil {
+		sendServiceError(c, err, "构造交易失败")
+		return
+	}
+	utils.SendSuccess(c, "构造交易成功", bundle)
+}
+
+// sendServiceError 服务层参数校验错误（AppError.Code为400）原样返回，其余返回500
+func sendServiceError(c *gin.Context, err error, msg string) {
+	var appErr *logger.AppError
+	if errors.As(err, &appErr) && appErr.Code == 400 {
+		utils.SendError(c, 400, appErr.Error())
+		return
+	}
+	utils.SendError(c, 500, msg)
+}
+
+// parseAddress 解析十六进制地址
+func parseAddress(s string) (common.Address, bool) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(s), true
+}
+
+// parseOptionalAddress 解析可选的十六进制地址，为空时返回零地址
+func parseOptionalAddress(s string) (common.Address, bool) {
+	if s == "" {
+		return common.Address{}, true
+	}
+	return parseAddress(s)
+}
+
+// parseChainID 解析查询参数chain_id，为空时返回0（全部链或默认链，由接口决定）
+func parseChainID(c *gin.Context) (uint64, bool) {
+	v := c.Query("chain_id")
+	if v == "" {
+		return 0, true
+	}
+	chainID, err := strconv.ParseUint(v, 10, 64)
+	return chainID, err == nil
+}
+
+// parseUint256 解析十进制uint256
+func parseUint256(s string) (*big.Int, bool) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() < 0 || n.BitLen() > 256 {
+		return nil, false
+	}
+	return n, true
+}
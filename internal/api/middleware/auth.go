@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/utils"
+)
+
+// TokenAuth 管理接口鉴权中间件：校验请求头 Authorization: Bearer <token>
+// 未配置token时拒绝全部请求，避免管理接口在漏配时对外开放
+func TokenAuth(token string) gin.HandlerFunc {
+	if token == "" {
+		log.Warn().Msg("未配置管理接口访问令牌，管理接口将拒绝全部请求")
+	}
+	return func(c *gin.Context) {
+		if token == "" {
+			utils.SendError(c, http.StatusServiceUnavailable, "管理接口未配置访问令牌")
+			return
+		}
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			utils.SendError(c, http.StatusUnauthorized, "未授权")
+			return
+		}
+		c.Next()
+	}
+}
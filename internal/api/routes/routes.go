@@ -6,8 +6,8 @@ import (
 	middlewares "github.com/ydh2333/NFTAuction-project/internal/api/middleware"
 )
 
-// InitRoutes 初始化路由
-func InitRoutes(r *gin.Engine) {
+// InitRoutes 初始化路由，adminToken为运维与合约管理接口的访问令牌
+func InitRoutes(r *gin.Engine, adminToken string) {
 	r.Use(middlewares.Logger()) // 全局中间件
 	r.Use(gin.Recovery())       // 异常恢复
 
@@ -27,14 +27,64 @@ func InitRoutes(r *gin.Engine) {
 		auctionDetail := api.Group("/auctionDetail")
 		{
 			auctionDetail.GET("/:id", auctionDetailHandler.GetAuctionDetail)
+			auctionDetail.GET("/:id/result", auctionDetailHandler.GetAuctionResult)
 		}
 		// 个人主页/NFT列表
 		nftListHandler := handles.NewNFTListHandler()
 		nftList := api.Group("/ownerPage")
 		{
 			nftList.GET("/nftList/:address", nftListHandler.GetNFTList)
+			nftList.GET("/cancelledAuctions/:address", nftListHandler.GetCancelledAuctions)
+			nftList.GET("/auctionResults/:address", nftListHandler.GetAuctionResults)
+		}
+		// 合约升级历史
+		contractHistoryHandler := handles.NewContractHistoryHandler()
+		contract := api.Group("/contract")
+		{
+			contract.GET("/history", contractHistoryHandler.GetContractHistory)
+		}
+		// 构造待钱包签名的交易
+		txBuilderHandler := handles.NewTxBuilderHandler()
+		txBuilder := api.Group("/txBuilder")
+		{
+			txBuilder.POST("/createAuction", txBuilderHandler.BuildCreateAuction)
+			txBuilder.POST("/placeBid", txBuilderHandler.BuildPlaceBid)
+			txBuilder.POST("/endAuction", txBuilderHandler.BuildEndAuction)
+		}
+		// 出价预检
+		bidSimulateHandler := handles.NewBidSimulateHandler()
+		auctions := api.Group("/auctions")
+		{
+			auctions.POST("/:id/bids/simulate", bidSimulateHandler.SimulateBid)
+		}
+		// 以下为运维与合约管理接口，需携带管理接口访问令牌
+		authorized := api.Group("", middlewares.TokenAuth(adminToken))
+		// 运维
+		opsHandler := handles.NewOpsHandler()
+		ops := authorized.Group("/ops")
+		{
+			ops.GET("/wallet", opsHandler.GetWalletStatus)
+			ops.GET("/rpc", opsHandler.GetRPCStats)
+			ops.GET("/upstreams", opsHandler.GetUpstreams)
+		}
+		// 后端发送的链上交易（含调用数据、nonce与费用）
+		transactionHandler := handles.NewTransactionHandler()
+		tx := authorized.Group("/tx")
+		{
+			tx.GET("", transactionHandler.ListTransactions)
+			tx.GET("/:hash", transactionHandler.GetTransaction)
+		}
+		// 合约管理操作（多管理员签名审批）
+		adminOperationHandler := handles.NewAdminOperationHandler()
+		admin := authorized.Group("/admin/operations")
+		{
+			admin.GET("", adminOperationHandler.ListOperations)
+			admin.POST("", adminOperationHandler.ProposeOperation)
+			admin.GET("/:id", adminOperationHandler.GetOperation)
+			admin.POST("/:id/approve", adminOperationHandler.ApproveOperation)
+			admin.POST("/:id/reject", adminOperationHandler.RejectOperation)
+			admin.POST("/:id/submit", adminOperationHandler.SubmitOperation)
 		}
-
 	}
 
 }
@@ -2,18 +2,22 @@ package ERC721
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog/log"
 	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/abiregistry"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/headercache"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/rpcpool"
 )
 
 // NFTMetadata NFT元数据结构体（适配主流ERC721元数据标准）
@@ -34,23 +38,42 @@ type NFTInfo struct {
 
 // ERC721Listener ERC721监听器
 type ERC721Listener struct {
-	client       *ethclient.Client // 以太坊RPC客户端
-	abi          abi.ABI           // 解析后的ERC721 ABI
-	contractAddr common.Address    // 监听的合约地址
-	zeroAddr     common.Address    // 零地址（过滤safeMint）
-	httpClient   *http.Client      // 解析元数据的HTTP客户端
+	chainID      uint64                  // 监听的链ID，写入NFT记录
+	client       chainclient.ChainClient // 链上客户端
+	headers      *headercache.Cache      // 区块头缓存（与同一客户端上的其他监听器共享）
+	abi          *abi.ABI                // 解析后的ERC721 ABI
+	caller       *contracts.ERC721Caller // 类型化合约调用
+	registry     *abiregistry.Registry   // 事件解析使用的ABI
+	contractAddr common.Address          // 监听的合约地址
+	zeroAddr     common.Address          // 零地址（过滤safeMint）
+	httpClient   *http.Client            // 解析元数据的HTTP客户端
+	guardCfg     config.GuardConfig      // 元数据网关的限流与熔断配置
+	resumeFrom   uint64                  // 订阅建立后先补齐该区块起的日志（回填结束区块+1），0表示不补齐
 }
 
 // NewERC721Listener 初始化监听器
 func NewERC721Listener(cfg *config.BlockchainConfig) (*ERC721Listener, error) {
-	// 1. 连接以太坊RPC节点（如Infura、Alchemy或自建节点）
-	client, err := ethclient.Dial(cfg.WSRpcEndpoint)
+	// 1. 连接以太坊RPC节点（如Infura、Alchemy或自建节点，可配置多个WebSocket节点）
+	client, err := rpcpool.Dial(cfg.PoolName("ws"), cfg.WSEndpoints(), cfg.RPCPool)
 	if err != nil {
 		return nil, err
 	}
+	return NewERC721ListenerWithClient(cfg, client)
+}
 
-	// 2. 解析ERC721 ABI
-	parsedABI, err := abi.JSON(strings.NewReader(ERC721ABI))
+// NewERC721ListenerWithClient 使用已建立的链上客户端初始化监听器（如chainclient.Fake、进程内模拟链）
+func NewERC721ListenerWithClient(cfg *config.BlockchainConfig, client chainclient.ChainClient) (*ERC721Listener, error) {
+	// 2. 解析ERC721 ABI（优先使用配置的编译产物），创建类型化绑定
+	defaultABI, err := contracts.ERC721MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	parsedABI, err := abiregistry.LoadABI(cfg.ERC721Artifact, defaultABI)
+	if err != nil {
+		return nil, err
+	}
+	contractAddr := common.HexToAddress(cfg.ERC721ContractAddr)
+	caller, err := contracts.NewERC721Caller(contractAddr, client)
 	if err != nil {
 		return nil, err
 	}
@@ -61,32 +84,67 @@ func NewERC721Listener(cfg *config.BlockchainConfig) (*ERC721Listener, error) {
 	}
 
 	return &ERC721Listener{
+		chainID:      cfg.ChainID,
 		client:       client,
+		headers:      headercache.For(client, cfg.ChainID, cfg.HeaderCache),
 		abi:          parsedABI,
-		contractAddr: common.HexToAddress(cfg.ERC721ContractAddr),
+		caller:       caller,
+		registry:     abiregistry.NewRegistry(parsedABI),
+		contractAddr: contractAddr,
 		zeroAddr:     common.HexToAddress("0x0000000000000000000000000000000000000000"),
 		httpClient:   httpClient,
+		guardCfg:     cfg.MetadataGuard,
 	}, nil
 }
 
-// StartListening 启动监听safeMint事件
-func (l *ERC721Listener) StartListeningSafeMint(ctx context.Context) error {
-	// 获取Transfer事件的ID（用于过滤日志）
+// HandleLog 处理单条日志（回放归档时按区块顺序逐条调用）
+// 仅处理本合约from为零地址的Transfer（safeMint），其他日志及因链重组被回滚的日志直接忽略
+func (l *ERC721Listener) HandleLog(ctx context.Context, logEntry types.Log) error {
 	transferEvent, ok := l.abi.Events["Transfer"]
 	if !ok {
 		return fmt.Errorf("ABI中未找到Transfer事件")
 	}
-	eventID := transferEvent.ID
+	if logEntry.Address != l.contractAddr || logEntry.Removed || len(logEntry.Topics) < 2 ||
+		logEntry.Topics[0] != transferEvent.ID || logEntry.Topics[1] != common.HexToHash(l.zeroAddr.Hex()) {
+		return nil
+	}
+	return l.handleSafeMint(ctx, logEntry)
+}
 
-	// 构造日志过滤条件：
-	filterQuery := ethereum.FilterQuery{
+// SafeMintQuery 本合约safeMint（from为零地址的Transfer）日志的过滤条件
+func (l *ERC721Listener) SafeMintQuery() (ethereum.FilterQuery, error) {
+	// 获取Transfer事件的ID（用于过滤日志）
+	transferEvent, ok := l.abi.Events["Transfer"]
+	if !ok {
+		return ethereum.FilterQuery{}, fmt.Errorf("ABI中未找到Transfer事件")
+	}
+
+	return ethereum.FilterQuery{
 		Addresses: []common.Address{l.contractAddr}, // 仅监听目标合约
 		Topics: [][]common.Hash{
-			{eventID},                            // Topics[0] = Transfer事件ID
+			{transferEvent.ID},                   // Topics[0] = Transfer事件ID
 			{common.HexToHash(l.zeroAddr.Hex())}, // Topics[1] = from地址（零地址，过滤safeMint）
 			nil,                                  // Topics[2] = to地址（任意）
 			nil,                                  // Topics[3] = tokenId（任意）
 		},
+	}, nil
+}
+
+// SetResumeBlock 设置订阅建立后补齐日志的起始区块（回填结束区块+1），须在StartListeningSafeMint之前调用
+func (l *ERC721Listener) SetResumeBlock(block uint64) {
+	l.resumeFrom = block
+}
+
+// Subscriptions StartListeningSafeMint建立的日志订阅数量：只订阅Transfer
+func (l *ERC721Listener) Subscriptions() int {
+	return 1
+}
+
+// StartListening 启动监听safeMint事件
+func (l *ERC721Listener) StartListeningSafeMint(ctx context.Context) error {
+	filterQuery, err := l.SafeMintQuery()
+	if err != nil {
+		return err
 	}
 
 	// 订阅日志
@@ -95,10 +153,30 @@ func (l *ERC721Listener) StartListeningSafeMint(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("订阅日志失败: %w", err)
 	}
-	defer sub.Unsubscribe()
+	defer func() { sub.Unsubscribe() }() // 重新订阅后sub会被替换，退出时取消最新的订阅
 
 	log.Info().Str("监听合约", l.contractAddr.Hex()).Msg("开始监听ERC721 safeMint事件...")
 
+	// 区块时间暂时无法获取而延后处理的事件，定期重试，避免阻塞后续事件；同时定期重新获取待补元数据的NFT
+	var retries []mintRetry
+	retryTicker := time.NewTicker(mintRetryInterval)
+	defer retryTicker.Stop()
+
+	// 订阅建立后补齐回填结束之后出块的日志，期间到达的订阅日志由订阅缓存，之后跳过已补齐的区块
+	var caughtUp uint64
+	if l.resumeFrom > 0 {
+		missed, head, err := chainclient.CatchUp(ctx, l.client, filterQuery, l.resumeFrom)
+		if err != nil {
+			return fmt.Errorf("补齐safeMint事件失败: %w", err)
+		}
+		for _, logEntry := range missed {
+			l.headers.ObserveLog(ctx, logEntry)
+			retries = l.processMint(ctx, retries, logEntry)
+		}
+		caughtUp = head
+		log.Info().Uint64("from", l.resumeFrom).Uint64("to", head).Int("logs", len(missed)).Msg("已补齐回填后出块的safeMint事件")
+	}
+
 	// 循环处理日志
 	for {
 		select {
@@ -115,10 +193,33 @@ func (l *ERC721Listener) StartListeningSafeMint(ctx context.Context) error {
 				return fmt.Errorf("重试订阅失败: %w", err)
 			}
 		case logEntry := <-logs:
-			// 处理单个日志条目
-			if err := l.handleSafeMint(ctx, logEntry); err != nil {
-				log.Error().Err(err).Str("交易哈希", logEntry.TxHash.Hex()).Msg("处理safeMint事件失败")
+			l.headers.ObserveLog(ctx, logEntry)
+			if logEntry.BlockNumber <= caughtUp && !logEntry.Removed {
+				continue
+			}
+			retries = l.processMint(ctx, retries, logEntry)
+		case <-retryTicker.C:
+			if len(retries) > 0 {
+				retries = l.retryMints(ctx, retries)
 			}
+			l.retryPendingMetadata(ctx)
 		}
 	}
 }
+
+// processMint 处理单个safeMint日志，区块时间暂时无法获取时加入重试队列
+func (l *ERC721Listener) processMint(ctx context.Context, retries []mintRetry, logEntry types.Log) []mintRetry {
+	// 链重组回滚的日志不重复处理
+	if logEntry.Removed {
+		log.Warn().Str("交易哈希", logEntry.TxHash.Hex()).Uint64("区块", logEntry.BlockNumber).Msg("safeMint事件因链重组被回滚，跳过")
+		return retries
+	}
+	err := l.handleSafeMint(ctx, logEntry)
+	switch {
+	case errors.Is(err, ErrMetadataDeferred):
+		retries = l.deferMint(retries, mintRetry{log: logEntry}, err)
+	case err != nil:
+		log.Error().Err(err).Str("交易哈希", logEntry.TxHash.Hex()).Msg("处理safeMint事件失败")
+	}
+	return retries
+}
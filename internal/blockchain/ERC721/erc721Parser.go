@@ -3,6 +3,7 @@ package ERC721
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -10,45 +11,24 @@ import (
 	"strings"
 	"time"
 
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
 	"github.com/ydh2333/NFTAuction-project/internal/models"
 	"github.com/ydh2333/NFTAuction-project/internal/repository"
 )
 
 // getTokenURI 调用合约tokenURI方法获取元数据链接
 func (l *ERC721Listener) getTokenURI(ctx context.Context, tokenId uint64) (string, error) {
-	tokenIdBig := big.NewInt(int64(tokenId))
-	// 打包调用参数
-	data, err := l.abi.Pack("tokenURI", tokenIdBig)
-	if err != nil {
-		return "", fmt.Errorf("打包tokenURI参数失败: %w", err)
-	}
-
-	// 构造合约调用消息
-	callMsg := ethereum.CallMsg{
-		To:   &l.contractAddr,
-		Data: data,
-	}
-
-	// 执行合约调用
-	result, err := l.client.CallContract(ctx, callMsg, nil)
+	tokenURI, err := l.caller.TokenURI(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(tokenId))
 	if err != nil {
 		return "", fmt.Errorf("调用tokenURI失败: %w", err)
 	}
-
-	// 解包返回值（string类型）
-	var tokenURI string
-	if err := l.abi.UnpackIntoInterface(&tokenURI, "tokenURI", result); err != nil {
-		return "", fmt.Errorf("解包tokenURI结果失败: %w", err)
-	}
-
 	return tokenURI, nil
 }
 
-// resolveMetadata 解析元数据链接（支持IPFS和HTTP）
+// resolveMetadata 解析元数据链接（支持IPFS和HTTP），按网关域名限流与熔断
 func (l *ERC721Listener) resolveMetadata(ctx context.Context, tokenURI string) (*NFTMetadata, error) {
 	// 处理IPFS链接（转换为HTTP可访问的链接）
 	resolvedURI := tokenURI
@@ -62,17 +42,35 @@ func (l *ERC721Listener) resolveMetadata(ctx context.Context, tokenURI string) (
 		return nil, fmt.Errorf("构建元数据请求失败: %w", err)
 	}
 
+	done, err := l.metadataGuard(req.URL.Host).Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := l.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("请求元数据失败: %w", err)
+		done(ctx.Err() == nil)
+		return nil, &upstreamError{fmt.Errorf("请求元数据失败: %w", err)}
 	}
 	defer resp.Body.Close()
 
-	// 读取响应体
-	body, err := io.ReadAll(resp.Body)
+	// 5xx与429说明网关本身有问题，计入熔断并稍后重试；其他状态码视为元数据本身不可用
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		done(true)
+		return nil, &upstreamError{fmt.Errorf("请求元数据失败，状态码: %d", resp.StatusCode)}
+	}
+	done(false)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("请求元数据失败，状态码: %d", resp.StatusCode)
+	}
+
+	// 读取响应体（限制大小，避免超大元数据占用内存）
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataBodySize+1))
 	if err != nil {
 		return nil, fmt.Errorf("读取元数据响应失败: %w", err)
 	}
+	if len(body) > maxMetadataBodySize {
+		return nil, fmt.Errorf("元数据超过%d字节", maxMetadataBodySize)
+	}
 
 	// 解析JSON元数据
 	var metadata NFTMetadata
@@ -85,29 +83,61 @@ func (l *ERC721Listener) resolveMetadata(ctx context.Context, tokenURI string) (
 
 // handleSafeMint 处理safeMint事件（解析Transfer日志）
 func (l *ERC721Listener) handleSafeMint(ctx context.Context, logEntry types.Log) error {
-	// 验证Transfer事件的Topics数量
-	if len(logEntry.Topics) < 4 {
-		return fmt.Errorf("日志Topics不足，无法解析Transfer事件")
+	blockTimeUnix, err := l.getBlockTime(ctx, logEntry.BlockNumber)
+	if err != nil {
+		if retryable(err) {
+			return fmt.Errorf("%w: %w", ErrMetadataDeferred, err)
+		}
+		return fmt.Errorf("获取区块时间失败: %w", err)
 	}
 
-	// 解析tokenID（uint256转uint64，如需高精度可改用big.Int）
-	var tokenIdHash common.Hash
-	tokenIdHash.SetBytes(logEntry.Topics[3].Bytes())
-	tokenIdBig := tokenIdHash.Big()
-	tokenId := tokenIdBig.Uint64()
+	nft, err := l.BuildNFT(ctx, logEntry, blockTimeUnix)
+	if err != nil || nft == nil {
+		return err
+	}
 
-	// 解析接收钱包地址
-	walletAddr := common.HexToAddress(logEntry.Topics[2].Hex()).Hex()
+	nftRepository := repository.NewNFTRepository()
+	if err := nftRepository.Create(nft); err != nil {
+		return err
+	}
 
-	blockTimeUnix, err := l.getBlockTime(ctx, logEntry.BlockNumber)
-	if err != nil {
-		return fmt.Errorf("获取区块时间失败: %w", err)
+	return nil
+}
+
+// BuildNFT 由safeMint日志构造NFT记录（获取tokenURI并解析、校验元数据），不写入数据库
+// blockTime为日志所在区块的Unix时间，由调用方提供以便批量获取区块头；
+// tokenURI或元数据获取失败时只输出基础信息并返回nil，与实时监听的处理一致；
+// 失败原因是上游被限流、熔断或暂时不可用时返回不含元数据、标记MetadataPending的记录，由监听器稍后重新获取
+func (l *ERC721Listener) BuildNFT(ctx context.Context, logEntry types.Log, blockTime uint64) (*models.NFT, error) {
+	// 解析Transfer事件（含索引字段from/to/tokenId）
+	event := new(contracts.ERC721Transfer)
+	if err := l.registry.UnpackLog(event, "Transfer", logEntry); err != nil {
+		return nil, fmt.Errorf("解析Transfer事件失败: %w", err)
 	}
 
-	// 1. 获取tokenURI
-	tokenURI, err := l.getTokenURI(ctx, tokenId)
-	if err != nil {
-		log.Warn().Err(err).Uint64("tokenId", tokenId).Msg("获取tokenURI失败，仅输出基础信息")
+	// 解析tokenID（uint256转uint64，如需高精度可改用big.Int）
+	tokenId := event.TokenId.Uint64()
+
+	// 解析接收钱包地址
+	walletAddr := event.To.Hex()
+
+	nft := &models.NFT{
+		ChainID:         l.chainID,
+		TokenID:         uint(tokenId),
+		ContractAddress: l.contractAddr.Hex(),
+		OwnerAddress:    walletAddr,
+		OptTime:         time.Unix(int64(blockTime), 0),
+	}
+	validation, err := l.fetchMetadata(ctx, tokenId)
+	switch {
+	case errors.Is(err, ErrMetadataDeferred):
+		retryAt := time.Now().Add(mintRetryBaseDelay)
+		nft.MetadataPending, nft.MetadataRetryAt = true, &retryAt
+		log.Warn().Err(err).Uint64("tokenId", tokenId).Time("retry_at", retryAt).Msg("NFT元数据暂时无法获取，先保存基础信息")
+		return nft, nil
+	case err != nil:
+		return nil, err
+	case validation == nil:
 		// 输出基础信息（无元数据）
 		l.printNFTInfo(&NFTInfo{
 			TokenID:      tokenId,
@@ -115,50 +145,60 @@ func (l *ERC721Listener) handleSafeMint(ctx context.Context, logEntry types.Log)
 			WalletAddr:   walletAddr,
 			TxHash:       logEntry.TxHash.Hex(),
 		})
-		return nil
+		return nil, nil
+	}
+
+	applyMetadata(nft, validation)
+	return nft, nil
+}
+
+// fetchMetadata 获取tokenURI并解析、清洗、校验元数据
+// 上游暂时不可用时返回ErrMetadataDeferred；tokenURI或元数据本身不可用时记录日志并返回nil
+func (l *ERC721Listener) fetchMetadata(ctx context.Context, tokenId uint64) (*MetadataValidationResult, error) {
+	// 1. 获取tokenURI
+	tokenURI, err := l.getTokenURI(ctx, tokenId)
+	if err != nil {
+		if retryable(err) {
+			return nil, fmt.Errorf("%w: %w", ErrMetadataDeferred, err)
+		}
+		log.Warn().Err(err).Uint64("tokenId", tokenId).Msg("获取tokenURI失败，仅输出基础信息")
+		return nil, nil
 	}
 
 	// 2. 解析元数据
 	metadata, err := l.resolveMetadata(ctx, tokenURI)
 	if err != nil {
+		if retryable(err) {
+			return nil, fmt.Errorf("%w: %w", ErrMetadataDeferred, err)
+		}
 		log.Warn().Err(err).Uint64("tokenId", tokenId).Str("tokenURI", tokenURI).Msg("解析元数据失败")
-		// 输出基础信息（无元数据）
-		l.printNFTInfo(&NFTInfo{
-			TokenID:      tokenId,
-			ContractAddr: l.contractAddr.Hex(),
-			WalletAddr:   walletAddr,
-			TxHash:       logEntry.TxHash.Hex(),
-		})
-		return nil
+		return nil, nil
 	}
 
-	nft := &models.NFT{
-		TokenID:         uint(tokenId),
-		ContractAddress: l.contractAddr.Hex(),
-		OwnerAddress:    walletAddr,
-		Name:            metadata.Name,
-		Description:     metadata.Description,
-		ImageURL:        metadata.Image,
-		OptTime:         time.Unix(int64(blockTimeUnix), 0),
-	}
-
-	nftRepository := repository.NewNFTRepository()
-	if err := nftRepository.Create(nft); err != nil {
-		return err
+	// 3. 清洗并校验元数据
+	validation := ValidateMetadata(metadata)
+	if len(validation.Warnings) > 0 {
+		log.Warn().Uint64("tokenId", tokenId).Strs("warnings", validation.Warnings).Msg("NFT元数据校验存在警告")
 	}
+	return validation, nil
+}
 
-	return nil
+// applyMetadata 写入校验后的元数据并清除待补标记
+func applyMetadata(nft *models.NFT, validation *MetadataValidationResult) {
+	nft.Name = validation.Metadata.Name
+	nft.Description = validation.Metadata.Description
+	nft.ImageURL = validation.Metadata.Image
+	nft.ValidationWarnings = validation.Warnings
+	nft.MetadataPending, nft.MetadataRetryAt = false, nil
 }
 
-// getBlockTime 根据区块号获取区块时间（格式化字符串 + Unix时间戳）
+// getBlockTime 根据区块号获取区块时间（Unix时间戳），优先读取区块头缓存
 func (l *ERC721Listener) getBlockTime(ctx context.Context, blockNumber uint64) (uint64, error) {
-	header, err := l.client.HeaderByNumber(ctx, big.NewInt(int64(blockNumber)))
+	blockTimeUnix, err := l.headers.BlockTime(ctx, blockNumber)
 	if err != nil {
 		return 0, fmt.Errorf("获取区块头失败: %w", err)
 	}
 
-	blockTimeUnix := header.Time
-
 	return blockTimeUnix, nil
 }
 
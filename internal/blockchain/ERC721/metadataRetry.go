@@ -0,0 +1,139 @@
+package ERC721
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/internal/resilience"
+)
+
+// ErrMetadataDeferred 上游（元数据网关或RPC节点）暂时不可用，需稍后重试
+// 元数据不可用时NFT先以MetadataPending保存，由监听器从数据库重新获取；区块时间不可用时safeMint事件在内存中延后处理
+var ErrMetadataDeferred = errors.New("NFT元数据暂时无法获取")
+
+// 延后处理的safeMint事件与待补元数据NFT的重试参数
+const (
+	mintRetryInterval   = 5 * time.Second  // 检查到期重试的间隔
+	mintRetryBaseDelay  = 30 * time.Second // 首次重试延迟，之后每次翻倍
+	mintRetryMaxDelay   = 10 * time.Minute
+	mintRetryMaxAttempt = 5
+	metadataRetryBatch  = 20 // 每次检查最多重新获取的NFT数量
+)
+
+// upstreamError 上游本身的故障（连接失败、超时、5xx、429），换个时间重试可能成功
+type upstreamError struct {
+	err error
+}
+
+func (e *upstreamError) Error() string {
+	return e.err.Error()
+}
+
+func (e *upstreamError) Unwrap() error {
+	return e.err
+}
+
+// retryable 错误是否应延后重试而非直接放弃元数据
+func retryable(err error) bool {
+	var upstream *upstreamError
+	return resilience.IsRejected(err) || errors.As(err, &upstream)
+}
+
+// mintRetry 等待重试的safeMint事件
+type mintRetry struct {
+	log      types.Log
+	attempts int
+	next     time.Time
+}
+
+// metadataGuard 元数据网关的限流与熔断（按域名区分）
+func (l *ERC721Listener) metadataGuard(host string) *resilience.Guard {
+	return resilience.For("metadata:"+host, l.guardCfg)
+}
+
+// deferMint 把处理失败的safeMint事件加入重试队列，超过最大次数时放弃
+func (l *ERC721Listener) deferMint(queue []mintRetry, retry mintRetry, err error) []mintRetry {
+	retry.attempts++
+	if retry.attempts > mintRetryMaxAttempt {
+		log.Error().Err(err).Str("交易哈希", retry.log.TxHash.Hex()).Int("attempts", retry.attempts).Msg("safeMint事件多次重试仍失败，放弃处理")
+		return queue
+	}
+	delay := min(mintRetryBaseDelay<<(retry.attempts-1), mintRetryMaxDelay)
+	if wait, ok := resilience.RetryAfter(err); ok {
+		delay = max(delay, wait)
+	}
+	retry.next = time.Now().Add(delay)
+	log.Warn().Err(err).Str("交易哈希", retry.log.TxHash.Hex()).Int("attempt", retry.attempts).Dur("delay", delay).Msg("safeMint事件延后重试")
+	return append(queue, retry)
+}
+
+// retryMints 处理到期的重试，返回仍需等待的事件
+func (l *ERC721Listener) retryMints(ctx context.Context, queue []mintRetry) []mintRetry {
+	now := time.Now()
+	pending := queue[:0:0]
+	for _, retry := range queue {
+		if now.Before(retry.next) || ctx.Err() != nil {
+			pending = append(pending, retry)
+			continue
+		}
+		err := l.handleSafeMint(ctx, retry.log)
+		switch {
+		case errors.Is(err, ErrMetadataDeferred):
+			pending = l.deferMint(pending, retry, err)
+		case err != nil:
+			log.Error().Err(err).Str("交易哈希", retry.log.TxHash.Hex()).Msg("处理safeMint事件失败")
+		default:
+			log.Info().Str("交易哈希", retry.log.TxHash.Hex()).Int("attempts", retry.attempts+1).Msg("safeMint事件重试成功")
+		}
+	}
+	return pending
+}
+
+// retryPendingMetadata 重新获取到期的待补元数据NFT（含重启前及回填时保存的记录）
+func (l *ERC721Listener) retryPendingMetadata(ctx context.Context) {
+	nftRepository := repository.NewNFTRepository()
+	nfts, err := nftRepository.GetMetadataPending(l.chainID, l.contractAddr.Hex(), time.Now(), metadataRetryBatch)
+	if err != nil {
+		return
+	}
+	for _, nft := range nfts {
+		if ctx.Err() != nil {
+			return
+		}
+		l.refreshMetadata(ctx, nft)
+		if err := nftRepository.UpdateMetadata(nft); err != nil {
+			return
+		}
+	}
+}
+
+// refreshMetadata 重新获取元数据：成功时写入元数据，上游仍不可用时按退避延后，超过最大次数或元数据本身不可用时放弃（保留基础信息）
+func (l *ERC721Listener) refreshMetadata(ctx context.Context, nft *models.NFT) {
+	validation, err := l.fetchMetadata(ctx, uint64(nft.TokenID))
+	switch {
+	case errors.Is(err, ErrMetadataDeferred):
+		nft.MetadataAttempts++
+		if nft.MetadataAttempts >= mintRetryMaxAttempt {
+			log.Error().Err(err).Uint("tokenId", nft.TokenID).Int("attempts", nft.MetadataAttempts).Msg("NFT元数据多次重试仍失败，放弃获取")
+			nft.MetadataPending, nft.MetadataRetryAt = false, nil
+			return
+		}
+		delay := min(mintRetryBaseDelay<<nft.MetadataAttempts, mintRetryMaxDelay)
+		if wait, ok := resilience.RetryAfter(err); ok {
+			delay = max(delay, wait)
+		}
+		retryAt := time.Now().Add(delay)
+		nft.MetadataRetryAt = &retryAt
+		log.Warn().Err(err).Uint("tokenId", nft.TokenID).Int("attempt", nft.MetadataAttempts).Dur("delay", delay).Msg("NFT元数据延后重试")
+	case err != nil || validation == nil:
+		nft.MetadataPending, nft.MetadataRetryAt = false, nil
+	default:
+		applyMetadata(nft, validation)
+		log.Info().Uint("tokenId", nft.TokenID).Int("attempts", nft.MetadataAttempts+1).Msg("NFT元数据重新获取成功")
+	}
+}
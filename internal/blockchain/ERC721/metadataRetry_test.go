@@ -0,0 +1,131 @@
+package ERC721
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/internal/testutil"
+)
+
+// newMintListener 模拟链上一次safeMint，tokenURI指向可切换状态的元数据网关
+// nftContract 测试中铸造NFT的合约地址
+var nftContract = common.HexToAddress("0x0000000000000000000000000000000000000721")
+
+func newMintListener(t *testing.T, gatewayUp *atomic.Bool) (*ERC721Listener, types.Log) {
+	t.Helper()
+	testutil.InitDB(t)
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !gatewayUp.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"Cool Cat #5","description":"cat","image":"https://example.com/5.png"}`))
+	}))
+	t.Cleanup(gateway.Close)
+
+	contractAddr := nftContract
+	fake := chainclient.NewFake()
+	listener, err := NewERC721ListenerWithClient(&config.BlockchainConfig{ChainID: 1, ERC721ContractAddr: contractAddr.Hex()}, fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.MockMethod(contractAddr, listener.abi, "tokenURI", gateway.URL+"/5.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	owner := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	_, logs := fake.Mine(types.Log{
+		Address: contractAddr,
+		Topics: []common.Hash{
+			listener.abi.Events["Transfer"].ID,
+			common.Hash{},
+			common.BytesToHash(owner.Bytes()),
+			common.BigToHash(big.NewInt(5)),
+		},
+	})
+	return listener, logs[0]
+}
+
+// dueNow 把待补元数据的重试时间提前到当前
+func dueNow(t *testing.T) {
+	t.Helper()
+	if err := repository.DB.Model(&models.NFT{}).Where("metadata_pending = ?", true).
+		Update("metadata_retry_at", time.Now().Add(-time.Second)).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getNFT(t *testing.T) *models.NFT {
+	t.Helper()
+	nft, err := repository.NewNFTRepository().GetNFTByTokenID(1, nftContract.Hex(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return nft
+}
+
+// TestPendingMetadataRetry 元数据网关不可用时先保存NFT基础信息，恢复后从数据库重新获取元数据
+func TestPendingMetadataRetry(t *testing.T) {
+	var gatewayUp atomic.Bool
+	listener, mint := newMintListener(t, &gatewayUp)
+	ctx := context.Background()
+
+	if err := listener.handleSafeMint(ctx, mint); err != nil {
+		t.Fatalf("handleSafeMint: %v", err)
+	}
+	nft := getNFT(t)
+	if !nft.MetadataPending || nft.Name != "" || nft.MetadataRetryAt == nil {
+		t.Fatalf("网关不可用时应保存待补元数据的NFT，实际 %+v", nft)
+	}
+
+	// 未到重试时间不处理
+	gatewayUp.Store(true)
+	listener.retryPendingMetadata(ctx)
+	if !getNFT(t).MetadataPending {
+		t.Fatal("未到重试时间就重新获取了元数据")
+	}
+
+	dueNow(t)
+	listener.retryPendingMetadata(ctx)
+	nft = getNFT(t)
+	if nft.MetadataPending || nft.Name != "Cool Cat #5" || nft.ImageURL != "https://example.com/5.png" {
+		t.Fatalf("重新获取元数据后 %+v", nft)
+	}
+}
+
+// TestPendingMetadataGiveUp 网关持续不可用时重试达到上限后放弃，保留基础信息
+func TestPendingMetadataGiveUp(t *testing.T) {
+	var gatewayUp atomic.Bool
+	listener, mint := newMintListener(t, &gatewayUp)
+	ctx := context.Background()
+
+	if err := listener.handleSafeMint(ctx, mint); err != nil {
+		t.Fatalf("handleSafeMint: %v", err)
+	}
+	for attempt := 1; attempt <= mintRetryMaxAttempt; attempt++ {
+		dueNow(t)
+		listener.retryPendingMetadata(ctx)
+		nft := getNFT(t)
+		if nft.MetadataAttempts != attempt {
+			t.Fatalf("第%d次重试后 attempts = %d", attempt, nft.MetadataAttempts)
+		}
+		if want := attempt < mintRetryMaxAttempt; nft.MetadataPending != want {
+			t.Fatalf("第%d次重试后 pending = %v，期望 %v", attempt, nft.MetadataPending, want)
+		}
+	}
+	if nft := getNFT(t); nft.OwnerAddress == "" || nft.Name != "" {
+		t.Fatalf("放弃后应保留基础信息，实际 %+v", nft)
+	}
+}
@@ -0,0 +1,154 @@
+package ERC721
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 元数据字段长度限制（与nfts表字段定义保持一致）
+const (
+	maxNameLength        = 255  // name字段为varchar(255)
+	maxDescriptionLength = 5000 // description字段为text，限制长度避免超大文本
+	maxImageURLLength    = 512  // image_url字段为varchar(512)
+	maxMetadataBodySize  = 1 << 20
+)
+
+var (
+	// 脚本/样式块（连同内容一起删除）
+	scriptBlockRegexp = regexp.MustCompile(`(?is)<(script|style|iframe|object|embed)[^>]*>.*?</(script|style|iframe|object|embed)\s*>`)
+	// 其余HTML标签、注释与声明（仅删除标签本身）；<后紧跟字母、/、!或?才视为标签，保留"1 < 2 > 0"这类普通文本
+	htmlTagRegexp = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z][^>]*>|<[!?][^>]*>`)
+	// 连续空白
+	spaceRegexp = regexp.MustCompile(`[ \t]+`)
+)
+
+// 允许的图片链接协议
+var allowedImageSchemes = map[string]bool{
+	"http":  true,
+	"https": true,
+	"ipfs":  true,
+	"ar":    true,
+}
+
+// MetadataValidationResult 元数据校验结果
+type MetadataValidationResult struct {
+	Metadata *NFTMetadata // 清洗后的元数据
+	Warnings []string     // 校验警告（字段被截断、清洗、拒绝等）
+}
+
+// ValidateMetadata 清洗并校验NFT元数据，返回可安全入库的元数据及警告信息
+func ValidateMetadata(metadata *NFTMetadata) *MetadataValidationResult {
+	result := &MetadataValidationResult{Metadata: &NFTMetadata{}}
+	if metadata == nil {
+		result.Warnings = append(result.Warnings, "元数据为空")
+		return result
+	}
+
+	result.Metadata.Name = sanitizeText("name", metadata.Name, maxNameLength, false, &result.Warnings)
+	result.Metadata.Description = sanitizeText("description", metadata.Description, maxDescriptionLength, true, &result.Warnings)
+	result.Metadata.Image = validateImageURL(metadata.Image, &result.Warnings)
+
+	if result.Metadata.Name == "" {
+		result.Warnings = append(result.Warnings, "name为空")
+	}
+
+	return result
+}
+
+// sanitizeText 去除标记、规范化Unicode并限制长度
+func sanitizeText(field, value string, maxLength int, multiline bool, warnings *[]string) string {
+	if value == "" {
+		return ""
+	}
+
+	if !utf8.ValidString(value) {
+		value = strings.ToValidUTF8(value, "")
+		*warnings = append(*warnings, fmt.Sprintf("%s包含非法UTF-8字符，已移除", field))
+	}
+
+	// 1. 实体只解码一次再删除标记：转义后的标签解码后同样被删除，"a &lt; b"保留为"a < b"
+	// 入库的是纯文本，展示时由前端转义
+	decoded := html.UnescapeString(value)
+	stripped := stripMarkup(decoded)
+	if stripped != decoded {
+		*warnings = append(*warnings, fmt.Sprintf("%s包含HTML标记，已移除", field))
+	}
+
+	// 2. Unicode规范化（NFC）并移除控制字符
+	cleaned := strings.Map(func(r rune) rune {
+		if r == '\n' && multiline {
+			return r
+		}
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, norm.NFC.String(stripped))
+	cleaned = strings.TrimSpace(spaceRegexp.ReplaceAllString(cleaned, " "))
+
+	// 3. 长度限制（按字符计算，与MySQL utf8mb4的varchar长度语义一致）
+	if utf8.RuneCountInString(cleaned) > maxLength {
+		cleaned = strings.TrimSpace(string([]rune(cleaned)[:maxLength]))
+		*warnings = append(*warnings, fmt.Sprintf("%s超过%d字符，已截断", field, maxLength))
+	}
+
+	return cleaned
+}
+
+// stripMarkup 删除脚本块和HTML标签
+func stripMarkup(value string) string {
+	value = scriptBlockRegexp.ReplaceAllString(value, "")
+	return htmlTagRegexp.ReplaceAllString(value, "")
+}
+
+// validateImageURL 校验图片链接，非法链接返回空字符串
+func validateImageURL(image string, warnings *[]string) string {
+	image = strings.TrimSpace(image)
+	if image == "" {
+		*warnings = append(*warnings, "image为空")
+		return ""
+	}
+
+	if len(image) > maxImageURLLength {
+		*warnings = append(*warnings, fmt.Sprintf("image链接超过%d字符，已拒绝", maxImageURLLength))
+		return ""
+	}
+
+	u, err := url.Parse(image)
+	if err != nil {
+		*warnings = append(*warnings, "image链接格式错误，已拒绝")
+		return ""
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if !allowedImageSchemes[scheme] {
+		*warnings = append(*warnings, fmt.Sprintf("image链接协议%q不被允许，已拒绝", u.Scheme))
+		return ""
+	}
+
+	if (scheme == "http" || scheme == "https") && u.Host == "" {
+		*warnings = append(*warnings, "image链接缺少域名，已拒绝")
+		return ""
+	}
+	if (scheme == "ipfs" || scheme == "ar") && u.Host == "" && u.Opaque == "" && u.Path == "" {
+		*warnings = append(*warnings, "image链接缺少内容标识，已拒绝")
+		return ""
+	}
+
+	if strings.ContainsAny(image, "<>\"' ") {
+		*warnings = append(*warnings, "image链接包含非法字符，已拒绝")
+		return ""
+	}
+
+	return image
+}
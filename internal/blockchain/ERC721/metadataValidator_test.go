@@ -0,0 +1,101 @@
+package ERC721
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	tests := []struct {
+		name        string
+		metadata    *NFTMetadata
+		want        NFTMetadata
+		wantWarning string // 期望出现的警告片段，为空表示不应有警告
+	}{
+		{
+			name:     "正常元数据",
+			metadata: &NFTMetadata{Name: "Cool Cat #1", Description: "第一行\n第二行", Image: "ipfs://QmHash/1.png"},
+			want:     NFTMetadata{Name: "Cool Cat #1", Description: "第一行\n第二行", Image: "ipfs://QmHash/1.png"},
+		},
+		{
+			name:     "比较符号不是标记",
+			metadata: &NFTMetadata{Name: "1 < 2 > 0", Description: "a &lt; b", Image: "https://example.com/a.png"},
+			want:     NFTMetadata{Name: "1 < 2 > 0", Description: "a < b", Image: "https://example.com/a.png"},
+		},
+		{
+			name:        "删除HTML标签",
+			metadata:    &NFTMetadata{Name: "<b>Bold</b> name", Description: "x<!-- c -->y", Image: "https://example.com/a.png"},
+			want:        NFTMetadata{Name: "Bold name", Description: "xy", Image: "https://example.com/a.png"},
+			wantWarning: "HTML标记",
+		},
+		{
+			name:        "删除脚本块",
+			metadata:    &NFTMetadata{Name: "hi<script>alert(1)</script>", Image: "https://example.com/a.png"},
+			want:        NFTMetadata{Name: "hi", Image: "https://example.com/a.png"},
+			wantWarning: "HTML标记",
+		},
+		{
+			name:        "转义后的标签解码后删除",
+			metadata:    &NFTMetadata{Name: "hi&lt;script&gt;alert(1)&lt;/script&gt;", Image: "https://example.com/a.png"},
+			want:        NFTMetadata{Name: "hi", Image: "https://example.com/a.png"},
+			wantWarning: "HTML标记",
+		},
+		{
+			name:     "单行字段换行与控制字符",
+			metadata: &NFTMetadata{Name: "a\nb\u200bc\x07", Image: "https://example.com/a.png"},
+			want:     NFTMetadata{Name: "a bc", Image: "https://example.com/a.png"},
+		},
+		{
+			name:        "超长名称截断",
+			metadata:    &NFTMetadata{Name: strings.Repeat("名", maxNameLength+10), Image: "https://example.com/a.png"},
+			want:        NFTMetadata{Name: strings.Repeat("名", maxNameLength), Image: "https://example.com/a.png"},
+			wantWarning: "已截断",
+		},
+		{
+			name:        "拒绝javascript协议",
+			metadata:    &NFTMetadata{Name: "n", Image: "javascript:alert(1)"},
+			want:        NFTMetadata{Name: "n"},
+			wantWarning: "协议",
+		},
+		{
+			name:        "拒绝缺少域名的http链接",
+			metadata:    &NFTMetadata{Name: "n", Image: "https:///a.png"},
+			want:        NFTMetadata{Name: "n"},
+			wantWarning: "缺少域名",
+		},
+		{
+			name:        "拒绝包含引号的链接",
+			metadata:    &NFTMetadata{Name: "n", Image: `https://example.com/a.png"onerror="x`},
+			want:        NFTMetadata{Name: "n"},
+			wantWarning: "非法字符",
+		},
+		{
+			name:        "名称为空",
+			metadata:    &NFTMetadata{Name: "<i></i>", Image: "https://example.com/a.png"},
+			want:        NFTMetadata{Image: "https://example.com/a.png"},
+			wantWarning: "name为空",
+		},
+		{
+			name:        "元数据为空",
+			metadata:    nil,
+			want:        NFTMetadata{},
+			wantWarning: "元数据为空",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateMetadata(tt.metadata)
+			if *result.Metadata != tt.want {
+				t.Errorf("metadata = %+v, want %+v", *result.Metadata, tt.want)
+			}
+			warnings := strings.Join(result.Warnings, "; ")
+			if tt.wantWarning == "" && len(result.Warnings) > 0 {
+				t.Errorf("unexpected warnings: %s", warnings)
+			}
+			if tt.wantWarning != "" && !strings.Contains(warnings, tt.wantWarning) {
+				t.Errorf("warnings = %q, want containing %q", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package NFTAuction
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/utils/logger"
+	"gorm.io/gorm"
+)
+
+// LogQuery 拍卖合约全部监听事件的过滤条件（历史区块回填使用，调用方设置区块范围）
+func (l *Listener) LogQuery() ethereum.FilterQuery {
+	var ids []common.Hash
+	for _, h := range l.eventHandlers() {
+		ids = append(ids, l.registry.EventIDs(h.name)...)
+	}
+	return ethereum.FilterQuery{
+		Addresses: []common.Address{l.contractAddr},
+		Topics:    [][]common.Hash{ids},
+	}
+}
+
+// eventName 日志对应的监听事件名，非监听事件返回空字符串
+func (l *Listener) eventName(log types.Log) string {
+	if log.Address != l.contractAddr || len(log.Topics) == 0 || log.Removed {
+		return ""
+	}
+	for _, h := range l.eventHandlers() {
+		for _, id := range l.registry.EventIDs(h.name) {
+			if id == log.Topics[0] {
+				return h.name
+			}
+		}
+	}
+	return ""
+}
+
+// SaveABIVersions 保存当前的ABI版本切换记录，返回恢复函数（ApplyLogs所在事务回滚时调用）
+func (l *Listener) SaveABIVersions() (restore func()) {
+	return l.registry.Snapshot()
+}
+
+// endedAuction 批量同步中待结算的拍卖及其EndAuction事件
+type endedAuction struct {
+	log   types.Log
+	key   models.AuctionKey
+	event *contracts.NFTAuctionEndAuction
+}
+
+// ApplyLogs 在事务tx中批量写入一段区块范围内的拍卖日志（logs须按区块号、日志序号排序）
+// 先批量插入拍卖与出价，再按每个拍卖最后一次出价更新最高价，最后结算已结束的拍卖、取消无人出价或已取消的拍卖，
+// 结果与逐条处理相同；合约升级/初始化事件按顺序在tx中即时处理，保证后续日志使用正确的ABI版本解析，
+// 事务回滚时调用方须以SaveABIVersions返回的函数撤销期间切换的ABI版本。
+// settlements为事务前以FetchSettlements查询的结算数据，事务内不访问节点。
+// 回填不更新Redis热度排行，API服务启动时会从MySQL重建。
+func (l *Listener) ApplyLogs(tx *gorm.DB, logs []types.Log, settlements Settlements) error {
+	var (
+		auctions  []*models.Auction
+		bids      []*models.Bid
+		highest   = make(map[models.AuctionKey]*models.Bid) // 拍卖 → 最后一次出价
+		ended     []endedAuction
+		cancelled = make(map[models.AuctionKey]models.AuctionTransition) // 取消的拍卖 → 取消迁移
+	)
+	for _, log := range logs {
+		switch l.eventName(log) {
+		case "CreateAuction":
+			auction, err := l.parseAuctionCreated(log)
+			if err != nil {
+				return err
+			}
+			auctions = append(auctions, auction)
+		case "PlaceBid":
+			bid, err := l.parseBidPlaced(log)
+			if err != nil {
+				return err
+			}
+			bids = append(bids, bid)
+			highest[bid.AuctionKey()] = bid
+		case "EndAuction":
+			key, event, err := l.parseAuctionEnded(log)
+			if err != nil {
+				return err
+			}
+			if event.Winner == (common.Address{}) {
+				cancelled[key] = eventTransition(log, models.AuctionStatusCancelled, models.AuctionCauseNoBidEnded, time.Unix(int64(event.OptTime.Uint64()), 0))
+				continue
+			}
+			ended = append(ended, endedAuction{log: log, key: key, event: event})
+		case "CancelAuction":
+			key, at, err := l.parseAuctionCancelled(log)
+			if err != nil {
+				return err
+			}
+			cancelled[key] = eventTransition(log, models.AuctionStatusCancelled, models.AuctionCauseCancelEvent, at)
+		case "Upgraded":
+			if err := l.applyUpgraded(tx, log); err != nil {
+				return err
+			}
+		case "Initialized":
+			if err := l.applyInitialized(tx, log); err != nil {
+				return err
+			}
+		}
+	}
+
+	auctionRepository := repository.NewAuctionRepositoryWithTx(tx)
+	bidRepository := repository.NewBidRepositoryWithTx(tx)
+	if err := auctionRepository.CreateBatch(auctions); err != nil {
+		return logger.WrapError(err, "批量保存拍卖数据失败")
+	}
+	if err := bidRepository.CreateBatch(bids); err != nil {
+		return logger.WrapError(err, "批量保存出价记录失败")
+	}
+
+	// 按拍卖ID顺序更新，避免并发事务间的锁顺序不一致
+	keys := make([]models.AuctionKey, 0, len(highest))
+	for key := range highest {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	for _, key := range keys {
+		bid := highest[key]
+		if err := auctionRepository.UpdateCurrentPrice(key, bid.Amount, bid.BidderAddress, bid.TokenAddress); err != nil {
+			return logger.WrapError(err, "更新拍卖的当前最高价和出价者失败")
+		}
+	}
+
+	for _, e := range ended {
+		if err := l.endAuction(tx, e.log, e.key, e.event, settlements[logPosition{e.log.TxHash, e.log.Index}]); err != nil {
+			return err
+		}
+	}
+	cancelledKeys := make([]models.AuctionKey, 0, len(cancelled))
+	for key := range cancelled {
+		cancelledKeys = append(cancelledKeys, key)
+	}
+	sort.Slice(cancelledKeys, func(i, j int) bool { return cancelledKeys[i].ID < cancelledKeys[j].ID })
+	for _, key := range cancelledKeys {
+		if _, err := auctionRepository.Cancel(key, cancelled[key]); err != nil {
+			return logger.WrapError(err, "取消拍卖失败")
+		}
+	}
+
+	logger.Log.Info().Int("auctions", len(auctions)).Int("bids", len(bids)).Int("ended", len(ended)).Int("cancelled", len(cancelled)).Msg("批量同步拍卖事件成功")
+	return nil
+}
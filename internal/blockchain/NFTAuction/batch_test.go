@@ -0,0 +1,297 @@
+package NFTAuction_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/NFTAuction"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/simchain"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/internal/testutil"
+	"gorm.io/gorm"
+)
+
+var (
+	auctionAddr = common.HexToAddress("0x00000000000000000000000000000000000a0c71")
+	nftAddr     = common.HexToAddress("0x0000000000000000000000000000000000000721")
+	seller      = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	bidder1     = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	bidder2     = common.HexToAddress("0x3000000000000000000000000000000000000003")
+)
+
+// event 一条待出块的拍卖事件
+type event struct {
+	name string
+	data interface{}
+}
+
+func create(id, start int64) event {
+	return event{"CreateAuction", contracts.NFTAuctionCreateAuction{
+		AuctionId:   big.NewInt(id),
+		Seller:      seller,
+		Duration:    big.NewInt(3600),
+		StartPrice:  big.NewInt(100),
+		StartTime:   big.NewInt(start),
+		NftContract: nftAddr,
+		NftId:       big.NewInt(id),
+		OptTime:     big.NewInt(start),
+	}}
+}
+
+func bid(id int64, bidder common.Address, amount int64) event {
+	return event{"PlaceBid", contracts.NFTAuctionPlaceBid{AuctionId: big.NewInt(id), Bidder: bidder, Amount: big.NewInt(amount), OptTime: big.NewInt(1)}}
+}
+
+func end(id int64, winner common.Address, amount int64) event {
+	return event{"EndAuction", contracts.NFTAuctionEndAuction{AuctionId: big.NewInt(id), Winner: winner, Amount: big.NewInt(amount), OptTime: big.NewInt(2)}}
+}
+
+// mine 每条事件单独出块，返回带区块号、交易哈希与日志序号的日志
+func mine(t *testing.T, fake *chainclient.Fake, events []event) []types.Log {
+	t.Helper()
+	parsed, err := contracts.NFTAuctionMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logs []types.Log
+	for _, e := range events {
+		lg, err := simchain.EncodeEvent(parsed, auctionAddr, e.name, e.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, mined := fake.Mine(lg)
+		logs = append(logs, mined...)
+	}
+	return logs
+}
+
+// snapshot 拍卖、出价、成交结果与状态历史的可比较内容（不含自增ID与写入时间）
+func snapshot(t *testing.T) []string {
+	t.Helper()
+	var (
+		auctions  []models.Auction
+		bids      []models.Bid
+		results   []models.AuctionResult
+		histories []models.AuctionStatusHistory
+	)
+	db := repository.DB
+	if err := db.Order("id").Find(&auctions).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Order("tx_hash").Find(&bids).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Order("auction_id").Find(&results).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Order("auction_id, block_number, id").Find(&histories).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []string
+	bidHash := make(map[uint]string)
+	for _, a := range auctions {
+		rows = append(rows, fmt.Sprintf("auction %d status=%s highest=%d/%s settled=%v cancelled=%v",
+			a.ID, a.Status, a.HighestBid, a.HighestBidder, a.Settled, a.CancelledAt != nil))
+	}
+	for _, b := range bids {
+		bidHash[b.ID] = *b.TxHash
+		rows = append(rows, fmt.Sprintf("bid %d %s %d winning=%v tx=%s", b.AuctionID, b.BidderAddress, b.Amount, b.IsWinning, *b.TxHash))
+	}
+	for _, r := range results {
+		winning := ""
+		if r.WinningBidID != nil {
+			winning = bidHash[*r.WinningBidID]
+		}
+		rows = append(rows, fmt.Sprintf("result %d winner=%s price=%d winningBid=%s tx=%s", r.AuctionID, r.WinnerAddress, r.FinalPrice, winning, r.SettlementTxHash))
+	}
+	for _, h := range histories {
+		rows = append(rows, fmt.Sprintf("history %d %s→%s cause=%s block=%d", h.AuctionID, h.FromStatus, h.ToStatus, h.Cause, h.BlockNumber))
+	}
+	return rows
+}
+
+// TestApplyLogsMatchesHandleLog 批量写入与逐条处理同一组日志后，数据库内容一致
+func TestApplyLogsMatchesHandleLog(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []event
+	}{
+		{"出价后成交", []event{create(1, 1), bid(1, bidder1, 150), bid(1, bidder2, 200), end(1, bidder2, 200)}},
+		{"无人出价结束", []event{create(1, 1), end(1, common.Address{}, 0)}},
+		{"多个拍卖交错", []event{
+			create(1, 1), create(2, 1), bid(2, bidder1, 120), bid(1, bidder2, 300),
+			bid(2, bidder2, 130), bid(1, bidder1, 310), end(2, bidder2, 130),
+		}},
+		{"同一出价者多次出价", []event{create(1, 1), bid(1, bidder1, 150), bid(1, bidder1, 160), end(1, bidder1, 160)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := chainclient.NewFake()
+			logs := mine(t, fake, tt.events)
+			cfg := &config.BlockchainConfig{ChainID: simchain.ChainID}
+			deployment := config.AuctionContractConfig{Address: auctionAddr.Hex()}
+
+			// 逐条处理
+			testutil.InitDB(t)
+			testutil.InitRedis(t)
+			listener, err := NFTAuction.NewDeploymentListener(cfg, deployment, fake)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, lg := range logs {
+				if err := listener.HandleLog(lg); err != nil {
+					t.Fatalf("HandleLog: %v", err)
+				}
+			}
+			want := snapshot(t)
+
+			// 批量写入（新库）
+			testutil.InitDB(t)
+			listener, err = NFTAuction.NewDeploymentListener(cfg, deployment, fake)
+			if err != nil {
+				t.Fatal(err)
+			}
+			settlements := listener.FetchSettlements(context.Background(), logs)
+			if err := repository.DB.Transaction(func(tx *gorm.DB) error {
+				return listener.ApplyLogs(tx, logs, settlements)
+			}); err != nil {
+				t.Fatalf("ApplyLogs: %v", err)
+			}
+			got := snapshot(t)
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ApplyLogs结果与逐条处理不一致\nApplyLogs:\n%v\nHandleLog:\n%v", got, want)
+			}
+			if len(want) == 0 {
+				t.Error("逐条处理未写入任何数据")
+			}
+		})
+	}
+}
+
+// TestApplyLogsRollback 事务回滚后不留下合约历史记录
+func TestApplyLogsRollback(t *testing.T) {
+	testutil.InitDB(t)
+	fake := chainclient.NewFake()
+	logs := mine(t, fake, []event{
+		create(1, 1),
+		{"Upgraded", contracts.NFTAuctionUpgraded{Implementation: common.HexToAddress("0x00000000000000000000000000000000000000b2")}},
+		{"Initialized", contracts.NFTAuctionInitialized{Version: 2}},
+	})
+	listener, err := NFTAuction.NewDeploymentListener(&config.BlockchainConfig{ChainID: simchain.ChainID},
+		config.AuctionContractConfig{Address: auctionAddr.Hex()}, fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rollback := errors.New("rollback")
+	err = repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := listener.ApplyLogs(tx, logs, nil); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("Transaction = %v, want %v", err, rollback)
+	}
+
+	var count int64
+	if err := repository.DB.Model(&models.ContractHistory{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("回滚后仍有%d条合约历史记录", count)
+	}
+}
+
+// offlineClient 设置offline后合约调用与日志查询均失败，用于确认写入事务内不访问节点
+type offlineClient struct {
+	*chainclient.Fake
+	offline atomic.Bool
+}
+
+func (c *offlineClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if c.offline.Load() {
+		return nil, errors.New("offline")
+	}
+	return c.Fake.FilterLogs(ctx, q)
+}
+
+func (c *offlineClient) CallContract(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	if c.offline.Load() {
+		return nil, errors.New("offline")
+	}
+	return c.Fake.CallContract(ctx, msg, block)
+}
+
+// TestApplyLogsSettlement 成交美元价值与NFT转移在事务前查询，写入时按拍卖的NFT合约与TokenID匹配Transfer事件
+func TestApplyLogsSettlement(t *testing.T) {
+	testutil.InitDB(t)
+	fake := chainclient.NewFake()
+	client := &offlineClient{Fake: fake}
+	auctionABI, err := contracts.NFTAuctionMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.MockMethod(auctionAddr, auctionABI, "calculateValue", big.NewInt(300)); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := mine(t, fake, []event{create(1, 1), bid(1, bidder1, 150)})
+	// 结算交易：先转出另一个NFT合约的同号NFT，再转出拍卖的NFT，最后发出EndAuction
+	settleTx := common.HexToHash("0x5e771e")
+	transfer := func(contract common.Address) types.Log {
+		return types.Log{Address: contract, TxHash: settleTx, Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+			common.BytesToHash(auctionAddr.Bytes()),
+			common.BytesToHash(bidder1.Bytes()),
+			common.BigToHash(big.NewInt(1)),
+		}}
+	}
+	ended, err := simchain.EncodeEvent(auctionABI, auctionAddr, "EndAuction", end(1, bidder1, 150).data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ended.TxHash = settleTx
+	_, mined := fake.Mine(transfer(common.HexToAddress("0x0000000000000000000000000000000000000999")), transfer(nftAddr), ended)
+	logs = append(logs, mined[2])
+
+	listener, err := NFTAuction.NewDeploymentListener(&config.BlockchainConfig{ChainID: simchain.ChainID},
+		config.AuctionContractConfig{Address: auctionAddr.Hex()}, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settlements := listener.FetchSettlements(context.Background(), logs)
+	client.offline.Store(true)
+	if err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		return listener.ApplyLogs(tx, logs, settlements)
+	}); err != nil {
+		t.Fatalf("ApplyLogs: %v", err)
+	}
+
+	var result models.AuctionResult
+	if err := repository.DB.First(&result).Error; err != nil {
+		t.Fatal(err)
+	}
+	if result.USDValue == nil || *result.USDValue != "300" {
+		t.Errorf("USDValue = %v，期望 300", result.USDValue)
+	}
+	if !result.NFTTransferred || result.NFTTransferLogIndex == nil || *result.NFTTransferLogIndex != mined[1].Index {
+		t.Errorf("NFT转移 = %v/%v，期望日志序号 %d", result.NFTTransferred, result.NFTTransferLogIndex, mined[1].Index)
+	}
+}
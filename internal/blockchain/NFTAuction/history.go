@@ -0,0 +1,103 @@
+package NFTAuction
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/utils/logger"
+	"gorm.io/gorm"
+)
+
+// 合约快照查询超时时间
+const snapshotTimeout = 10 * time.Second
+
+// 处理Upgraded事件（合约升级），记录合约历史，后续区块的日志使用新实现合约的ABI解析
+func (l *Listener) handleUpgraded(log types.Log) error {
+	return l.applyUpgraded(repository.DB, log)
+}
+
+// applyUpgraded 在db中记录合约升级（批量写入时为回填事务），记录成功后切换ABI版本
+func (l *Listener) applyUpgraded(db *gorm.DB, log types.Log) error {
+	event := new(contracts.NFTAuctionUpgraded)
+	if err := l.registry.UnpackLog(event, "Upgraded", log); err != nil {
+		return logger.WrapError(err, "解析Upgraded事件失败")
+	}
+
+	history := l.newContractHistory(log, models.ContractEventUpgraded)
+	history.Implementation = event.Implementation.Hex()
+	if err := repository.NewContractHistoryRepositoryWithTx(db).Create(history); err != nil {
+		return logger.WrapError(err, "保存合约升级记录失败")
+	}
+
+	l.registry.Activate(event.Implementation, log.BlockNumber)
+	logger.Log.Info().Str("implementation", event.Implementation.Hex()).Uint64("block", log.BlockNumber).Msg("拍卖合约已升级，切换ABI版本")
+	return nil
+}
+
+// 处理Initialized事件（合约初始化/重新初始化）
+func (l *Listener) handleInitialized(log types.Log) error {
+	return l.applyInitialized(repository.DB, log)
+}
+
+// applyInitialized 在db中记录合约初始化（批量写入时为回填事务）
+func (l *Listener) applyInitialized(db *gorm.DB, log types.Log) error {
+	event := new(contracts.NFTAuctionInitialized)
+	if err := l.registry.UnpackLog(event, "Initialized", log); err != nil {
+		return logger.WrapError(err, "解析Initialized事件失败")
+	}
+
+	history := l.newContractHistory(log, models.ContractEventInitialized)
+	history.Version = event.Version
+	if impl, ok := l.registry.ImplementationAt(log.BlockNumber); ok {
+		history.Implementation = impl.Hex()
+	}
+	if err := repository.NewContractHistoryRepositoryWithTx(db).Create(history); err != nil {
+		return logger.WrapError(err, "保存合约初始化记录失败")
+	}
+
+	logger.Log.Info().Uint64("version", event.Version).Uint64("block", log.BlockNumber).Msg("同步合约初始化事件成功")
+	return nil
+}
+
+// newContractHistory 构造合约历史记录，并快照事件所在区块的管理员与升级接口版本
+// 节点不支持历史状态查询时快照字段留空，不影响记录本身
+func (l *Listener) newContractHistory(log types.Log, eventType models.ContractEventType) *models.ContractHistory {
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+	defer cancel()
+
+	history := &models.ContractHistory{
+		ChainID:         l.chainID,
+		OptTime:         time.Now(),
+		ContractAddress: log.Address.Hex(),
+		EventType:       eventType,
+		BlockNumber:     log.BlockNumber,
+		TxHash:          log.TxHash.Hex(),
+		LogIndex:        log.Index,
+	}
+
+	if blockTime, err := l.headers.BlockTime(ctx, log.BlockNumber); err != nil {
+		logger.Log.Warn().Err(err).Uint64("block", log.BlockNumber).Msg("获取区块时间失败")
+	} else {
+		history.BlockTime = time.Unix(int64(blockTime), 0)
+	}
+
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(log.BlockNumber)}
+	if admin, err := l.caller.Admin(opts); err != nil {
+		logger.Log.Warn().Err(err).Uint64("block", log.BlockNumber).Msg("快照合约管理员失败")
+	} else {
+		history.Admin = admin.Hex()
+	}
+	if version, err := l.caller.UPGRADEINTERFACEVERSION(opts); err != nil {
+		logger.Log.Warn().Err(err).Uint64("block", log.BlockNumber).Msg("快照合约升级接口版本失败")
+	} else {
+		history.InterfaceVersion = version
+	}
+
+	return history
+}
@@ -2,65 +2,135 @@ package NFTAuction
 
 import (
 	"context"
-	"strings"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog/log"
 	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/abiregistry"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/headercache"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/rpcpool"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
 	"github.com/ydh2333/NFTAuction-project/utils/logger"
 	"golang.org/x/sync/errgroup"
 )
 
 type Listener struct {
-	client       *ethclient.Client
-	abi          abi.ABI
+	chainID      uint64 // 监听的链ID，写入每条记录
+	client       chainclient.ChainClient
+	headers      *headercache.Cache // 区块头缓存（与同一客户端上的其他监听器共享）
+	abi          *abi.ABI
+	registry     *abiregistry.Registry       // 按区块选择ABI版本解析日志
+	caller       *contracts.NFTAuctionCaller // 只读合约调用（管理员、接口版本快照）
 	contractAddr common.Address
 	startBlock   uint64
+	resumeFrom   uint64 // 订阅建立后先补齐该区块起的日志（回填结束区块+1），0表示不补齐
 	pollInterval int64
+
+	reconcileCursor repository.AuctionCursor // 核对无人出价过期拍卖的分页位置，查完一轮后从头开始
 }
 
 // 初始化监听器
 func NewListener(cfg *config.BlockchainConfig) (*Listener, error) {
-	// 连接以太坊RPC
-	client, err := ethclient.Dial(cfg.WSRpcEndpoint)
+	// 连接以太坊RPC（可配置多个WebSocket节点，订阅断开时自动切换）
+	client, err := rpcpool.Dial(cfg.PoolName("ws"), cfg.WSEndpoints(), cfg.RPCPool)
 	if err != nil {
 		return nil, err
 	}
+	return NewListenerWithClient(cfg, client)
+}
+
+// NewListenerWithClient 使用已建立的链上客户端初始化当前部署的监听器（如chainclient.Fake、进程内模拟链）
+func NewListenerWithClient(cfg *config.BlockchainConfig, client chainclient.ChainClient) (*Listener, error) {
+	return NewDeploymentListener(cfg, cfg.AuctionDeployments()[0], client)
+}
+
+// NewListenersWithClient 为链上每个拍卖合约部署（当前部署与仍需索引的旧部署）各初始化一个监听器
+func NewListenersWithClient(cfg *config.BlockchainConfig, client chainclient.ChainClient) ([]*Listener, error) {
+	var listeners []*Listener
+	for _, deployment := range cfg.AuctionDeployments() {
+		listener, err := NewDeploymentListener(cfg, deployment, client)
+		if err != nil {
+			return nil, logger.WrapError(err, "初始化拍卖合约%s的监听器失败", deployment.Address)
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
 
-	// 解析ABI
-	parsedABI, err := abi.JSON(strings.NewReader(AuctionContractABI))
+// NewDeploymentListener 初始化指定拍卖合约部署的监听器（使用该部署的起始区块与ABI版本）
+func NewDeploymentListener(cfg *config.BlockchainConfig, deployment config.AuctionContractConfig, client chainclient.ChainClient) (*Listener, error) {
+	// 解析ABI（内置ABI作为兜底，配置的各版本编译产物按生效区块使用）
+	parsedABI, err := contracts.NFTAuctionMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	registry, err := abiregistry.FromConfig(parsedABI, deployment.ABIVersions)
 	if err != nil {
 		return nil, err
 	}
+
+	contractAddr := common.HexToAddress(deployment.Address)
+	caller, err := contracts.NewNFTAuctionCaller(contractAddr, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// 恢复已同步的Upgraded事件对应的ABI版本生效区块（重启且不回填时，升级后的日志仍用新ABI解析）
+	upgrades, err := repository.NewContractHistoryRepository().GetUpgrades(cfg.ChainID, contractAddr.Hex())
+	if err != nil {
+		return nil, logger.WrapError(err, "恢复拍卖合约%s的ABI版本失败", contractAddr.Hex())
+	}
+	for _, upgrade := range upgrades {
+		registry.Activate(common.HexToAddress(upgrade.Implementation), upgrade.BlockNumber)
+	}
+
 	return &Listener{
+		chainID:      cfg.ChainID,
 		client:       client,
+		headers:      headercache.For(client, cfg.ChainID, cfg.HeaderCache),
 		abi:          parsedABI,
-		contractAddr: common.HexToAddress(cfg.ContractAddr),
-		startBlock:   cfg.StartBlock,
+		registry:     registry,
+		caller:       caller,
+		contractAddr: contractAddr,
+		startBlock:   deployment.StartBlock,
 		pollInterval: int64(cfg.PollInterval),
 	}, nil
 }
 
+// ContractAddress 监听的拍卖合约地址
+func (l *Listener) ContractAddress() common.Address {
+	return l.contractAddr
+}
+
+// StartBlock 该拍卖合约部署的起始块号
+func (l *Listener) StartBlock() uint64 {
+	return l.startBlock
+}
+
+// SetResumeBlock 设置订阅建立后补齐日志的起始区块（回填结束区块+1），须在Start之前调用
+func (l *Listener) SetResumeBlock(block uint64) {
+	l.resumeFrom = block
+}
+
+// Subscriptions Start建立的日志订阅数量：全部事件共用一个订阅
+func (l *Listener) Subscriptions() int {
+	return 1
+}
+
 // 启动监听（非阻塞，后台运行）
 func (l *Listener) Start(ctx context.Context) error {
 	// 1. 创建带上下文的errgroup，用于管理多个协程
 	eg, ctx := errgroup.WithContext(ctx)
 
-	// 2. 启动3个事件监听协程（AuctionCreated/BidPlaced/AuctionEnded）
-	eg.Go(func() error {
-		return l.listenEvent(ctx, "CreateAuction", l.handleAuctionCreated)
-	})
-
-	eg.Go(func() error {
-		return l.listenEvent(ctx, "PlaceBid", l.handleBidPlaced)
-	})
-
+	// 2. 启动事件监听协程：全部事件（CreateAuction/PlaceBid/EndAuction，以及合约升级/初始化事件）共用一个订阅，
+	// 按链上顺序逐条处理；ABI按区块切换，Upgraded所在区块的全部日志（包括Upgraded之前的）都使用新ABI解析
 	eg.Go(func() error {
-		return l.listenEvent(ctx, "EndAuction", l.handleAuctionEnded)
+		return l.listenLogs(ctx)
 	})
 
 	// 3. 启动拍卖过期检查协程（兜底逻辑）
@@ -73,49 +143,109 @@ func (l *Listener) Start(ctx context.Context) error {
 	return eg.Wait()
 }
 
-// 通用事件监听逻辑
-func (l *Listener) listenEvent(ctx context.Context, eventName string, handler func(log types.Log) error) error {
-	event := l.abi.Events[eventName]
-	if event.ID == (common.Hash{}) {
-		log.Error().Str("event", eventName).Msg("事件不存在")
-		return logger.NewErrorf("事件%s不存在", eventName)
+// eventHandler 监听的事件及其处理函数
+type eventHandler struct {
+	name   string
+	handle func(log types.Log) error
+}
+
+// eventHandlers 监听的全部事件（CancelAuction不在内置ABI中，只在配置的ABI版本包含时才有事件签名）
+func (l *Listener) eventHandlers() []eventHandler {
+	return []eventHandler{
+		{"CreateAuction", l.handleAuctionCreated},
+		{"PlaceBid", l.handleBidPlaced},
+		{"EndAuction", l.handleAuctionEnded},
+		{"CancelAuction", l.handleAuctionCancelled},
+		{"Upgraded", l.handleUpgraded},
+		{"Initialized", l.handleInitialized},
 	}
+}
 
-	// 过滤条件：合约地址 + 事件签名
-	query := ethereum.FilterQuery{
-		Addresses: []common.Address{l.contractAddr},
-		Topics:    [][]common.Hash{{event.ID}},
+// HandleLog 按事件签名把单条日志分发给对应的处理函数（订阅与回放归档时按区块顺序逐条调用）
+// 非拍卖合约、未监听的事件以及因链重组被回滚的日志直接忽略
+func (l *Listener) HandleLog(log types.Log) error {
+	name := l.eventName(log)
+	if name == "" {
+		return nil
 	}
+	for _, h := range l.eventHandlers() {
+		if h.name == name {
+			return h.handle(log)
+		}
+	}
+	return nil
+}
 
+// listenLogs 订阅拍卖合约全部监听事件并按顺序处理
+func (l *Listener) listenLogs(ctx context.Context) error {
+	query := l.LogQuery()
 	logs := make(chan types.Log)
 	sub, err := l.client.SubscribeFilterLogs(ctx, query, logs)
 	if err != nil {
-		log.Error().Err(err).Str("event", eventName).Msg("订阅事件失败")
-		return logger.WrapError(err, "订阅事件%s失败", eventName)
+		log.Error().Err(err).Str("contract", l.contractAddr.Hex()).Msg("订阅拍卖合约事件失败")
+		return logger.WrapError(err, "订阅拍卖合约%s事件失败", l.contractAddr.Hex())
 	}
-	defer sub.Unsubscribe()
+	defer func() { sub.Unsubscribe() }() // 重新订阅后sub会被替换，退出时取消最新的订阅
 
-	// logger.Log.Info().Str("event", eventName).Msg("开始监听事件")
-	log.Info().Str("event", eventName).Msg("开始监听事件")
+	log.Info().Str("contract", l.contractAddr.Hex()).Msg("开始监听拍卖合约事件")
+
+	// 订阅建立后补齐回填结束之后出块的日志，期间到达的订阅日志由订阅缓存，之后跳过已补齐的区块
+	caughtUp, err := l.catchUp(ctx, query)
+	if err != nil {
+		return err
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case err := <-sub.Err():
-			log.Error().Err(err).Str("event", eventName).Msg("事件订阅出错，重试中...")
+			log.Error().Err(err).Str("contract", l.contractAddr.Hex()).Msg("事件订阅出错，重试中...")
 			// 重试订阅
 			sub.Unsubscribe()
 			sub, err = l.client.SubscribeFilterLogs(ctx, query, logs)
 			if err != nil {
-				log.Error().Err(err).Str("event", eventName).Msg("重试订阅事件失败")
-				return logger.WrapError(err, "重试订阅事件%s失败", eventName)
+				log.Error().Err(err).Str("contract", l.contractAddr.Hex()).Msg("重试订阅事件失败")
+				return logger.WrapError(err, "重试订阅拍卖合约%s事件失败", l.contractAddr.Hex())
 			}
 		case log1 := <-logs:
+			l.headers.ObserveLog(ctx, log1)
+			if log1.BlockNumber <= caughtUp && !log1.Removed {
+				continue
+			}
+			eventName := l.eventName(log1)
+			// 链重组回滚的日志不重复处理，以重组后规范链上的日志为准
+			if log1.Removed {
+				log.Warn().Str("event", eventName).Str("tx_hash", log1.TxHash.Hex()).Uint64("block", log1.BlockNumber).Msg("事件因链重组被回滚，跳过")
+				continue
+			}
 			log.Info().Str("event", eventName).Str("tx_hash", log1.TxHash.Hex()).Msg("收到事件")
-			if err := handler(log1); err != nil {
+			if err := l.HandleLog(log1); err != nil {
 				log.Error().Err(err).Str("event", eventName).Str("tx_hash", log1.TxHash.Hex()).Msg("处理事件失败")
 			}
 		}
 	}
 }
+
+// catchUp 按区块顺序处理resumeFrom到最新区块的日志，返回已补齐到的区块（未设置resumeFrom时返回0）
+func (l *Listener) catchUp(ctx context.Context, query ethereum.FilterQuery) (uint64, error) {
+	if l.resumeFrom == 0 {
+		return 0, nil
+	}
+	logs, head, err := chainclient.CatchUp(ctx, l.client, query, l.resumeFrom)
+	if err != nil {
+		log.Error().Err(err).Str("contract", l.contractAddr.Hex()).Uint64("from", l.resumeFrom).Msg("补齐拍卖合约事件失败")
+		return 0, logger.WrapError(err, "补齐拍卖合约%s事件失败", l.contractAddr.Hex())
+	}
+	for _, log1 := range logs {
+		if log1.Removed {
+			continue
+		}
+		l.headers.ObserveLog(ctx, log1)
+		if err := l.HandleLog(log1); err != nil {
+			log.Error().Err(err).Str("event", l.eventName(log1)).Str("tx_hash", log1.TxHash.Hex()).Msg("处理事件失败")
+		}
+	}
+	log.Info().Str("contract", l.contractAddr.Hex()).Uint64("from", l.resumeFrom).Uint64("to", head).Int("logs", len(logs)).Msg("已补齐回填后出块的拍卖合约事件")
+	return head, nil
+}
@@ -5,40 +5,49 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
 	"github.com/ydh2333/NFTAuction-project/internal/models"
 	"github.com/ydh2333/NFTAuction-project/internal/redis"
 	"github.com/ydh2333/NFTAuction-project/internal/repository"
 	"github.com/ydh2333/NFTAuction-project/utils/logger"
 )
 
+// reconcileBatchSize 每次核对链上是否已无人出价结束的拍卖数量
+const reconcileBatchSize = 50
+
 // 处理AuctionCreated事件（拍卖创建）
 func (l *Listener) handleAuctionCreated(log types.Log) error {
-	var event struct {
-		AuctionId         *big.Int
-		Seller            common.Address
-		Duration          *big.Int
-		StartPrice        *big.Int
-		StartTokenAddress common.Address
-		StartTime         *big.Int
-		NftContract       common.Address
-		NftId             *big.Int
-		OptTime           *big.Int
+	auction, err := l.parseAuctionCreated(log)
+	if err != nil {
+		return err
+	}
+
+	// 保存拍卖数据
+	auctionRepository := repository.NewAuctionRepository()
+	if err := auctionRepository.Create(auction); err != nil {
+		return logger.WrapError(err, "保存拍卖数据失败")
 	}
 
-	// 解析事件数据
-	if err := l.abi.UnpackIntoInterface(&event, "CreateAuction", log.Data); err != nil {
-		return logger.WrapError(err, "解析CreateAuction事件失败")
+	logger.Log.Info().Str("auction", auction.Key().String()).Msg("同步拍卖创建事件成功")
+	return nil
+}
+
+// parseAuctionCreated 解析CreateAuction事件为拍卖记录
+func (l *Listener) parseAuctionCreated(log types.Log) (*models.Auction, error) {
+	// 解析事件数据（含索引字段）
+	event := new(contracts.NFTAuctionCreateAuction)
+	if err := l.registry.UnpackLog(event, "CreateAuction", log); err != nil {
+		return nil, logger.WrapError(err, "解析CreateAuction事件失败")
 	}
 
-	// 解析索引字段（topics）
-	event.AuctionId = new(big.Int).SetBytes(log.Topics[1].Bytes())
 	EndTime := new(big.Int).Add(event.StartTime, event.Duration)
 
-	// 存入数据库
-	auction := &models.Auction{
+	return &models.Auction{
+		ChainID:           l.chainID,
+		ContractAddress:   l.contractAddr.Hex(),
 		ID:                event.AuctionId.Uint64(),
 		CreatorAddress:    event.Seller.Hex(),
 		Duration:          time.Duration(event.Duration.Uint64()) * time.Second,
@@ -49,42 +58,15 @@ func (l *Listener) handleAuctionCreated(log types.Log) error {
 		NFTContract:       event.NftContract.Hex(),
 		NFTTokenID:        uint(event.NftId.Uint64()),
 		OptTime:           time.Unix(int64(event.OptTime.Uint64()), 0),
-	}
-
-	// 保存拍卖数据
-	auctionRepository := repository.NewAuctionRepository()
-	if err := auctionRepository.Create(auction); err != nil {
-		return logger.WrapError(err, "保存拍卖数据失败")
-	}
-
-	logger.Log.Info().Uint64("auction_id", auction.ID).Msg("同步拍卖创建事件成功")
-	return nil
+	}, nil
 }
 
 // 处理BidPlaced事件（出价）
 func (l *Listener) handleBidPlaced(log types.Log) error {
-	var event struct {
-		AuctionId    *big.Int
-		Bidder       common.Address
-		Amount       *big.Int
-		TokenAddress common.Address
-		OptTime      *big.Int
-	}
-
-	if err := l.abi.UnpackIntoInterface(&event, "PlaceBid", log.Data); err != nil {
-		return logger.WrapError(err, "解析PlaceBid事件失败")
-	}
-
-	// 解析索引字段
-	event.AuctionId = new(big.Int).SetBytes(log.Topics[1].Bytes())
-
 	// 1. 保存出价记录
-	bid := &models.Bid{
-		AuctionID:     event.AuctionId.Uint64(),
-		BidderAddress: event.Bidder.Hex(),
-		Amount:        event.Amount.Uint64(),
-		TokenAddress:  event.TokenAddress.Hex(),
-		OptTime:       time.Unix(int64(event.OptTime.Uint64()), 0),
+	bid, err := l.parseBidPlaced(log)
+	if err != nil {
+		return err
 	}
 
 	// 2. 创建拍卖表记录，更新拍卖的当前最高价和出价者，二者需要保持数据一致性
@@ -98,7 +80,7 @@ func (l *Listener) handleBidPlaced(log types.Log) error {
 		return logger.WrapError(err, "保存出价记录失败")
 	}
 	auctionRepository := repository.NewAuctionRepositoryWithTx(tx)
-	if err := auctionRepository.UpdateCurrentPrice(uint(bid.AuctionID), bid.Amount, bid.BidderAddress, bid.TokenAddress); err != nil {
+	if err := auctionRepository.UpdateCurrentPrice(bid.AuctionKey(), bid.Amount, bid.BidderAddress, bid.TokenAddress); err != nil {
 		return logger.WrapError(err, "更新拍卖的当前最高价和出价者失败")
 	}
 
@@ -110,49 +92,58 @@ func (l *Listener) handleBidPlaced(log types.Log) error {
 	// 3. 出价成功，更新redis拍卖热度
 	// 采用异步执行，不阻塞拍卖服务，同时也让出价事件处理更快
 	go func() {
-		if err := redis.IncrAuctionHot(bid.AuctionID); err != nil {
+		if err := redis.IncrAuctionHot(bid.AuctionKey()); err != nil {
 			logger.Log.Error().Err(err).Msg("增加拍卖热度失败")
 		} else {
-			logger.Log.Info().Uint64("auction_id", bid.AuctionID).Msg("增加拍卖热度成功")
+			logger.Log.Info().Str("auction", bid.AuctionKey().String()).Msg("增加拍卖热度成功")
 		}
 	}()
 
-	logger.Log.Info().Uint64("auction_id", bid.AuctionID).Str("bidder", bid.BidderAddress).Msg("同步出价事件成功")
+	logger.Log.Info().Str("auction", bid.AuctionKey().String()).Str("bidder", bid.BidderAddress).Msg("同步出价事件成功")
 	return nil
 }
 
-// 处理AuctionEnded事件（拍卖结束）
-func (l *Listener) handleAuctionEnded(log types.Log) error {
-	var event struct {
-		AuctionId    *big.Int
-		Winner       common.Address
-		Amount       *big.Int
-		TokenAddress common.Address
-		OptTime      *big.Int
+// parseBidPlaced 解析PlaceBid事件为出价记录
+func (l *Listener) parseBidPlaced(log types.Log) (*models.Bid, error) {
+	event := new(contracts.NFTAuctionPlaceBid)
+	if err := l.registry.UnpackLog(event, "PlaceBid", log); err != nil {
+		return nil, logger.WrapError(err, "解析PlaceBid事件失败")
 	}
 
-	if err := l.abi.UnpackIntoInterface(&event, "EndAuction", log.Data); err != nil {
-		return logger.WrapError(err, "解析EndAuction事件失败")
-	}
+	txHash, logIndex := log.TxHash.Hex(), log.Index
+	return &models.Bid{
+		ChainID:         l.chainID,
+		ContractAddress: l.contractAddr.Hex(),
+		AuctionID:       event.AuctionId.Uint64(),
+		BidderAddress:   event.Bidder.Hex(),
+		Amount:          event.Amount.Uint64(),
+		TokenAddress:    event.TokenAddress.Hex(),
+		OptTime:         time.Unix(int64(event.OptTime.Uint64()), 0),
+		TxHash:          &txHash,
+		LogIndex:        &logIndex,
+	}, nil
+}
 
-	event.AuctionId = new(big.Int).SetBytes(log.Topics[1].Bytes())
-	auctionID := uint(event.AuctionId.Uint64())
+// 处理AuctionEnded事件（拍卖结束），无人出价（获胜者为零地址）的拍卖视为取消
+func (l *Listener) handleAuctionEnded(log types.Log) error {
+	key, event, err := l.parseAuctionEnded(log)
+	if err != nil {
+		return err
+	}
+	at := time.Unix(int64(event.OptTime.Uint64()), 0)
+	if event.Winner == (common.Address{}) {
+		return l.cancelAuction(key, eventTransition(log, models.AuctionStatusCancelled, models.AuctionCauseNoBidEnded, at))
+	}
 
+	// 美元价值与NFT转移在开启事务前查询，避免持有行锁等待节点响应
+	s := l.fetchSettlement(context.Background(), log, event)
 	tx := repository.DB.Begin()
 	if tx.Error != nil {
 		return logger.WrapError(tx.Error, "开启事务失败")
 	}
-	// 更新拍卖状态
-	auctionRepository := repository.NewAuctionRepositoryWithTx(tx)
-	auctionStatus := models.AuctionStatusEnded
-	if err := auctionRepository.UpdateStatus(auctionID, auctionStatus); err != nil {
-		return logger.WrapError(err, "更新拍卖状态失败")
-	}
-
-	// 更新拍卖的当前最高价和出价者
-	bidRepository := repository.NewBidRepositoryWithTx(tx)
-	if err := bidRepository.MarkWinningBid(auctionID); err != nil {
-		return logger.WrapError(err, "标记获胜竞拍失败")
+	if err := l.endAuction(tx, log, key, event, s); err != nil {
+		tx.Rollback()
+		return err
 	}
 
 	// 所有操作成功，提交事务
@@ -161,17 +152,117 @@ func (l *Listener) handleAuctionEnded(log types.Log) error {
 	}
 
 	// 拍卖结束，从热度排行中删除拍卖
-	if err := redis.DelAuctionHot(uint64(auctionID)); err != nil {
+	if err := redis.DelAuctionHot(key); err != nil {
 		logger.Log.Error().Err(err).Msg("从热度排行中删除拍卖失败")
 	}
 
-	logger.Log.Info().Uint64("auction_id", uint64(auctionID)).Str("status", string(auctionStatus)).Msg("同步拍卖结束事件成功")
+	logger.Log.Info().Str("auction", key.String()).Str("status", string(models.AuctionStatusEnded)).Msg("同步拍卖结束事件成功")
 	return nil
 }
 
-// 定期检查进行中、过期拍卖
-func (l *Listener) checkAuctionExpiry(ctx context.Context) error {
+// parseAuctionEnded 解析EndAuction事件，返回结束的拍卖标识
+func (l *Listener) parseAuctionEnded(log types.Log) (models.AuctionKey, *contracts.NFTAuctionEndAuction, error) {
+	event := new(contracts.NFTAuctionEndAuction)
+	if err := l.registry.UnpackLog(event, "EndAuction", log); err != nil {
+		return models.AuctionKey{}, nil, logger.WrapError(err, "解析EndAuction事件失败")
+	}
+	return models.AuctionKey{ChainID: l.chainID, Contract: l.contractAddr.Hex(), ID: event.AuctionId.Uint64()}, event, nil
+}
+
+// 处理CancelAuction事件（拍卖取消）
+func (l *Listener) handleAuctionCancelled(log types.Log) error {
+	key, at, err := l.parseAuctionCancelled(log)
+	if err != nil {
+		return err
+	}
+	return l.cancelAuction(key, eventTransition(log, models.AuctionStatusCancelled, models.AuctionCauseCancelEvent, at))
+}
 
+// eventTransition 由事件日志触发的状态迁移
+func eventTransition(log types.Log, to models.AuctionStatus, cause models.AuctionStatusCause, at time.Time) models.AuctionTransition {
+	return models.AuctionTransition{To: to, Cause: cause, BlockNumber: log.BlockNumber, TxHash: log.TxHash.Hex(), At: at}
+}
+
+// parseAuctionCancelled 解析CancelAuction事件，返回取消的拍卖标识与取消时间
+// 内置ABI没有该事件，字段以配置的ABI版本为准：须包含auctionId，optTime缺失时使用区块时间
+func (l *Listener) parseAuctionCancelled(log types.Log) (models.AuctionKey, time.Time, error) {
+	values := make(map[string]interface{})
+	if err := l.registry.UnpackLogIntoMap(values, "CancelAuction", log); err != nil {
+		return models.AuctionKey{}, time.Time{}, logger.WrapError(err, "解析CancelAuction事件失败")
+	}
+	auctionID, ok := values["auctionId"].(*big.Int)
+	if !ok {
+		return models.AuctionKey{}, time.Time{}, logger.NewErrorf("CancelAuction事件缺少auctionId")
+	}
+	key := models.AuctionKey{ChainID: l.chainID, Contract: l.contractAddr.Hex(), ID: auctionID.Uint64()}
+	if optTime, ok := values["optTime"].(*big.Int); ok {
+		return key, time.Unix(int64(optTime.Uint64()), 0), nil
+	}
+	return key, l.blockTime(log), nil
+}
+
+// cancelAuction 取消拍卖并从热度排行中删除，当前状态不允许取消（如已成交）时跳过
+func (l *Listener) cancelAuction(key models.AuctionKey, t models.AuctionTransition) error {
+	applied, err := repository.NewAuctionRepository().Cancel(key, t)
+	if err != nil {
+		return logger.WrapError(err, "取消拍卖失败")
+	}
+	if !applied {
+		logger.Log.Warn().Str("auction", key.String()).Msg("拍卖当前状态不允许取消，跳过")
+		return nil
+	}
+
+	if err := redis.DelAuctionHot(key); err != nil {
+		logger.Log.Error().Err(err).Msg("从热度排行中删除拍卖失败")
+	}
+
+	logger.Log.Info().Str("auction", key.String()).Str("status", string(models.AuctionStatusCancelled)).Msg("同步拍卖取消成功")
+	return nil
+}
+
+// blockTime 日志所在区块的时间，获取失败时使用当前时间
+func (l *Listener) blockTime(log types.Log) time.Time {
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+	defer cancel()
+	blockTime, err := l.headers.BlockTime(ctx, log.BlockNumber)
+	if err != nil {
+		logger.Log.Warn().Err(err).Uint64("block", log.BlockNumber).Msg("获取区块时间失败")
+		return time.Now()
+	}
+	return time.Unix(int64(blockTime), 0)
+}
+
+// reconcileCancelled 兜底：合约没有取消事件或事件未同步时，核对无人出价的过期拍卖在auctions()视图中的状态，
+// 链上已结束且没有出价者的拍卖视为已取消；每次核对一页，依次轮转全部待核对的拍卖
+func (l *Listener) reconcileCancelled(ctx context.Context) {
+	auctions, err := repository.NewAuctionRepository().GetExpiredWithoutBids(l.chainID, l.contractAddr.Hex(), time.Now(), l.reconcileCursor, reconcileBatchSize)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("查询无人出价的过期拍卖失败")
+		return
+	}
+	if len(auctions) < reconcileBatchSize {
+		l.reconcileCursor = repository.AuctionCursor{}
+	} else {
+		l.reconcileCursor = repository.CursorOf(auctions[len(auctions)-1])
+	}
+	for _, auction := range auctions {
+		onchain, err := l.caller.Auctions(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(auction.ID))
+		if err != nil {
+			logger.Log.Warn().Err(err).Str("auction", auction.Key().String()).Msg("查询链上拍卖状态失败")
+			continue
+		}
+		if !onchain.Ended || onchain.HighestBidder != (common.Address{}) {
+			continue
+		}
+		t := models.AuctionTransition{To: models.AuctionStatusCancelled, Cause: models.AuctionCauseNoBidEnded, At: time.Now()}
+		if err := l.cancelAuction(auction.Key(), t); err != nil {
+			logger.Log.Error().Err(err).Str("auction", auction.Key().String()).Msg("取消拍卖失败")
+		}
+	}
+}
+
+// 定期检查进行中、过期拍卖（只处理本监听器所属合约部署的拍卖）
+func (l *Listener) checkAuctionExpiry(ctx context.Context) error {
 	ticker := time.NewTicker(time.Duration(l.pollInterval) * time.Second)
 	defer ticker.Stop()
 
@@ -180,28 +271,52 @@ func (l *Listener) checkAuctionExpiry(ctx context.Context) error {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
-			// 获取当前时间戳（秒）
-			currentTime := time.Now().Unix()
-
-			// 更新：开始时间 < 当前时间 → 进行中
-			result := repository.DB.Model(&models.Auction{}).
-				Where("status = ? AND start_time < FROM_UNIXTIME(?)", "pending", currentTime).
-				Update("status", "active")
+			auctionRepository := repository.NewAuctionRepository()
+			contract := l.contractAddr.Hex()
+
+			// 开始时间 < 当前时间 → 进行中
+			l.advanceByTime(models.AuctionStatusActive, models.AuctionCauseStarted, func(now time.Time) ([]*models.Auction, error) {
+				return auctionRepository.GetPendingStarted(l.chainID, contract, now, reconcileBatchSize)
+			})
+			// 进行中且结束时间 < 当前时间 → 已结束（流拍或等待EndAuction事件结算）
+			l.advanceByTime(models.AuctionStatusEnded, models.AuctionCauseExpired, func(now time.Time) ([]*models.Auction, error) {
+				return auctionRepository.GetActiveExpired(l.chainID, contract, now, reconcileBatchSize)
+			})
+
+			l.reconcileCancelled(ctx)
+		}
+	}
+}
 
-			if result.Error != nil {
-				log.Error().Err(result.Error).Msg("检查过期拍卖失败")
+// advanceByTime 按时间迁移拍卖状态：分批查询到期的拍卖并逐个经状态机迁移到to
+// 迁移前事件已把拍卖推进到后续状态时状态机拒绝迁移，不会回退
+func (l *Listener) advanceByTime(to models.AuctionStatus, cause models.AuctionStatusCause, due func(now time.Time) ([]*models.Auction, error)) {
+	auctionRepository := repository.NewAuctionRepository()
+	now := time.Now()
+	count := 0
+	for {
+		auctions, err := due(now)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("检查过期拍卖失败")
+			return
+		}
+		progressed := false
+		for _, auction := range auctions {
+			applied, err := auctionRepository.TransitionStatus(auction.Key(), models.AuctionTransition{To: to, Cause: cause, At: now})
+			if err != nil {
+				logger.Log.Error().Err(err).Str("auction", auction.Key().String()).Msg("更新拍卖状态失败")
+				continue
 			}
-
-			// 更新：进行中且结束时间 < 当前时间 → 流拍
-			result = repository.DB.Model(&models.Auction{}).
-				Where("status = ? AND end_time < FROM_UNIXTIME(?)", "active", currentTime).
-				Update("status", "ended")
-
-			if result.Error != nil {
-				logger.Log.Error().Err(result.Error).Msg("检查过期拍卖失败")
-			} else if result.RowsAffected > 0 {
-				logger.Log.Info().Int64("count", result.RowsAffected).Msg("更新过期流拍拍卖")
+			if applied {
+				progressed = true
+				count++
 			}
 		}
+		if len(auctions) < reconcileBatchSize || !progressed {
+			break
+		}
+	}
+	if count > 0 {
+		logger.Log.Info().Uint64("chain_id", l.chainID).Str("contract", l.contractAddr.Hex()).Str("status", string(to)).Int("count", count).Msg("按时间更新拍卖状态")
 	}
 }
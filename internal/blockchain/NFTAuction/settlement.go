@@ -0,0 +1,174 @@
+package NFTAuction
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/utils/logger"
+	"gorm.io/gorm"
+)
+
+// settlement 结算事件在事务外查询的链上数据
+type settlement struct {
+	usdValue    *string     // 结算区块的成交美元价值，查询失败时为空
+	transfers   []types.Log // 结算交易中拍卖合约转给获胜者的ERC721 Transfer事件
+	transferErr error       // 查询Transfer事件失败的原因
+}
+
+// logPosition 日志在链上的位置（交易哈希+日志序号）
+type logPosition struct {
+	txHash common.Hash
+	index  uint
+}
+
+// Settlements 一段日志中各EndAuction事件在事务外查询的链上数据（FetchSettlements构造，传给ApplyLogs）
+type Settlements map[logPosition]*settlement
+
+// FetchSettlements 在写入事务之前查询logs中有获胜者的EndAuction事件的成交美元价值与NFT转移
+// 按日志的事件签名选择ABI解析，本段内尚未同步的合约升级不影响解析；解析失败的日志跳过，由ApplyLogs报错
+func (l *Listener) FetchSettlements(ctx context.Context, logs []types.Log) Settlements {
+	settlements := make(Settlements)
+	for _, log := range logs {
+		if l.eventName(log) != "EndAuction" {
+			continue
+		}
+		event := new(contracts.NFTAuctionEndAuction)
+		if err := l.registry.UnpackLogBySignature(event, "EndAuction", log); err != nil || event.Winner == (common.Address{}) {
+			continue
+		}
+		settlements[logPosition{log.TxHash, log.Index}] = l.fetchSettlement(ctx, log, event)
+	}
+	return settlements
+}
+
+// fetchSettlement 查询结算区块的美元价值与结算交易中拍卖合约转给获胜者的Transfer事件
+// 节点不支持历史状态查询或查询失败时美元价值留空、NFT转移视为未确认，不影响结算本身
+func (l *Listener) fetchSettlement(ctx context.Context, log types.Log, event *contracts.NFTAuctionEndAuction) *settlement {
+	ctx, cancel := context.WithTimeout(ctx, snapshotTimeout)
+	defer cancel()
+
+	s := new(settlement)
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(log.BlockNumber)}
+	if value, err := l.caller.CalculateValue(opts, event.Amount, event.TokenAddress); err != nil {
+		logger.Log.Warn().Err(err).Uint64("auction_id", event.AuctionId.Uint64()).Uint64("block", log.BlockNumber).Msg("查询成交美元价值失败")
+	} else {
+		usd := value.String()
+		s.usdValue = &usd
+	}
+	s.transfers, s.transferErr = l.findWinnerTransfers(ctx, log, event.Winner)
+	return s
+}
+
+// endAuction 在事务tx中结算拍卖：迁移到已结束、标记链上已结束、按事件的获胜者与成交价标记唯一的获胜出价并保存成交结果
+// 过期检查已把拍卖标记为已结束时状态不变，仍需标记结算；s为事务前查询的链上数据（为空时美元价值与NFT转移留空）
+func (l *Listener) endAuction(tx *gorm.DB, log types.Log, key models.AuctionKey, event *contracts.NFTAuctionEndAuction, s *settlement) error {
+	at := time.Unix(int64(event.OptTime.Uint64()), 0)
+	auctionRepository := repository.NewAuctionRepositoryWithTx(tx)
+	if _, err := auctionRepository.TransitionStatus(key, eventTransition(log, models.AuctionStatusEnded, models.AuctionCauseEndEvent, at)); err != nil {
+		return logger.WrapError(err, "更新拍卖状态失败")
+	}
+	if err := auctionRepository.MarkSettled(key); err != nil {
+		return logger.WrapError(err, "标记拍卖已结束失败")
+	}
+
+	winningBid, err := repository.NewBidRepositoryWithTx(tx).MarkWinningBid(key, event.Winner.Hex(), event.Amount.Uint64(), event.TokenAddress.Hex())
+	if err != nil {
+		return logger.WrapError(err, "标记获胜竞拍失败")
+	}
+	if winningBid == nil {
+		logger.Log.Warn().Str("auction", key.String()).Str("winner", event.Winner.Hex()).Msg("未找到与结算事件一致的出价记录")
+	}
+
+	auction, err := auctionRepository.GetByID(key)
+	if err != nil {
+		return logger.WrapError(err, "查询结算的拍卖失败")
+	}
+	result := newAuctionResult(log, auction, event, at, s)
+	if winningBid != nil {
+		result.WinningBidID = &winningBid.ID
+	}
+	if err := repository.NewAuctionResultRepositoryWithTx(tx).Save(result); err != nil {
+		return logger.WrapError(err, "保存拍卖成交结果失败")
+	}
+	return nil
+}
+
+// newAuctionResult 构造拍卖成交结果，从事务前查询的Transfer事件中找出拍卖NFT的转移
+func newAuctionResult(log types.Log, auction *models.Auction, event *contracts.NFTAuctionEndAuction, at time.Time, s *settlement) *models.AuctionResult {
+	result := &models.AuctionResult{
+		OptTime:          time.Now(),
+		ChainID:          auction.ChainID,
+		ContractAddress:  auction.ContractAddress,
+		AuctionID:        auction.ID,
+		SellerAddress:    auction.CreatorAddress,
+		WinnerAddress:    event.Winner.Hex(),
+		FinalPrice:       event.Amount.Uint64(),
+		TokenAddress:     event.TokenAddress.Hex(),
+		SettlementTxHash: log.TxHash.Hex(),
+		SettlementBlock:  log.BlockNumber,
+		SettledAt:        at,
+	}
+	if s == nil {
+		logger.Log.Warn().Str("auction", auction.Key().String()).Msg("缺少结算交易的链上数据，美元价值与NFT转移留空")
+		return result
+	}
+	result.USDValue = s.usdValue
+
+	if s.transferErr != nil {
+		logger.Log.Warn().Err(s.transferErr).Str("auction", auction.Key().String()).Msg("查询结算交易的NFT转移失败")
+		return result
+	}
+	nftContract := common.HexToAddress(auction.NFTContract)
+	tokenID := common.BigToHash(new(big.Int).SetUint64(uint64(auction.NFTTokenID)))
+	for _, lg := range s.transfers {
+		if lg.Address == nftContract && len(lg.Topics) == 4 && lg.Topics[3] == tokenID {
+			index := lg.Index
+			result.NFTTransferred = true
+			result.NFTTransferLogIndex = &index
+			return result
+		}
+	}
+	logger.Log.Warn().Str("auction", auction.Key().String()).Str("tx", log.TxHash.Hex()).Msg("结算交易中未找到NFT转给获胜者的Transfer事件")
+	return result
+}
+
+// findWinnerTransfers 查询结算交易中从拍卖合约转给获胜者的ERC721 Transfer事件（不限NFT合约，写入时再按拍卖的NFT匹配）
+func (l *Listener) findWinnerTransfers(ctx context.Context, log types.Log, winner common.Address) ([]types.Log, error) {
+	erc721ABI, err := contracts.ERC721MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	transfer, ok := erc721ABI.Events["Transfer"]
+	if !ok {
+		return nil, logger.NewErrorf("ABI中未找到Transfer事件")
+	}
+
+	block := new(big.Int).SetUint64(log.BlockNumber)
+	logs, err := l.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: block,
+		ToBlock:   block,
+		Topics: [][]common.Hash{
+			{transfer.ID},
+			{common.BytesToHash(l.contractAddr.Bytes())},
+			{common.BytesToHash(winner.Bytes())},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var transfers []types.Log
+	for _, lg := range logs {
+		if lg.TxHash == log.TxHash && !lg.Removed {
+			transfers = append(transfers, lg)
+		}
+	}
+	return transfers, nil
+}
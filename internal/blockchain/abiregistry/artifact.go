@@ -0,0 +1,81 @@
+package abiregistry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ydh2333/NFTAuction-project/config"
+)
+
+// artifact Hardhat/Foundry编译产物中与ABI相关的字段
+// Hardhat: {"contractName": "...", "abi": [...], "bytecode": "0x..."}
+// Foundry: {"abi": [...], "bytecode": {"object": "0x..."}, ...}
+type artifact struct {
+	ContractName string          `json:"contractName"`
+	ABI          json.RawMessage `json:"abi"`
+}
+
+// LoadArtifact 从编译产物文件加载ABI，同时兼容纯ABI数组文件
+func LoadArtifact(path string) (*abi.ABI, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取合约产物%s失败: %w", path, err)
+	}
+	return ParseArtifact(data)
+}
+
+// ParseArtifact 解析编译产物内容中的ABI
+func ParseArtifact(data []byte) (*abi.ABI, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("合约产物内容为空")
+	}
+
+	abiJSON := data
+	if data[0] == '{' {
+		var a artifact
+		if err := json.Unmarshal(data, &a); err != nil {
+			return nil, fmt.Errorf("解析合约产物失败: %w", err)
+		}
+		if len(a.ABI) == 0 {
+			return nil, fmt.Errorf("合约产物中缺少abi字段")
+		}
+		abiJSON = a.ABI
+	}
+
+	parsed, err := abi.JSON(bytes.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("解析ABI失败: %w", err)
+	}
+	return &parsed, nil
+}
+
+// FromConfig 根据配置加载各版本编译产物并构建注册表
+func FromConfig(defaultABI *abi.ABI, versions []config.ABIVersionConfig) (*Registry, error) {
+	registry := NewRegistry(defaultABI)
+	for _, v := range versions {
+		if !common.IsHexAddress(v.Implementation) {
+			return nil, fmt.Errorf("实现合约地址%q格式错误", v.Implementation)
+		}
+		contractABI, err := LoadArtifact(v.Artifact)
+		if err != nil {
+			return nil, err
+		}
+		implementation := common.HexToAddress(v.Implementation)
+		registry.Register(implementation, contractABI)
+		registry.Activate(implementation, v.FromBlock)
+	}
+	return registry, nil
+}
+
+// LoadABI 加载单一版本ABI，路径为空时返回defaultABI
+func LoadABI(path string, defaultABI *abi.ABI) (*abi.ABI, error) {
+	if path == "" {
+		return defaultABI, nil
+	}
+	return LoadArtifact(path)
+}
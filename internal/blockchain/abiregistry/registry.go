@@ -0,0 +1,190 @@
+// Package abiregistry 合约ABI版本注册表
+//
+// 可升级合约（UUPS）每次升级都可能改变事件结构。注册表按实现合约地址保存各版本ABI，
+// 并记录每个实现合约生效的起始区块，解析日志时使用该区块生效的ABI。
+package abiregistry
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// activation 实现合约在某个区块开始生效
+type activation struct {
+	implementation common.Address
+	fromBlock      uint64
+}
+
+// Registry ABI版本注册表（并发安全）
+type Registry struct {
+	mu          sync.RWMutex
+	defaultABI  *abi.ABI                    // 未匹配到任何版本时使用的ABI
+	versions    map[common.Address]*abi.ABI // 实现合约地址 → ABI
+	activations []activation                // 按生效区块升序排列
+}
+
+// NewRegistry 创建注册表，defaultABI为兜底ABI（一般为类型化绑定内置的ABI）
+func NewRegistry(defaultABI *abi.ABI) *Registry {
+	return &Registry{
+		defaultABI: defaultABI,
+		versions:   make(map[common.Address]*abi.ABI),
+	}
+}
+
+// Register 注册实现合约对应的ABI
+func (r *Registry) Register(implementation common.Address, contractABI *abi.ABI) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions[implementation] = contractABI
+}
+
+// Activate 记录实现合约从fromBlock开始生效（配置或Upgraded事件触发）
+func (r *Registry) Activate(implementation common.Address, fromBlock uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, a := range r.activations {
+		if a.implementation == implementation && a.fromBlock == fromBlock {
+			return
+		}
+	}
+	r.activations = append(r.activations, activation{implementation: implementation, fromBlock: fromBlock})
+	sort.SliceStable(r.activations, func(i, j int) bool {
+		return r.activations[i].fromBlock < r.activations[j].fromBlock
+	})
+}
+
+// Snapshot 保存当前的生效记录，返回的函数把注册表恢复到保存时的状态（批量写入的事务回滚时撤销期间的升级）
+func (r *Registry) Snapshot() (restore func()) {
+	r.mu.RLock()
+	saved := slices.Clone(r.activations)
+	r.mu.RUnlock()
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.activations = saved
+	}
+}
+
+// ImplementationAt 返回在指定区块生效的实现合约地址
+func (r *Registry) ImplementationAt(block uint64) (common.Address, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.activations) - 1; i >= 0; i-- {
+		if r.activations[i].fromBlock <= block {
+			return r.activations[i].implementation, true
+		}
+	}
+	return common.Address{}, false
+}
+
+// ABIAt 返回在指定区块生效的ABI，实现合约未注册ABI时使用兜底ABI
+func (r *Registry) ABIAt(block uint64) *abi.ABI {
+	impl, ok := r.ImplementationAt(block)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if ok {
+		if contractABI, found := r.versions[impl]; found {
+			return contractABI
+		}
+	}
+	return r.defaultABI
+}
+
+// EventIDs 返回所有版本中同名事件的签名（去重），用于订阅过滤
+func (r *Registry) EventIDs(eventName string) []common.Hash {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[common.Hash]bool)
+	var ids []common.Hash
+	add := func(contractABI *abi.ABI) {
+		if contractABI == nil {
+			return
+		}
+		if event, ok := contractABI.Events[eventName]; ok && !seen[event.ID] {
+			seen[event.ID] = true
+			ids = append(ids, event.ID)
+		}
+	}
+
+	add(r.defaultABI)
+	for _, contractABI := range r.versions {
+		add(contractABI)
+	}
+	return ids
+}
+
+// UnpackLog 使用日志所在区块生效的ABI解析事件（含索引字段）
+func (r *Registry) UnpackLog(out interface{}, eventName string, log types.Log) error {
+	contract, err := r.boundAt(eventName, log)
+	if err != nil {
+		return err
+	}
+	return contract.UnpackLog(out, eventName, log)
+}
+
+// UnpackLogIntoMap 同UnpackLog，按ABI参数名解析到map（内置ABI中没有、字段随版本变化的事件使用）
+func (r *Registry) UnpackLogIntoMap(out map[string]interface{}, eventName string, log types.Log) error {
+	contract, err := r.boundAt(eventName, log)
+	if err != nil {
+		return err
+	}
+	return contract.UnpackLogIntoMap(out, eventName, log)
+}
+
+// UnpackLogBySignature 同UnpackLog，区块生效的ABI中同名事件的签名与日志不一致时，改用签名一致的已注册版本解析
+// （在同步到合约升级事件之前预先解析升级后的日志时使用）
+func (r *Registry) UnpackLogBySignature(out interface{}, eventName string, log types.Log) error {
+	contractABI := r.ABIAt(log.BlockNumber)
+	if len(log.Topics) > 0 {
+		if event, ok := contractABI.Events[eventName]; !ok || event.ID != log.Topics[0] {
+			if matched := r.abiWithEvent(eventName, log.Topics[0]); matched != nil {
+				contractABI = matched
+			}
+		}
+	}
+	if _, ok := contractABI.Events[eventName]; !ok {
+		return fmt.Errorf("区块%d生效的ABI中不存在事件%s", log.BlockNumber, eventName)
+	}
+	return bind.NewBoundContract(log.Address, *contractABI, nil, nil, nil).UnpackLog(out, eventName, log)
+}
+
+// abiWithEvent 返回同名事件签名为id的ABI版本，不存在时返回nil
+func (r *Registry) abiWithEvent(eventName string, id common.Hash) *abi.ABI {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, contractABI := range append([]*abi.ABI{r.defaultABI}, slices.Collect(maps.Values(r.versions))...) {
+		if contractABI == nil {
+			continue
+		}
+		if event, ok := contractABI.Events[eventName]; ok && event.ID == id {
+			return contractABI
+		}
+	}
+	return nil
+}
+
+// boundAt 使用日志所在区块生效的ABI绑定合约，该ABI中不存在事件时返回错误
+func (r *Registry) boundAt(eventName string, log types.Log) (*bind.BoundContract, error) {
+	contractABI := r.ABIAt(log.BlockNumber)
+	if contractABI == nil {
+		return nil, fmt.Errorf("区块%d没有可用的ABI", log.BlockNumber)
+	}
+	if _, ok := contractABI.Events[eventName]; !ok {
+		return nil, fmt.Errorf("区块%d生效的ABI中不存在事件%s", log.BlockNumber, eventName)
+	}
+	return bind.NewBoundContract(log.Address, *contractABI, nil, nil, nil), nil
+}
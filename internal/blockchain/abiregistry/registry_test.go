@@ -0,0 +1,136 @@
+package abiregistry
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func mustABI(t *testing.T, event string) *abi.ABI {
+	t.Helper()
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"event","name":"` + event + `","inputs":[]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &parsed
+}
+
+func TestRegistryImplementationAt(t *testing.T) {
+	var (
+		defaultABI = mustABI(t, "Default")
+		v1ABI      = mustABI(t, "V1")
+		v2ABI      = mustABI(t, "V2")
+		v1         = common.HexToAddress("0x1")
+		v2         = common.HexToAddress("0x2")
+		unknown    = common.HexToAddress("0x3") // 升级到未配置ABI的实现合约
+	)
+
+	registry := NewRegistry(defaultABI)
+	registry.Register(v1, v1ABI)
+	registry.Register(v2, v2ABI)
+	// 乱序激活，重复激活忽略
+	registry.Activate(v2, 200)
+	registry.Activate(v1, 100)
+	registry.Activate(unknown, 300)
+	registry.Activate(v1, 100)
+
+	tests := []struct {
+		name     string
+		block    uint64
+		wantImpl common.Address
+		wantOK   bool
+		wantABI  *abi.ABI
+	}{
+		{"首个版本生效前", 99, common.Address{}, false, defaultABI},
+		{"v1生效区块", 100, v1, true, v1ABI},
+		{"v1生效期间", 199, v1, true, v1ABI},
+		{"v2生效区块", 200, v2, true, v2ABI},
+		{"未注册ABI的实现使用兜底ABI", 300, unknown, true, defaultABI},
+		{"最新区块", 1 << 40, unknown, true, defaultABI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			impl, ok := registry.ImplementationAt(tt.block)
+			if impl != tt.wantImpl || ok != tt.wantOK {
+				t.Errorf("ImplementationAt(%d) = %s, %v, want %s, %v", tt.block, impl.Hex(), ok, tt.wantImpl.Hex(), tt.wantOK)
+			}
+			if got := registry.ABIAt(tt.block); got != tt.wantABI {
+				t.Errorf("ABIAt(%d) returned wrong ABI", tt.block)
+			}
+		})
+	}
+}
+
+func TestRegistryEventIDs(t *testing.T) {
+	defaultABI := mustABI(t, "Shared")
+	registry := NewRegistry(defaultABI)
+	registry.Register(common.HexToAddress("0x1"), mustABI(t, "Shared"))
+	registry.Register(common.HexToAddress("0x2"), mustABI(t, "Other"))
+
+	if ids := registry.EventIDs("Shared"); len(ids) != 1 {
+		t.Errorf("EventIDs(Shared) = %d ids, want 1 (deduplicated)", len(ids))
+	}
+	if ids := registry.EventIDs("Other"); len(ids) != 1 {
+		t.Errorf("EventIDs(Other) = %d ids, want 1", len(ids))
+	}
+	if ids := registry.EventIDs("Missing"); len(ids) != 0 {
+		t.Errorf("EventIDs(Missing) = %d ids, want 0", len(ids))
+	}
+}
+
+func TestRegistrySnapshot(t *testing.T) {
+	v1 := common.HexToAddress("0x1")
+	v2 := common.HexToAddress("0x2")
+	registry := NewRegistry(mustABI(t, "Default"))
+	registry.Activate(v1, 100)
+
+	restore := registry.Snapshot()
+	registry.Activate(v2, 200)
+	if impl, _ := registry.ImplementationAt(200); impl != v2 {
+		t.Fatalf("ImplementationAt(200) = %s, want %s", impl.Hex(), v2.Hex())
+	}
+
+	restore()
+	if impl, ok := registry.ImplementationAt(200); impl != v1 || !ok {
+		t.Errorf("after restore ImplementationAt(200) = %s, %v, want %s", impl.Hex(), ok, v1.Hex())
+	}
+}
+
+func TestRegistryUnpackLogBySignature(t *testing.T) {
+	parse := func(t *testing.T, inputs string) *abi.ABI {
+		t.Helper()
+		parsed, err := abi.JSON(strings.NewReader(`[{"type":"event","name":"Ended","inputs":[` + inputs + `]}]`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &parsed
+	}
+	v1 := parse(t, `{"name":"amount","type":"uint256","indexed":false}`)
+	v2 := parse(t, `{"name":"amount","type":"uint256","indexed":false},{"name":"fee","type":"uint256","indexed":false}`)
+	impl := common.HexToAddress("0x2")
+
+	registry := NewRegistry(v1)
+	registry.Register(impl, v2)
+	// 升级尚未同步（未激活v2），日志已按v2的事件签名发出
+	data, err := v2.Events["Ended"].Inputs.Pack(big.NewInt(7), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := types.Log{BlockNumber: 10, Topics: []common.Hash{v2.Events["Ended"].ID}, Data: data}
+
+	out := make(map[string]interface{})
+	if err := registry.UnpackLogIntoMap(out, "Ended", log); err == nil {
+		t.Error("UnpackLog with the block's ABI succeeded, want signature mismatch")
+	}
+	var event struct{ Amount, Fee *big.Int }
+	if err := registry.UnpackLogBySignature(&event, "Ended", log); err != nil {
+		t.Fatalf("UnpackLogBySignature: %v", err)
+	}
+	if event.Amount.Int64() != 7 || event.Fee.Int64() != 1 {
+		t.Errorf("UnpackLogBySignature = %v/%v, want 7/1", event.Amount, event.Fee)
+	}
+}
@@ -0,0 +1,147 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/txmanager"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+)
+
+// 管理操作的交易用途
+const (
+	PurposeSetPriceFeed = "setPriceETHFeed"
+	PurposeUpgrade      = "upgradeToAndCall"
+)
+
+// ERC1967ImplementationSlot ERC1967实现合约存储槽，UUPS实现合约的proxiableUUID必须返回该值
+var ERC1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// PriceFeedInfo 预言机最新一轮报价
+type PriceFeedInfo struct {
+	Description     string    `json:"description"`
+	Decimals        uint8     `json:"decimals"`
+	RoundID         string    `json:"round_id"`
+	Answer          string    `json:"answer"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	AnsweredInRound string    `json:"answered_in_round"`
+}
+
+// ValidatePriceFeed 校验Chainlink预言机：有合约代码、latestRoundData报价为正、未过期且为完整轮次
+func (c *AuctionContract) ValidatePriceFeed(ctx context.Context, feed common.Address, maxStaleness time.Duration) (*PriceFeedInfo, error) {
+	if err := c.requireCode(ctx, feed); err != nil {
+		return nil, err
+	}
+	aggregator, err := contracts.NewAggregatorV3Caller(feed, c.client)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	decimals, err := aggregator.Decimals(opts)
+	if err != nil {
+		return nil, fmt.Errorf("调用decimals失败: %w", c.decoder.Wrap(err))
+	}
+	description, err := aggregator.Description(opts)
+	if err != nil {
+		return nil, fmt.Errorf("调用description失败: %w", c.decoder.Wrap(err))
+	}
+	round, err := aggregator.LatestRoundData(opts)
+	if err != nil {
+		return nil, fmt.Errorf("调用latestRoundData失败: %w", c.decoder.Wrap(err))
+	}
+
+	info := &PriceFeedInfo{
+		Description:     description,
+		Decimals:        decimals,
+		RoundID:         round.RoundId.String(),
+		Answer:          round.Answer.String(),
+		UpdatedAt:       time.Unix(round.UpdatedAt.Int64(), 0),
+		AnsweredInRound: round.AnsweredInRound.String(),
+	}
+	if round.Answer.Sign() <= 0 {
+		return info, fmt.Errorf("预言机报价%s不为正", info.Answer)
+	}
+	if round.UpdatedAt.Sign() == 0 {
+		return info, errors.New("预言机当前轮次未完成")
+	}
+	if round.AnsweredInRound.Cmp(round.RoundId) < 0 {
+		return info, fmt.Errorf("预言机报价来自旧轮次%s", info.AnsweredInRound)
+	}
+	if maxStaleness > 0 && time.Since(info.UpdatedAt) > maxStaleness {
+		return info, fmt.Errorf("预言机报价已%s未更新", time.Since(info.UpdatedAt).Truncate(time.Second))
+	}
+	return info, nil
+}
+
+// ValidateImplementation 校验新实现合约：有合约代码且proxiableUUID返回ERC1967实现槽
+func (c *AuctionContract) ValidateImplementation(ctx context.Context, implementation common.Address) error {
+	if err := c.requireCode(ctx, implementation); err != nil {
+		return err
+	}
+	impl, err := contracts.NewNFTAuctionCaller(implementation, c.client)
+	if err != nil {
+		return err
+	}
+	uuid, err := impl.ProxiableUUID(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("调用proxiableUUID失败: %w", c.decoder.Wrap(err))
+	}
+	if common.Hash(uuid) != ERC1967ImplementationSlot {
+		return fmt.Errorf("proxiableUUID返回%s，与ERC1967实现槽不一致", common.Hash(uuid).Hex())
+	}
+	return nil
+}
+
+// ContractAdmin 查询拍卖合约管理员
+func (c *AuctionContract) ContractAdmin(ctx context.Context) (common.Address, error) {
+	return c.auction.Admin(&bind.CallOpts{Context: ctx})
+}
+
+// SetPriceETHFeed 以后端签名地址发送setPriceETHFeed交易（不等待上链）
+func (c *AuctionContract) SetPriceETHFeed(ctx context.Context, token, feed common.Address) (*models.Transaction, error) {
+	data, err := packCalldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.auction.SetPriceETHFeed(opts, token, feed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.send(ctx, txmanager.TxRequest{Purpose: PurposeSetPriceFeed, To: c.address, Data: data})
+}
+
+// UpgradeToAndCall 以后端签名地址发送upgradeToAndCall交易（不等待上链）
+func (c *AuctionContract) UpgradeToAndCall(ctx context.Context, implementation common.Address, callData []byte) (*models.Transaction, error) {
+	data, err := packCalldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.auction.UpgradeToAndCall(opts, implementation, callData)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.send(ctx, txmanager.TxRequest{Purpose: PurposeUpgrade, To: c.address, Data: data})
+}
+
+// send 发送交易，调用方须是合约管理员
+func (c *AuctionContract) send(ctx context.Context, req txmanager.TxRequest) (*models.Transaction, error) {
+	if c.txManager == nil {
+		return nil, ErrNoSigner
+	}
+	return c.txManager.Send(ctx, req)
+}
+
+// requireCode 校验地址上部署了合约
+func (c *AuctionContract) requireCode(ctx context.Context, address common.Address) error {
+	code, err := c.client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("地址%s上没有合约代码", address.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+)
+
+// ErrReplayOnly 归档只支持按顺序回放，不支持订阅
+var ErrReplayOnly = errors.New("日志归档不支持订阅，请使用Logs按顺序回放")
+
+// 单行归档的最大长度
+const maxLineSize = 16 << 20
+
+// Archive 已加载的日志归档，作为ChainClient应答回放期间的查询
+type Archive struct {
+	logs    []types.Log              // 规范链日志，按区块号、日志序号排序
+	headers map[uint64]*types.Header // 区块号 → 最后记录的区块头
+	calls   map[string][]Call        // 合约地址+调用数据 → 记录的调用
+	head    uint64
+}
+
+var _ chainclient.ChainClient = (*Archive)(nil)
+
+// logKey 日志在链上的唯一标识
+type logKey struct {
+	block common.Hash
+	index uint
+}
+
+// Load 读取JSONL归档
+// 同一日志重复记录时只保留一条；之后以Removed=true记录的日志（链重组回滚）从结果中剔除。
+// 最后一行不完整（写入时进程中断）时忽略该行。
+func Load(path string) (*Archive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开日志归档失败: %w", err)
+	}
+	defer file.Close()
+
+	a := &Archive{
+		headers: make(map[uint64]*types.Header),
+		calls:   make(map[string][]Call),
+	}
+	logs := make(map[logKey]types.Log)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	var (
+		lineNo  int
+		pending error // 解析失败的行，只有之后还有内容时才视为错误
+	)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if pending != nil {
+			return nil, pending
+		}
+
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			pending = fmt.Errorf("解析日志归档第%d行失败: %w", lineNo, err)
+			continue
+		}
+		switch entry.Kind {
+		case KindLog:
+			if entry.Log == nil {
+				continue
+			}
+			key := logKey{block: entry.Log.BlockHash, index: entry.Log.Index}
+			if entry.Log.Removed {
+				delete(logs, key)
+			} else {
+				logs[key] = *entry.Log
+			}
+		case KindHeader:
+			if entry.Header != nil && entry.Header.Number != nil {
+				a.headers[entry.Header.Number.Uint64()] = entry.Header
+			}
+		case KindCall:
+			if entry.Call != nil {
+				key := callKey(entry.Call.To, entry.Call.Data)
+				a.calls[key] = append(a.calls[key], *entry.Call)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取日志归档失败: %w", err)
+	}
+	if pending != nil {
+		log.Warn().Err(pending).Msg("忽略日志归档末尾不完整的行")
+	}
+
+	a.logs = make([]types.Log, 0, len(logs))
+	for _, lg := range logs {
+		a.logs = append(a.logs, lg)
+		a.head = max(a.head, lg.BlockNumber)
+	}
+	sort.Slice(a.logs, func(i, j int) bool {
+		if a.logs[i].BlockNumber != a.logs[j].BlockNumber {
+			return a.logs[i].BlockNumber < a.logs[j].BlockNumber
+		}
+		return a.logs[i].Index < a.logs[j].Index
+	})
+	for number := range a.headers {
+		a.head = max(a.head, number)
+	}
+	return a, nil
+}
+
+// Logs 按链上顺序返回归档中的全部日志
+func (a *Archive) Logs() []types.Log {
+	return a.logs
+}
+
+// SubscribeFilterLogs 归档不支持订阅
+func (a *Archive) SubscribeFilterLogs(context.Context, ethereum.FilterQuery, chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, ErrReplayOnly
+}
+
+// FilterLogs 按区块范围、地址与主题过滤归档日志
+func (a *Archive) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	result := make([]types.Log, 0)
+	for _, lg := range a.logs {
+		if q.BlockHash != nil {
+			if lg.BlockHash != *q.BlockHash {
+				continue
+			}
+		} else if (q.FromBlock != nil && lg.BlockNumber < q.FromBlock.Uint64()) ||
+			(q.ToBlock != nil && q.ToBlock.Sign() >= 0 && lg.BlockNumber > q.ToBlock.Uint64()) {
+			continue
+		}
+		if chainclient.MatchLog(q, lg) {
+			result = append(result, lg)
+		}
+	}
+	return result, nil
+}
+
+// HeaderByNumber 返回记录的区块头，number为空时返回最高的已记录区块
+func (a *Archive) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	n := a.head
+	if number != nil && number.Sign() >= 0 {
+		n = number.Uint64()
+	}
+	header, ok := a.headers[n]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return types.CopyHeader(header), nil
+}
+
+// BlockNumber 归档中的最高区块号
+func (a *Archive) BlockNumber(context.Context) (uint64, error) {
+	return a.head, nil
+}
+
+// CallContract 返回记录的调用结果：优先匹配相同区块，否则取最后一次记录
+func (a *Archive) CallContract(_ context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if msg.To == nil {
+		return nil, errors.New("日志归档不支持合约创建调用")
+	}
+	calls := a.calls[callKey(*msg.To, msg.Data)]
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("日志归档中没有合约%s的该调用记录", msg.To.Hex())
+	}
+
+	call := calls[len(calls)-1]
+	if blockNumber != nil {
+		for _, c := range calls {
+			if c.Block != nil && c.Block.ToInt().Cmp(blockNumber) == 0 {
+				call = c
+			}
+		}
+	}
+	if call.Error != "" {
+		return nil, errors.New(call.Error)
+	}
+	return call.Result, nil
+}
+
+// CodeAt 有调用记录的地址视为有合约代码
+func (a *Archive) CodeAt(_ context.Context, contract common.Address, _ *big.Int) ([]byte, error) {
+	prefix := strings.ToLower(contract.Hex()) + ":"
+	for key := range a.calls {
+		if strings.HasPrefix(key, prefix) {
+			return []byte{0x00}, nil
+		}
+	}
+	return nil, nil
+}
+
+func callKey(to common.Address, data []byte) string {
+	return fmt.Sprintf("%s:%x", strings.ToLower(to.Hex()), data)
+}
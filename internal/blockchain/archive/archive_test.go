@@ -0,0 +1,253 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+)
+
+var (
+	hashA = common.HexToHash("0xa1")
+	hashB = common.HexToHash("0xb2")
+	hashC = common.HexToHash("0xc3") // 重组后同高度的新区块
+)
+
+func logAt(number uint64, block common.Hash, index uint) *types.Log {
+	return &types.Log{BlockNumber: number, BlockHash: block, Index: index, Topics: []common.Hash{}}
+}
+
+func removed(lg *types.Log) *types.Log {
+	cp := *lg
+	cp.Removed = true
+	return &cp
+}
+
+func headerAt(number uint64, extra string) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: big.NewInt(0), Extra: []byte(extra)}
+}
+
+// line 编码归档中的一行
+func line(t *testing.T, entry Entry) string {
+	t.Helper()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// writeArchive 把各行写入临时归档文件
+func writeArchive(t *testing.T, lines []string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "archive.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	type logID struct {
+		number uint64
+		block  common.Hash
+		index  uint
+	}
+	a0, a1, b0 := logAt(1, hashA, 0), logAt(1, hashA, 1), logAt(2, hashB, 0)
+	logLine := func(t *testing.T, lg *types.Log) string { return line(t, Entry{Kind: KindLog, Log: lg}) }
+	headerLine := func(t *testing.T, h *types.Header) string { return line(t, Entry{Kind: KindHeader, Header: h}) }
+
+	tests := []struct {
+		name       string
+		lines      func(t *testing.T) []string
+		wantErr    bool
+		wantLogs   []logID
+		wantHead   uint64
+		wantHeader map[uint64]string // 区块号 → 期望的区块头Extra
+	}{
+		{
+			name: "重复记录只保留一条",
+			lines: func(t *testing.T) []string {
+				return []string{logLine(t, a0), logLine(t, a1), logLine(t, a0), logLine(t, a1)}
+			},
+			wantLogs: []logID{{1, hashA, 0}, {1, hashA, 1}},
+			wantHead: 1,
+		},
+		{
+			name: "按区块号与日志序号排序",
+			lines: func(t *testing.T) []string {
+				return []string{logLine(t, b0), logLine(t, a1), logLine(t, a0)}
+			},
+			wantLogs: []logID{{1, hashA, 0}, {1, hashA, 1}, {2, hashB, 0}},
+			wantHead: 2,
+		},
+		{
+			name: "之后标记回滚的日志剔除",
+			lines: func(t *testing.T) []string {
+				return []string{logLine(t, a0), logLine(t, b0), logLine(t, removed(b0))}
+			},
+			wantLogs: []logID{{1, hashA, 0}},
+			wantHead: 1,
+		},
+		{
+			name: "回滚后同高度新区块的日志保留",
+			lines: func(t *testing.T) []string {
+				return []string{logLine(t, b0), logLine(t, removed(b0)), logLine(t, logAt(2, hashC, 0))}
+			},
+			wantLogs: []logID{{2, hashC, 0}},
+			wantHead: 2,
+		},
+		{
+			name: "末尾不完整的行忽略",
+			lines: func(t *testing.T) []string {
+				full := logLine(t, b0)
+				return []string{logLine(t, a0), full[:len(full)/2]}
+			},
+			wantLogs: []logID{{1, hashA, 0}},
+			wantHead: 1,
+		},
+		{
+			name: "中间行损坏报错",
+			lines: func(t *testing.T) []string {
+				return []string{logLine(t, a0), `{"kind":"log","log":`, logLine(t, b0)}
+			},
+			wantErr: true,
+		},
+		{
+			name: "同高度区块头以最后记录为准",
+			lines: func(t *testing.T) []string {
+				return []string{headerLine(t, headerAt(2, "old")), logLine(t, a0), headerLine(t, headerAt(2, "new")), headerLine(t, headerAt(1, "one"))}
+			},
+			wantLogs:   []logID{{1, hashA, 0}},
+			wantHead:   2,
+			wantHeader: map[uint64]string{1: "one", 2: "new"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Load(writeArchive(t, tt.lines(t)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() err = %v，期望出错 %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got := make([]logID, 0, len(a.Logs()))
+			for _, lg := range a.Logs() {
+				got = append(got, logID{lg.BlockNumber, lg.BlockHash, lg.Index})
+			}
+			if len(got) != len(tt.wantLogs) {
+				t.Fatalf("日志 = %v，期望 %v", got, tt.wantLogs)
+			}
+			for i := range got {
+				if got[i] != tt.wantLogs[i] {
+					t.Fatalf("日志 = %v，期望 %v", got, tt.wantLogs)
+				}
+			}
+
+			if head, _ := a.BlockNumber(context.Background()); head != tt.wantHead {
+				t.Errorf("最高区块 = %d，期望 %d", head, tt.wantHead)
+			}
+			for number, extra := range tt.wantHeader {
+				header, err := a.HeaderByNumber(context.Background(), new(big.Int).SetUint64(number))
+				if err != nil {
+					t.Fatalf("区块头%d: %v", number, err)
+				}
+				if string(header.Extra) != extra {
+					t.Errorf("区块头%d = %q，期望 %q", number, header.Extra, extra)
+				}
+			}
+		})
+	}
+}
+
+// TestRecorderRoundTrip 经Recorder订阅与查询到的日志、区块头和合约调用，Load后按相同结果应答，链重组回滚的日志被剔除
+func TestRecorderRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	fake := chainclient.NewFake()
+	contract := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	fake.HandleCall(contract, func(data []byte, _ uint64) ([]byte, error) {
+		return append([]byte{0x01}, data...), nil
+	})
+
+	path := filepath.Join(t.TempDir(), "archive.jsonl")
+	recorder, err := NewRecorder(fake, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan types.Log, 16)
+	sub, err := recorder.SubscribeFilterLogs(ctx, ethereum.FilterQuery{Addresses: []common.Address{contract}}, ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emit := types.Log{Address: contract, Topics: []common.Hash{common.HexToHash("0x01")}}
+	_, first := fake.Mine(emit, emit)
+	_, reorged := fake.Mine(emit)
+	if _, err := fake.Reorg(1, []types.Log{emit}); err != nil {
+		t.Fatal(err)
+	}
+	// 订阅依次收到：区块1的2条、区块2的1条、回滚的1条、替换区块的1条
+	for i := 0; i < 5; i++ {
+		<-ch
+	}
+	sub.Unsubscribe()
+
+	// 回填查询与订阅重复记录同一批日志
+	if _, err := recorder.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(0), Addresses: []common.Address{contract}}); err != nil {
+		t.Fatal(err)
+	}
+	callData := []byte{0xde, 0xad}
+	want, err := recorder.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: callData}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonical, err := fake.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Logs()) != len(canonical) {
+		t.Fatalf("回放日志%d条，期望规范链上的%d条", len(a.Logs()), len(canonical))
+	}
+	for i, lg := range a.Logs() {
+		if lg.BlockHash != canonical[i].BlockHash || lg.Index != canonical[i].Index || lg.TxHash != canonical[i].TxHash {
+			t.Errorf("第%d条日志 = %d/%s，期望 %d/%s", i, lg.BlockNumber, lg.BlockHash.Hex(), canonical[i].BlockNumber, canonical[i].BlockHash.Hex())
+		}
+		if lg.BlockHash == reorged[0].BlockHash {
+			t.Error("回滚区块的日志仍在回放结果中")
+		}
+	}
+
+	header, err := a.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Hash() != fake.Head().Hash() {
+		t.Errorf("最新区块头 = %s，期望重组后的 %s", header.Hash().Hex(), fake.Head().Hash().Hex())
+	}
+	if header, err := a.HeaderByNumber(ctx, big.NewInt(1)); err != nil || header.Hash() != first[0].BlockHash {
+		t.Errorf("区块1的区块头 = %v, %v", header, err)
+	}
+
+	got, err := a.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: callData}, nil)
+	if err != nil || string(got) != string(want) {
+		t.Errorf("回放调用 = %x, %v，期望 %x", got, err, want)
+	}
+}
@@ -0,0 +1,213 @@
+// Package archive 链上日志归档与回放
+//
+// Recorder包装监听器使用的ChainClient，把收到的每条原始日志、查询过的区块头以及只读合约调用
+// 追加写入JSONL归档（每行一条Entry）；Load读取归档得到Archive，它本身也是ChainClient，
+// 回放时监听器的区块时间、tokenURI、合约快照等查询都由归档应答，不再访问RPC节点。
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+)
+
+// 归档条目类型
+const (
+	KindLog    = "log"
+	KindHeader = "header"
+	KindCall   = "call"
+)
+
+// 已记录区块头的去重集合上限，超过后清空重新计数
+const maxRecordedHeaders = 10000
+
+// headerTimeout 记录日志所在区块头的查询超时
+const headerTimeout = 10 * time.Second
+
+// Entry 归档中的一行
+type Entry struct {
+	Kind       string        `json:"kind"`
+	RecordedAt time.Time     `json:"recorded_at"`
+	Log        *types.Log    `json:"log,omitempty"`
+	Header     *types.Header `json:"header,omitempty"`
+	Call       *Call         `json:"call,omitempty"`
+}
+
+// Call 一次只读合约调用及其结果
+type Call struct {
+	To     common.Address `json:"to"`
+	Data   hexutil.Bytes  `json:"data"`
+	Block  *hexutil.Big   `json:"block,omitempty"` // 为空表示最新区块
+	Result hexutil.Bytes  `json:"result,omitempty"`
+	Error  string         `json:"error,omitempty"` // 调用失败（如revert）时的错误信息
+}
+
+// Recorder 记录日志的ChainClient包装
+type Recorder struct {
+	chainclient.ChainClient
+
+	mu      sync.Mutex
+	file    *os.File
+	enc     *json.Encoder
+	headers map[uint64]common.Hash // 已记录的区块头
+}
+
+var _ chainclient.ChainClient = (*Recorder)(nil)
+
+// NewRecorder 以追加方式打开归档文件并包装client
+func NewRecorder(client chainclient.ChainClient, path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("打开日志归档失败: %w", err)
+	}
+	return &Recorder{
+		ChainClient: client,
+		file:        file,
+		enc:         json.NewEncoder(file),
+		headers:     make(map[uint64]common.Hash),
+	}, nil
+}
+
+// Close 关闭归档文件
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// SubscribeFilterLogs 订阅日志，每条日志在转发给订阅方之前写入归档（连同所在区块头）
+func (r *Recorder) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	inner := make(chan types.Log)
+	sub, err := r.ChainClient.SubscribeFilterLogs(ctx, q, inner)
+	if err != nil {
+		return nil, err
+	}
+
+	rs := &recordingSubscription{Subscription: sub, quit: make(chan struct{})}
+	go func() {
+		for {
+			select {
+			case lg := <-inner:
+				r.recordLog(lg)
+				select {
+				case ch <- lg:
+				case <-rs.quit:
+					return
+				}
+			case <-rs.quit:
+				return
+			}
+		}
+	}()
+	return rs, nil
+}
+
+// FilterLogs 查询日志并写入归档
+func (r *Recorder) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	logs, err := r.ChainClient.FilterLogs(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	for _, lg := range logs {
+		r.recordLog(lg)
+	}
+	return logs, nil
+}
+
+// HeaderByNumber 查询区块头并写入归档
+func (r *Recorder) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := r.ChainClient.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	r.recordHeader(header)
+	return header, nil
+}
+
+// CallContract 执行只读调用并写入归档（调用失败也记录，回放时返回相同错误）
+func (r *Recorder) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	result, err := r.ChainClient.CallContract(ctx, msg, blockNumber)
+	if msg.To != nil {
+		call := &Call{To: *msg.To, Data: msg.Data, Result: result}
+		if blockNumber != nil {
+			call.Block = (*hexutil.Big)(new(big.Int).Set(blockNumber))
+		}
+		if err != nil {
+			call.Error = err.Error()
+		}
+		r.write(Entry{Kind: KindCall, Call: call})
+	}
+	return result, err
+}
+
+// recordLog 写入日志，日志所在区块头尚未记录时一并查询记录
+func (r *Recorder) recordLog(lg types.Log) {
+	r.write(Entry{Kind: KindLog, Log: &lg})
+
+	r.mu.Lock()
+	_, recorded := r.headers[lg.BlockNumber]
+	r.mu.Unlock()
+	if recorded || lg.Removed {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), headerTimeout)
+	defer cancel()
+	if _, err := r.HeaderByNumber(ctx, new(big.Int).SetUint64(lg.BlockNumber)); err != nil {
+		log.Warn().Err(err).Uint64("block", lg.BlockNumber).Msg("归档区块头失败")
+	}
+}
+
+// recordHeader 写入区块头（同一区块哈希只记录一次）
+func (r *Recorder) recordHeader(header *types.Header) {
+	if header == nil || header.Number == nil {
+		return
+	}
+	number, hash := header.Number.Uint64(), header.Hash()
+
+	r.mu.Lock()
+	if r.headers[number] == hash {
+		r.mu.Unlock()
+		return
+	}
+	if len(r.headers) >= maxRecordedHeaders {
+		r.headers = make(map[uint64]common.Hash)
+	}
+	r.headers[number] = hash
+	r.mu.Unlock()
+
+	r.write(Entry{Kind: KindHeader, Header: header})
+}
+
+// write 追加一行到归档，写入失败只记录日志，不影响监听
+func (r *Recorder) write(entry Entry) {
+	entry.RecordedAt = time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(entry); err != nil {
+		log.Error().Err(err).Str("kind", entry.Kind).Msg("写入日志归档失败")
+	}
+}
+
+// recordingSubscription 取消订阅时同时停止转发协程
+type recordingSubscription struct {
+	ethereum.Subscription
+	quit chan struct{}
+	once sync.Once
+}
+
+func (s *recordingSubscription) Unsubscribe() {
+	s.once.Do(func() { close(s.quit) })
+	s.Subscription.Unsubscribe()
+}
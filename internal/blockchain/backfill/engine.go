@@ -0,0 +1,476 @@
+// Package backfill 历史区块并行回填
+//
+// 监听器只订阅新日志，服务停机期间或StartBlock之后的历史事件需要回填。Engine把待回填的区块范围
+// 切分成若干段，由多个协程并发调用eth_getLogs拉取拍卖合约与ERC721 safeMint日志（节点提示结果过多时
+// 自动对半缩小查询范围），批量获取铸造区块的区块头并解析NFT元数据；各段按区块顺序在单个事务中
+// 批量写入NFT、拍卖与出价并保存回填进度，同一拍卖的事件顺序与逐条处理一致，中断后从进度处继续。
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/ERC721"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/NFTAuction"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/headercache"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+)
+
+// ProgressName 回填进度在sync_progress表中的名称，每条链单独记录
+func ProgressName(chainID uint64) string {
+	return fmt.Sprintf("backfill:%d", chainID)
+}
+
+// 节点因结果过多/范围过大拒绝eth_getLogs时的错误信息片段（各家节点措辞不同）
+var tooLargeHints = []string{"more than", "too many", "limit exceeded", "response size", "block range", "query returned"}
+
+// Engine 历史区块回填引擎
+type Engine struct {
+	chainID    uint64
+	client     chainclient.ChainClient
+	headers    *headercache.Cache
+	auctions   []*NFTAuction.Listener // 各拍卖合约部署的监听器
+	erc721     *ERC721.ERC721Listener
+	cfg        config.BackfillConfig
+	startBlock uint64 // 各拍卖合约部署中最早的起始块号
+
+	rangeSize atomic.Uint64 // 当前eth_getLogs查询的区块数，随节点响应自适应调整
+}
+
+// chunk 按顺序写入的一段区块范围
+type chunk struct {
+	seq         int
+	from, to    uint64
+	auctionLogs [][]types.Log            // 各拍卖合约部署的日志（与Engine.auctions一一对应），按区块号、日志序号排序
+	settlements []NFTAuction.Settlements // 各拍卖合约部署结算事件的美元价值与NFT转移（拉取时查询，写入事务内不访问节点）
+	nfts        []*models.NFT            // 该范围内铸造的NFT
+}
+
+// auctionLogCount 该段全部拍卖合约部署的日志数
+func (c *chunk) auctionLogCount() int {
+	n := 0
+	for _, logs := range c.auctionLogs {
+		n += len(logs)
+	}
+	return n
+}
+
+// NewEngine 创建回填引擎，复用监听器的日志解析（拍卖ABI版本切换、NFT元数据解析）
+// auctions为链上各拍卖合约部署的监听器，从其中最早的起始块号开始回填
+func NewEngine(cfg *config.BlockchainConfig, client chainclient.ChainClient, auctions []*NFTAuction.Listener, erc721 *ERC721.ERC721Listener) *Engine {
+	bf := cfg.Backfill
+	if bf.Workers <= 0 {
+		bf.Workers = 4
+	}
+	if bf.RangeSize == 0 {
+		bf.RangeSize = 2000
+	}
+	if bf.MinRangeSize == 0 || bf.MinRangeSize > bf.RangeSize {
+		bf.MinRangeSize = min(10, bf.RangeSize)
+	}
+	if bf.HeaderBatchSize <= 0 {
+		bf.HeaderBatchSize = 100
+	}
+
+	e := &Engine{
+		chainID:    cfg.ChainID,
+		client:     client,
+		headers:    headercache.For(client, cfg.ChainID, cfg.HeaderCache),
+		auctions:   auctions,
+		erc721:     erc721,
+		cfg:        bf,
+		startBlock: cfg.StartBlock,
+	}
+	for _, auction := range auctions {
+		e.startBlock = min(e.startBlock, auction.StartBlock())
+	}
+	e.rangeSize.Store(bf.RangeSize)
+	return e
+}
+
+// Run 回填到最新区块后返回，同时返回回填到的区块（监听器订阅后从其下一个区块补齐日志）
+// 从max(上次进度+1, StartBlock)开始，最新区块减去确认数之前的区块写入后保存进度；
+// 尚未达到确认数的区块同样写入但不保存进度，下次启动会重新拉取（已存在的记录跳过）。
+func (e *Engine) Run(ctx context.Context) (uint64, error) {
+	from := e.startBlock
+	last, ok, err := repository.NewSyncProgressRepository().Get(ProgressName(e.chainID))
+	if err != nil {
+		return 0, fmt.Errorf("查询回填进度失败: %w", err)
+	}
+	if ok && last+1 > from {
+		from = last + 1
+	}
+
+	head, err := e.client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("获取最新区块失败: %w", err)
+	}
+	if from > head {
+		log.Info().Uint64("chain_id", e.chainID).Uint64("from", from).Uint64("head", head).Msg("无需回填历史区块")
+		return from - 1, nil
+	}
+
+	var safe uint64
+	if head > e.cfg.Confirmations {
+		safe = head - e.cfg.Confirmations
+	}
+	log.Info().Uint64("chain_id", e.chainID).Uint64("from", from).Uint64("safe", safe).Uint64("head", head).Int("workers", e.cfg.Workers).Msg("开始回填历史区块")
+
+	if from <= safe {
+		if err := e.sync(ctx, from, safe, true); err != nil {
+			return 0, err
+		}
+	}
+	if tail := max(from, safe+1); tail <= head {
+		if err := e.sync(ctx, tail, head, false); err != nil {
+			return 0, err
+		}
+	}
+
+	log.Info().Uint64("chain_id", e.chainID).Uint64("head", head).Msg("历史区块回填完成")
+	return head, nil
+}
+
+// sync 并发拉取[from, to]的日志并按区块顺序写入，checkpoint为true时每段写入后保存进度
+func (e *Engine) sync(ctx context.Context, from, to uint64, checkpoint bool) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	jobs := make(chan *chunk)
+	results := make(chan *chunk)
+	// 已分发但尚未写入的段数上限，避免前面的段拉取较慢时后面的段在内存中无限堆积
+	window := make(chan struct{}, e.cfg.Workers*2)
+
+	// 1. 分发：按配置的范围切分
+	eg.Go(func() error {
+		defer close(jobs)
+		seq := 0
+		for start := from; start <= to; start += e.cfg.RangeSize {
+			c := &chunk{seq: seq, from: start, to: min(start+e.cfg.RangeSize-1, to)}
+			seq++
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			select {
+			case jobs <- c:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if c.to == to {
+				break
+			}
+		}
+		return nil
+	})
+
+	// 2. 拉取：多个协程并发
+	var wg sync.WaitGroup
+	for i := 0; i < e.cfg.Workers; i++ {
+		wg.Add(1)
+		eg.Go(func() error {
+			defer wg.Done()
+			for c := range jobs {
+				if err := e.fetch(ctx, c); err != nil {
+					return err
+				}
+				select {
+				case results <- c:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 3. 写入：按段序号依次提交
+	eg.Go(func() error {
+		pending := make(map[int]*chunk)
+		next := 0
+		for c := range results {
+			pending[c.seq] = c
+			for {
+				c, ok := pending[next]
+				if !ok {
+					break
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if err := e.apply(c, checkpoint); err != nil {
+					return err
+				}
+				delete(pending, next)
+				<-window
+				next++
+			}
+		}
+		return nil
+	})
+
+	return eg.Wait()
+}
+
+// fetch 拉取一段区块范围的拍卖日志与safeMint日志，并构造NFT记录
+func (e *Engine) fetch(ctx context.Context, c *chunk) error {
+	c.auctionLogs = make([][]types.Log, len(e.auctions))
+	c.settlements = make([]NFTAuction.Settlements, len(e.auctions))
+	for i, auction := range e.auctions {
+		// 部署之前的区块没有该合约的日志
+		if c.to < auction.StartBlock() {
+			continue
+		}
+		logs, err := e.filterLogs(ctx, auction.LogQuery(), max(c.from, auction.StartBlock()), c.to)
+		if err != nil {
+			return err
+		}
+		c.auctionLogs[i] = sortLogs(logs)
+		c.settlements[i] = auction.FetchSettlements(ctx, c.auctionLogs[i])
+	}
+	mintQuery, err := e.erc721.SafeMintQuery()
+	if err != nil {
+		return err
+	}
+	mintLogs, err := e.filterLogs(ctx, mintQuery, c.from, c.to)
+	if err != nil {
+		return err
+	}
+	mintLogs = sortLogs(mintLogs)
+
+	if len(mintLogs) > 0 {
+		blocks := make([]uint64, 0, len(mintLogs))
+		for _, lg := range mintLogs {
+			if len(blocks) == 0 || blocks[len(blocks)-1] != lg.BlockNumber {
+				blocks = append(blocks, lg.BlockNumber)
+			}
+		}
+		blockTimes, err := e.blockTimes(ctx, blocks)
+		if err != nil {
+			return err
+		}
+		for _, lg := range mintLogs {
+			// 元数据上游暂时不可用时先保存基础信息，由实时监听器稍后重新获取
+			nft, err := e.erc721.BuildNFT(ctx, lg, blockTimes[lg.BlockNumber])
+			if err != nil {
+				return fmt.Errorf("解析区块%d的safeMint事件失败: %w", lg.BlockNumber, err)
+			}
+			if nft != nil {
+				c.nfts = append(c.nfts, nft)
+			}
+		}
+	}
+
+	log.Debug().Uint64("from", c.from).Uint64("to", c.to).Int("auction_logs", c.auctionLogCount()).Int("nfts", len(c.nfts)).Msg("拉取历史区块日志")
+	return nil
+}
+
+// apply 在一个事务中写入一段区块范围：先NFT，再拍卖与出价，最后保存进度
+func (e *Engine) apply(c *chunk, checkpoint bool) error {
+	// 事务回滚时撤销本段内合约升级切换的ABI版本
+	restores := make([]func(), len(e.auctions))
+	for i, auction := range e.auctions {
+		restores[i] = auction.SaveABIVersions()
+	}
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewNFTRepositoryWithTx(tx).CreateBatch(c.nfts); err != nil {
+			return err
+		}
+		for i, auction := range e.auctions {
+			if err := auction.ApplyLogs(tx, c.auctionLogs[i], c.settlements[i]); err != nil {
+				return err
+			}
+		}
+		if checkpoint {
+			return repository.NewSyncProgressRepositoryWithTx(tx).Save(ProgressName(e.chainID), c.to)
+		}
+		return nil
+	})
+	if err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		return fmt.Errorf("写入区块%d-%d失败: %w", c.from, c.to, err)
+	}
+	log.Info().Uint64("from", c.from).Uint64("to", c.to).Int("auction_logs", c.auctionLogCount()).Int("nfts", len(c.nfts)).Msg("回填区块范围完成")
+	return nil
+}
+
+// filterLogs 按当前查询范围逐段拉取[from, to]的日志
+func (e *Engine) filterLogs(ctx context.Context, q ethereum.FilterQuery, from, to uint64) ([]types.Log, error) {
+	var logs []types.Log
+	for start := from; start <= to; {
+		end := min(start+e.rangeSize.Load()-1, to)
+		got, err := e.filterRange(ctx, q, start, end)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, got...)
+		if end == to {
+			break
+		}
+		start = end + 1
+	}
+	return logs, nil
+}
+
+// filterRange 查询[from, to]的日志，节点提示结果过多时对半拆分重试并缩小后续查询范围
+func (e *Engine) filterRange(ctx context.Context, q ethereum.FilterQuery, from, to uint64) ([]types.Log, error) {
+	q.FromBlock = new(big.Int).SetUint64(from)
+	q.ToBlock = new(big.Int).SetUint64(to)
+	logs, err := e.client.FilterLogs(ctx, q)
+	if err == nil {
+		e.grow()
+		return logs, nil
+	}
+	if from == to || !isTooLarge(err) {
+		return nil, fmt.Errorf("查询区块%d-%d的日志失败: %w", from, to, err)
+	}
+
+	e.shrink(to - from + 1)
+	mid := from + (to-from)/2
+	log.Warn().Err(err).Uint64("from", from).Uint64("to", to).Uint64("range_size", e.rangeSize.Load()).Msg("日志查询结果过多，拆分区块范围")
+	left, err := e.filterRange(ctx, q, from, mid)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.filterRange(ctx, q, mid+1, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// shrink 把查询范围缩小到失败范围的一半（不低于MinRangeSize）
+func (e *Engine) shrink(failed uint64) {
+	target := max(failed/2, e.cfg.MinRangeSize)
+	for {
+		cur := e.rangeSize.Load()
+		if cur <= target || e.rangeSize.CompareAndSwap(cur, target) {
+			return
+		}
+	}
+}
+
+// grow 查询成功后逐步恢复查询范围（每次增加1/8，不超过RangeSize）
+func (e *Engine) grow() {
+	for {
+		cur := e.rangeSize.Load()
+		if cur >= e.cfg.RangeSize {
+			return
+		}
+		next := min(cur+cur/8+1, e.cfg.RangeSize)
+		if e.rangeSize.CompareAndSwap(cur, next) {
+			return
+		}
+	}
+}
+
+// blockTimes 获取区块时间：先查区块头缓存，未命中的区块在底层支持批量请求时按批次合并，
+// 否则逐个查询；从节点获取的区块头写入缓存
+func (e *Engine) blockTimes(ctx context.Context, numbers []uint64) (map[uint64]uint64, error) {
+	times := make(map[uint64]uint64, len(numbers))
+	var blocks []uint64
+	for _, number := range numbers {
+		if h, ok := e.headers.Cached(number); ok {
+			times[number] = h.Time
+		} else {
+			blocks = append(blocks, number)
+		}
+	}
+
+	batchCall, ok := batchCaller(e.client)
+	if !ok {
+		for _, number := range blocks {
+			blockTime, err := e.headers.BlockTime(ctx, number)
+			if err != nil {
+				return nil, fmt.Errorf("获取区块%d的区块头失败: %w", number, err)
+			}
+			times[number] = blockTime
+		}
+		return times, nil
+	}
+
+	for start := 0; start < len(blocks); start += e.cfg.HeaderBatchSize {
+		batch := blocks[start:min(start+e.cfg.HeaderBatchSize, len(blocks))]
+		elems := make([]rpc.BatchElem, len(batch))
+		headers := make([]*types.Header, len(batch))
+		for i, number := range batch {
+			headers[i] = new(types.Header)
+			elems[i] = rpc.BatchElem{
+				Method: "eth_getBlockByNumber",
+				Args:   []interface{}{hexutil.EncodeUint64(number), false},
+				Result: headers[i],
+			}
+		}
+		if err := batchCall(ctx, elems); err != nil {
+			return nil, fmt.Errorf("批量获取区块头失败: %w", err)
+		}
+		for i, elem := range elems {
+			if elem.Error != nil {
+				return nil, fmt.Errorf("获取区块%d的区块头失败: %w", batch[i], elem.Error)
+			}
+			times[batch[i]] = e.headers.Observe(ctx, headers[i]).Time
+		}
+	}
+	return times, nil
+}
+
+// batchCaller 底层客户端支持JSON-RPC批量请求时返回批量调用函数（rpcpool.Pool、*ethclient.Client）
+func batchCaller(client chainclient.ChainClient) (func(context.Context, []rpc.BatchElem) error, bool) {
+	switch c := client.(type) {
+	case interface {
+		BatchCallContext(context.Context, []rpc.BatchElem) error
+	}:
+		return c.BatchCallContext, true
+	case interface{ Client() *rpc.Client }:
+		return c.Client().BatchCallContext, true
+	}
+	return nil, false
+}
+
+// isTooLarge 判断eth_getLogs错误是否因为查询结果过多或区块范围过大
+func isTooLarge(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, hint := range tooLargeHints {
+		if strings.Contains(msg, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortLogs 去掉因链重组被回滚的日志，并按区块号、日志序号排序
+func sortLogs(logs []types.Log) []types.Log {
+	kept := logs[:0]
+	for _, lg := range logs {
+		if !lg.Removed {
+			kept = append(kept, lg)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].BlockNumber != kept[j].BlockNumber {
+			return kept[i].BlockNumber < kept[j].BlockNumber
+		}
+		return kept[i].Index < kept[j].Index
+	})
+	return kept
+}
@@ -0,0 +1,249 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/ERC721"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/NFTAuction"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/simchain"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/internal/testutil"
+	"gorm.io/gorm"
+)
+
+var (
+	auctionAddr = common.HexToAddress("0x00000000000000000000000000000000000a0c71")
+	nftAddr     = common.HexToAddress("0x0000000000000000000000000000000000000721")
+	seller      = common.HexToAddress("0x1000000000000000000000000000000000000001")
+)
+
+// limitedClient 模拟限制eth_getLogs区块范围的节点：超过maxRange的查询返回结果过多错误，
+// slowFrom起始的查询延迟返回（让后面的段先拉取完成）
+type limitedClient struct {
+	*chainclient.Fake
+	maxRange uint64
+	slowFrom uint64
+	delay    time.Duration
+
+	mu      sync.Mutex
+	queries [][2]uint64 // 成功的查询范围
+}
+
+func (c *limitedClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+	if c.maxRange > 0 && to-from+1 > c.maxRange {
+		return nil, errors.New("query returned more than 10000 results")
+	}
+	if c.delay > 0 && from == c.slowFrom {
+		time.Sleep(c.delay)
+	}
+	c.mu.Lock()
+	c.queries = append(c.queries, [2]uint64{from, to})
+	c.mu.Unlock()
+	return c.Fake.FilterLogs(ctx, q)
+}
+
+// mineAuctions 每个区块创建一个拍卖，共blocks个区块
+func mineAuctions(t *testing.T, fake *chainclient.Fake, blocks int) {
+	t.Helper()
+	parsed, err := contracts.NFTAuctionMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= blocks; i++ {
+		lg, err := simchain.EncodeEvent(parsed, auctionAddr, "CreateAuction", contracts.NFTAuctionCreateAuction{
+			AuctionId:   big.NewInt(int64(i)),
+			Seller:      seller,
+			Duration:    big.NewInt(3600),
+			StartPrice:  big.NewInt(100),
+			StartTime:   big.NewInt(1),
+			NftContract: nftAddr,
+			NftId:       big.NewInt(int64(i)),
+			OptTime:     big.NewInt(1),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fake.Mine(lg)
+	}
+}
+
+// newTestEngine 在client上创建拍卖与ERC721监听器及回填引擎
+func newTestEngine(t *testing.T, client chainclient.ChainClient, bf config.BackfillConfig) *Engine {
+	t.Helper()
+	cfg := &config.BlockchainConfig{
+		ChainID:      simchain.ChainID,
+		ContractAddr: auctionAddr.Hex(),
+		StartBlock:   1,
+		Backfill:     bf,
+	}
+	auctions, err := NFTAuction.NewListenersWithClient(cfg, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	erc721, err := ERC721.NewERC721ListenerWithClient(cfg, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewEngine(cfg, client, auctions, erc721)
+}
+
+// recordProgress 按写入顺序记录保存的回填进度
+func recordProgress(t *testing.T) func() []uint64 {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		saved []uint64
+	)
+	err := repository.DB.Callback().Create().After("gorm:create").Register("test:record_progress", func(db *gorm.DB) {
+		if progress, ok := db.Statement.Dest.(*models.SyncProgress); ok && db.Error == nil {
+			mu.Lock()
+			saved = append(saved, progress.Block)
+			mu.Unlock()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func() []uint64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(saved)
+	}
+}
+
+func auctionIDs(t *testing.T) []uint64 {
+	t.Helper()
+	var ids []uint64
+	if err := repository.DB.Model(&models.Auction{}).Order("id").Pluck("id", &ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func TestShrinkGrow(t *testing.T) {
+	e := &Engine{cfg: config.BackfillConfig{RangeSize: 100, MinRangeSize: 10}}
+
+	tests := []struct {
+		name  string
+		start uint64
+		op    func()
+		want  uint64
+	}{
+		{"失败后缩小到失败范围的一半", 100, func() { e.shrink(100) }, 50},
+		{"不低于MinRangeSize", 16, func() { e.shrink(16) }, 10},
+		{"已小于目标值时不放大", 20, func() { e.shrink(100) }, 20},
+		{"成功后增加1/8", 40, e.grow, 46},
+		{"不超过RangeSize", 95, e.grow, 100},
+		{"达到RangeSize后不变", 100, e.grow, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e.rangeSize.Store(tt.start)
+			tt.op()
+			if got := e.rangeSize.Load(); got != tt.want {
+				t.Errorf("rangeSize = %d，期望 %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRunSplitsTooLargeRanges 节点拒绝大范围查询时拆分重试，全部日志写入且后续查询不超过节点限制
+func TestRunSplitsTooLargeRanges(t *testing.T) {
+	testutil.InitDB(t)
+	fake := chainclient.NewFake()
+	mineAuctions(t, fake, 40)
+	client := &limitedClient{Fake: fake, maxRange: 5}
+	engine := newTestEngine(t, client, config.BackfillConfig{Workers: 1, RangeSize: 16, MinRangeSize: 2})
+
+	head, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head != 40 {
+		t.Errorf("Run = %d，期望 40", head)
+	}
+	if ids := auctionIDs(t); len(ids) != 40 {
+		t.Errorf("写入拍卖%d个，期望 40", len(ids))
+	}
+	for _, q := range client.queries {
+		if q[1]-q[0]+1 > client.maxRange {
+			t.Errorf("成功的查询范围 %d-%d 超过节点限制", q[0], q[1])
+		}
+	}
+	if size := engine.rangeSize.Load(); size < engine.cfg.MinRangeSize || size > engine.cfg.RangeSize {
+		t.Errorf("rangeSize = %d，超出[%d, %d]", size, engine.cfg.MinRangeSize, engine.cfg.RangeSize)
+	}
+}
+
+// TestRunAppliesChunksInOrder 前面的段拉取较慢时，后面的段等待其写入后再按区块顺序写入并保存进度
+func TestRunAppliesChunksInOrder(t *testing.T) {
+	testutil.InitDB(t)
+	saved := recordProgress(t)
+	fake := chainclient.NewFake()
+	mineAuctions(t, fake, 20)
+	client := &limitedClient{Fake: fake, slowFrom: 1, delay: 100 * time.Millisecond}
+	engine := newTestEngine(t, client, config.BackfillConfig{Workers: 4, RangeSize: 5})
+
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := saved(), []uint64{5, 10, 15, 20}; !slices.Equal(got, want) {
+		t.Errorf("保存进度顺序 = %v，期望 %v", got, want)
+	}
+	if ids := auctionIDs(t); len(ids) != 20 {
+		t.Errorf("写入拍卖%d个，期望 20", len(ids))
+	}
+}
+
+// TestRunConfirmations 只保存到最新区块减去确认数的进度，之后的区块写入但下次启动重新拉取
+func TestRunConfirmations(t *testing.T) {
+	testutil.InitDB(t)
+	fake := chainclient.NewFake()
+	mineAuctions(t, fake, 10)
+	client := &limitedClient{Fake: fake}
+	bf := config.BackfillConfig{Workers: 2, RangeSize: 4, Confirmations: 3}
+	engine := newTestEngine(t, client, bf)
+
+	head, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head != 10 {
+		t.Errorf("Run = %d，期望 10", head)
+	}
+	progress, ok, err := repository.NewSyncProgressRepository().Get(ProgressName(simchain.ChainID))
+	if err != nil || !ok || progress != 7 {
+		t.Fatalf("回填进度 = %d, %v, %v，期望 7", progress, ok, err)
+	}
+	if ids := auctionIDs(t); len(ids) != 10 {
+		t.Fatalf("写入拍卖%d个，期望 10（未确认区块同样写入）", len(ids))
+	}
+
+	// 再次回填从进度之后开始，已写入的未确认区块重复拉取时跳过
+	client.queries = nil
+	if _, err := newTestEngine(t, client, bf).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range client.queries {
+		if q[0] <= 7 {
+			t.Errorf("再次回填查询了已确认的区块 %d-%d", q[0], q[1])
+		}
+	}
+	if ids := auctionIDs(t); len(ids) != 10 {
+		t.Errorf("再次回填后拍卖%d个，期望 10", len(ids))
+	}
+}
@@ -0,0 +1,47 @@
+// Package chainclient 监听器依赖的链上接口
+//
+// 监听器只需要订阅/查询日志、查询区块头与区块高度以及只读合约调用，ChainClient收窄为这些方法，
+// 生产环境传入*ethclient.Client，验证和回放时传入Fake或其他实现。
+package chainclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ChainClient 监听器使用的链上接口（*ethclient.Client已实现）
+type ChainClient interface {
+	// SubscribeFilterLogs、FilterLogs
+	ethereum.LogFilterer
+	// CallContract，以及调用返回空数据时abigen绑定用于判断合约是否存在的CodeAt
+	bind.ContractCaller
+
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+var _ ChainClient = (*ethclient.Client)(nil)
+
+// CatchUp 查询from到最新区块之间匹配q的日志，返回日志与查询时的最新区块
+// 监听器在订阅建立之后调用，补齐回填结束到订阅建立之间出块的日志；订阅收到的不晚于该区块的日志应跳过
+func CatchUp(ctx context.Context, client ChainClient, q ethereum.FilterQuery, from uint64) ([]types.Log, uint64, error) {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from > head {
+		return nil, head, nil
+	}
+	q.FromBlock = new(big.Int).SetUint64(from)
+	q.ToBlock = new(big.Int).SetUint64(head)
+	logs, err := client.FilterLogs(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, head, nil
+}
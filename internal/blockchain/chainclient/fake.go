@@ -0,0 +1,450 @@
+package chainclient
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BlockInterval Fake出块间隔（区块时间戳递增量）
+const BlockInterval = 12 * time.Second
+
+// 单个订阅未投递日志的缓冲上限
+const subscriptionBuffer = 1024
+
+// ErrSubscriptionDropped FailSubscriptions默认注入的订阅错误
+var ErrSubscriptionDropped = errors.New("模拟订阅断开")
+
+// CallHandler 合约只读调用应答，data为调用数据（含方法选择器），block为查询区块号
+type CallHandler func(data []byte, block uint64) ([]byte, error)
+
+// Fake 内存中的ChainClient实现
+//
+// 日志由调用方编码后通过Mine打包进新区块并推送给匹配的订阅；Reorg回滚最近的区块，
+// 先以Removed=true重新推送被回滚的日志，再打包替换区块；FailSubscriptions/FailNextSubscribe
+// 模拟节点断线和重新订阅失败。合约只读调用通过HandleCall/MockMethod注册应答。
+type Fake struct {
+	mu            sync.Mutex
+	headers       []*types.Header // 下标即区块号（当前规范链）
+	logs          []types.Log     // 规范链上的全部日志
+	subs          map[*fakeSubscription]struct{}
+	calls         map[common.Address]CallHandler
+	forks         uint64  // 已发生的重组次数，写入替换区块的Extra以区分区块哈希
+	subscribeErrs []error // 后续SubscribeFilterLogs依次返回的错误
+}
+
+var _ ChainClient = (*Fake)(nil)
+
+// NewFake 创建Fake，创世区块时间为当前时间
+func NewFake() *Fake {
+	return &Fake{
+		headers: []*types.Header{newHeader(nil, uint64(time.Now().Unix()), 0)},
+		subs:    make(map[*fakeSubscription]struct{}),
+		calls:   make(map[common.Address]CallHandler),
+	}
+}
+
+// Head 返回最新区块头
+func (f *Fake) Head() *types.Header {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return types.CopyHeader(f.headers[len(f.headers)-1])
+}
+
+// Mine 打包新区块：补全日志的区块号、区块哈希、交易哈希与索引后推送给匹配的订阅
+// 未设置TxHash的日志按区块号与日志序号生成交易哈希，同一交易的多条日志可预先设置相同TxHash
+func (f *Fake) Mine(logs ...types.Log) (*types.Header, []types.Log) {
+	f.mu.Lock()
+	header, mined := f.mineLocked(logs)
+	subs := f.subscriptionsLocked()
+	f.mu.Unlock()
+
+	deliver(subs, mined)
+	return header, mined
+}
+
+// Reorg 回滚最近depth个区块，并以blocks（每个元素为一个区块的日志）打包替换区块
+// 被回滚的日志按逆序以Removed=true推送给订阅，与节点在链重组时的行为一致
+func (f *Fake) Reorg(depth int, blocks ...[]types.Log) ([]*types.Header, error) {
+	f.mu.Lock()
+	if depth <= 0 || depth >= len(f.headers) {
+		f.mu.Unlock()
+		return nil, fmt.Errorf("重组深度%d超出范围（当前高度%d）", depth, len(f.headers)-1)
+	}
+	f.forks++
+	f.headers = f.headers[:len(f.headers)-depth]
+	newHead := uint64(len(f.headers) - 1)
+
+	keep := len(f.logs)
+	for keep > 0 && f.logs[keep-1].BlockNumber > newHead {
+		keep--
+	}
+	removed := make([]types.Log, 0, len(f.logs)-keep)
+	for i := len(f.logs) - 1; i >= keep; i-- {
+		lg := f.logs[i]
+		lg.Removed = true
+		removed = append(removed, lg)
+	}
+	f.logs = f.logs[:keep]
+
+	headers := make([]*types.Header, 0, len(blocks))
+	var mined []types.Log
+	for _, logs := range blocks {
+		header, blockLogs := f.mineLocked(logs)
+		headers = append(headers, header)
+		mined = append(mined, blockLogs...)
+	}
+	subs := f.subscriptionsLocked()
+	f.mu.Unlock()
+
+	deliver(subs, removed)
+	deliver(subs, mined)
+	return headers, nil
+}
+
+// FailSubscriptions 向当前全部订阅注入错误并关闭订阅（模拟节点断线），err为nil时使用ErrSubscriptionDropped
+func (f *Fake) FailSubscriptions(err error) {
+	if err == nil {
+		err = ErrSubscriptionDropped
+	}
+	f.mu.Lock()
+	subs := f.subscriptionsLocked()
+	f.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.fail(err)
+	}
+}
+
+// FailNextSubscribe 后续的SubscribeFilterLogs调用依次返回errs（模拟重新订阅失败）
+func (f *Fake) FailNextSubscribe(errs ...error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subscribeErrs = append(f.subscribeErrs, errs...)
+}
+
+// Subscriptions 当前日志订阅数量
+func (f *Fake) Subscriptions() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subs)
+}
+
+// HandleCall 注册合约只读调用应答
+func (f *Fake) HandleCall(address common.Address, handler CallHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[address] = handler
+}
+
+// MockMethod 为合约方法注册固定返回值，同一合约的其他方法保持原有应答
+func (f *Fake) MockMethod(address common.Address, contractABI *abi.ABI, method string, outputs ...interface{}) error {
+	m, ok := contractABI.Methods[method]
+	if !ok {
+		return fmt.Errorf("ABI中未找到方法%s", method)
+	}
+	ret, err := m.Outputs.Pack(outputs...)
+	if err != nil {
+		return fmt.Errorf("编码%s返回值失败: %w", method, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	next := f.calls[address]
+	f.calls[address] = func(data []byte, block uint64) ([]byte, error) {
+		if len(data) >= 4 && string(data[:4]) == string(m.ID) {
+			return ret, nil
+		}
+		if next != nil {
+			return next(data, block)
+		}
+		return nil, fmt.Errorf("合约%s未模拟方法0x%x", address.Hex(), data[:min(len(data), 4)])
+	}
+	return nil
+}
+
+// SubscribeFilterLogs 订阅此后打包的匹配日志
+func (f *Fake) SubscribeFilterLogs(_ context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.subscribeErrs) > 0 {
+		err := f.subscribeErrs[0]
+		f.subscribeErrs = f.subscribeErrs[1:]
+		return nil, err
+	}
+
+	sub := &fakeSubscription{
+		fake:  f,
+		query: q,
+		queue: make(chan types.Log, subscriptionBuffer),
+		errCh: make(chan error, 1),
+		quit:  make(chan struct{}),
+	}
+	f.subs[sub] = struct{}{}
+	go sub.forward(ch)
+	return sub, nil
+}
+
+// FilterLogs 查询规范链上的匹配日志，FromBlock为空时从创世区块开始，ToBlock为空时到最新区块
+func (f *Fake) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	head := uint64(len(f.headers) - 1)
+	from, to := uint64(0), head
+	if q.FromBlock != nil {
+		from = q.FromBlock.Uint64()
+	}
+	if q.ToBlock != nil && q.ToBlock.Sign() >= 0 {
+		to = q.ToBlock.Uint64()
+	}
+
+	result := make([]types.Log, 0)
+	for _, lg := range f.logs {
+		if q.BlockHash != nil {
+			if lg.BlockHash != *q.BlockHash {
+				continue
+			}
+		} else if lg.BlockNumber < from || lg.BlockNumber > to {
+			continue
+		}
+		if MatchLog(q, lg) {
+			result = append(result, lg)
+		}
+	}
+	return result, nil
+}
+
+// HeaderByNumber 查询区块头，number为空时返回最新区块
+func (f *Fake) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if number == nil || number.Sign() < 0 {
+		return types.CopyHeader(f.headers[len(f.headers)-1]), nil
+	}
+	if !number.IsUint64() || number.Uint64() >= uint64(len(f.headers)) {
+		return nil, ethereum.NotFound
+	}
+	return types.CopyHeader(f.headers[number.Uint64()]), nil
+}
+
+// BlockNumber 最新区块号
+func (f *Fake) BlockNumber(context.Context) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return uint64(len(f.headers) - 1), nil
+}
+
+// CallContract 执行只读调用
+func (f *Fake) CallContract(_ context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if msg.To == nil {
+		return nil, errors.New("Fake不支持合约创建调用")
+	}
+	f.mu.Lock()
+	handler := f.calls[*msg.To]
+	block := uint64(len(f.headers) - 1)
+	f.mu.Unlock()
+
+	if handler == nil {
+		return nil, fmt.Errorf("合约%s未注册调用应答", msg.To.Hex())
+	}
+	if blockNumber != nil && blockNumber.Sign() >= 0 {
+		block = blockNumber.Uint64()
+	}
+	return handler(msg.Data, block)
+}
+
+// CodeAt 注册了调用应答的地址视为有合约代码
+func (f *Fake) CodeAt(_ context.Context, contract common.Address, _ *big.Int) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.calls[contract]; ok {
+		return []byte{0x00}, nil
+	}
+	return nil, nil
+}
+
+// mineLocked 打包一个区块（调用方持有锁）
+func (f *Fake) mineLocked(logs []types.Log) (*types.Header, []types.Log) {
+	parent := f.headers[len(f.headers)-1]
+	header := newHeader(parent, parent.Time+uint64(BlockInterval/time.Second), f.forks)
+	f.headers = append(f.headers, header)
+
+	number := header.Number.Uint64()
+	mined := make([]types.Log, len(logs))
+	txIndex := make(map[common.Hash]uint)
+	for i, lg := range logs {
+		if lg.TxHash == (common.Hash{}) {
+			lg.TxHash = syntheticTxHash(number, f.forks, uint(i))
+		}
+		if _, ok := txIndex[lg.TxHash]; !ok {
+			txIndex[lg.TxHash] = uint(len(txIndex))
+		}
+		lg.BlockNumber = number
+		lg.BlockHash = header.Hash()
+		lg.BlockTimestamp = header.Time
+		lg.TxIndex = txIndex[lg.TxHash]
+		lg.Index = uint(i)
+		lg.Removed = false
+		mined[i] = lg
+		f.logs = append(f.logs, lg)
+	}
+	return types.CopyHeader(header), mined
+}
+
+func (f *Fake) subscriptionsLocked() []*fakeSubscription {
+	subs := make([]*fakeSubscription, 0, len(f.subs))
+	for sub := range f.subs {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+func (f *Fake) removeSubscription(sub *fakeSubscription) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.subs, sub)
+}
+
+// deliver 将日志投递给匹配的订阅
+func deliver(subs []*fakeSubscription, logs []types.Log) {
+	for _, lg := range logs {
+		for _, sub := range subs {
+			if MatchLog(sub.query, lg) {
+				sub.push(lg)
+			}
+		}
+	}
+}
+
+// fakeSubscription Fake的日志订阅，日志经缓冲队列异步转发，避免订阅方处理日志时阻塞出块
+type fakeSubscription struct {
+	fake  *Fake
+	query ethereum.FilterQuery
+	queue chan types.Log
+	errCh chan error
+	quit  chan struct{}
+	once  sync.Once
+}
+
+func (s *fakeSubscription) Err() <-chan error {
+	return s.errCh
+}
+
+func (s *fakeSubscription) Unsubscribe() {
+	s.close(nil)
+}
+
+// fail 注入错误并关闭订阅
+func (s *fakeSubscription) fail(err error) {
+	s.close(err)
+}
+
+func (s *fakeSubscription) close(err error) {
+	s.once.Do(func() {
+		if err != nil {
+			s.errCh <- err
+		}
+		close(s.quit)
+		close(s.errCh)
+		s.fake.removeSubscription(s)
+	})
+}
+
+func (s *fakeSubscription) push(lg types.Log) {
+	select {
+	case s.queue <- lg:
+	case <-s.quit:
+	}
+}
+
+func (s *fakeSubscription) forward(ch chan<- types.Log) {
+	for {
+		select {
+		case lg := <-s.queue:
+			select {
+			case ch <- lg:
+			case <-s.quit:
+				return
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// MatchLog 判断日志是否满足过滤条件的地址与主题（空条件匹配任意值，不比较区块范围）
+func MatchLog(q ethereum.FilterQuery, lg types.Log) bool {
+	if len(q.Addresses) > 0 {
+		found := false
+		for _, addr := range q.Addresses {
+			if addr == lg.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(q.Topics) > len(lg.Topics) {
+		return false
+	}
+	for i, sub := range q.Topics {
+		if len(sub) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range sub {
+			if topic == lg.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// newHeader 构造区块头（字段满足JSON编解码的必填要求），fork写入Extra以区分重组前后的同高度区块
+func newHeader(parent *types.Header, timestamp, fork uint64) *types.Header {
+	header := &types.Header{
+		UncleHash:   types.EmptyUncleHash,
+		Root:        types.EmptyRootHash,
+		TxHash:      types.EmptyTxsHash,
+		ReceiptHash: types.EmptyReceiptsHash,
+		Difficulty:  big.NewInt(0),
+		Number:      big.NewInt(0),
+		GasLimit:    30_000_000,
+		Time:        timestamp,
+	}
+	if parent != nil {
+		header.ParentHash = parent.Hash()
+		header.Number = new(big.Int).Add(parent.Number, big.NewInt(1))
+	}
+	if fork > 0 {
+		header.Extra = binary.BigEndian.AppendUint64(nil, fork)
+	}
+	return header
+}
+
+// syntheticTxHash 按区块号、重组次数与日志序号生成交易哈希
+func syntheticTxHash(block, fork uint64, index uint) common.Hash {
+	buf := make([]byte, 0, 24)
+	buf = binary.BigEndian.AppendUint64(buf, block)
+	buf = binary.BigEndian.AppendUint64(buf, fork)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(index))
+	return crypto.Keccak256Hash([]byte("chainclient-fake-tx"), buf)
+}
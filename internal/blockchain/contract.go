@@ -1,615 +1,85 @@
 package blockchain
 
 import (
-	"bytes"
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
-	"time"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog/log"
 	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/reverts"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/rpcpool"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/signer"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/txmanager"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
 )
 
+// 后端发送交易的用途，写入交易表
+const (
+	PurposeCreateAuction = "createAuction"
+	PurposePlaceBid      = "placeBid"
+	PurposeEndAuction    = "endAuction"
+)
+
+// ErrNoSigner 未配置后端签名器，无法发送交易
+var ErrNoSigner = errors.New("未配置后端签名器")
+
+var (
+	// ErrUnknownChain 未配置该链的拍卖合约
+	ErrUnknownChain = errors.New("未配置该链的拍卖合约")
+	// ErrUnknownContract 该链未配置此拍卖合约地址
+	ErrUnknownContract = errors.New("未配置该拍卖合约")
+
+	// Auction 默认链（首个初始化的链）当前部署的拍卖合约实例（InitAuctionContract初始化）
+	Auction *AuctionContract
+
+	// auctions 各链当前部署的拍卖合约实例，按链ID索引
+	auctions = make(map[uint64]*AuctionContract)
+	// deployments 各链的全部拍卖合约部署（含重新部署前的旧合约），按配置顺序，第一个为当前部署
+	deployments = make(map[uint64][]*AuctionContract)
+	// chainOrder 链的初始化顺序
+	chainOrder []uint64
+)
+
+// Backend 拍卖合约依赖的链上接口（*ethclient.Client与rpcpool.Pool均已实现）
+type Backend interface {
+	bind.ContractBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
 // 创建拍卖、竞价和结束拍卖采用前端直接与链交互，不再使用后端代创建
 // AuctionContract 拍卖合约实例
 type AuctionContract struct {
-	client     *ethclient.Client // 区块链客户端
-	address    common.Address    // 合约地址
-	abi        abi.ABI           // 合约ABI
-	privateKey string            // 私钥
-	chainID    *big.Int          // 链ID
+	client    Backend               // 区块链客户端
+	address   common.Address        // 合约地址
+	abi       *abi.ABI              // 合约ABI
+	auction   *contracts.NFTAuction // 类型化合约绑定
+	chainID   *big.Int              // 链ID
+	txManager *txmanager.Manager    // 后端签名地址的交易管理器
+	decoder   *reverts.Decoder      // 回滚原因解析器
 }
 
-// 拍卖合约ABI
-const auctionABI = `[
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "target",
-				"type": "address"
-			}
-		],
-		"name": "AddressEmptyCode",
-		"type": "error"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "_seller",
-				"type": "address"
-			},
-			{
-				"internalType": "uint256",
-				"name": "_duration",
-				"type": "uint256"
-			},
-			{
-				"internalType": "uint256",
-				"name": "_startPrice",
-				"type": "uint256"
-			},
-			{
-				"internalType": "address",
-				"name": "_nftContract",
-				"type": "address"
-			},
-			{
-				"internalType": "uint256",
-				"name": "_nftId",
-				"type": "uint256"
-			}
-		],
-		"name": "createAuction",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "implementation",
-				"type": "address"
-			}
-		],
-		"name": "ERC1967InvalidImplementation",
-		"type": "error"
-	},
-	{
-		"inputs": [],
-		"name": "ERC1967NonPayable",
-		"type": "error"
-	},
-	{
-		"inputs": [],
-		"name": "FailedCall",
-		"type": "error"
-	},
-	{
-		"inputs": [],
-		"name": "InvalidInitialization",
-		"type": "error"
-	},
-	{
-		"inputs": [],
-		"name": "NotInitializing",
-		"type": "error"
-	},
-	{
-		"inputs": [],
-		"name": "UUPSUnauthorizedCallContext",
-		"type": "error"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "bytes32",
-				"name": "slot",
-				"type": "bytes32"
-			}
-		],
-		"name": "UUPSUnsupportedProxiableUUID",
-		"type": "error"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{
-				"indexed": true,
-				"internalType": "uint256",
-				"name": "auctionId",
-				"type": "uint256"
-			},
-			{
-				"indexed": false,
-				"internalType": "address",
-				"name": "seller",
-				"type": "address"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "duration",
-				"type": "uint256"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "startPrice",
-				"type": "uint256"
-			},
-			{
-				"indexed": false,
-				"internalType": "address",
-				"name": "startTokenAddress",
-				"type": "address"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "startTime",
-				"type": "uint256"
-			},
-			{
-				"indexed": false,
-				"internalType": "address",
-				"name": "nftContract",
-				"type": "address"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "nftId",
-				"type": "uint256"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "optTime",
-				"type": "uint256"
-			}
-		],
-		"name": "CreateAuction",
-		"type": "event"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "uint256",
-				"name": "_auctionId",
-				"type": "uint256"
-			}
-		],
-		"name": "endAuction",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{
-				"indexed": true,
-				"internalType": "uint256",
-				"name": "auctionId",
-				"type": "uint256"
-			},
-			{
-				"indexed": false,
-				"internalType": "address",
-				"name": "winner",
-				"type": "address"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "amount",
-				"type": "uint256"
-			},
-			{
-				"indexed": false,
-				"internalType": "address",
-				"name": "tokenAddress",
-				"type": "address"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "optTime",
-				"type": "uint256"
-			}
-		],
-		"name": "EndAuction",
-		"type": "event"
-	},
-	{
-		"inputs": [],
-		"name": "initialize",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{
-				"indexed": false,
-				"internalType": "uint64",
-				"name": "version",
-				"type": "uint64"
-			}
-		],
-		"name": "Initialized",
-		"type": "event"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "uint256",
-				"name": "_auctionId",
-				"type": "uint256"
-			},
-			{
-				"internalType": "uint256",
-				"name": "_amount",
-				"type": "uint256"
-			},
-			{
-				"internalType": "address",
-				"name": "_tokenAddress",
-				"type": "address"
-			}
-		],
-		"name": "placeBid",
-		"outputs": [],
-		"stateMutability": "payable",
-		"type": "function"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{
-				"indexed": true,
-				"internalType": "uint256",
-				"name": "auctionId",
-				"type": "uint256"
-			},
-			{
-				"indexed": false,
-				"internalType": "address",
-				"name": "bidder",
-				"type": "address"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "amount",
-				"type": "uint256"
-			},
-			{
-				"indexed": false,
-				"internalType": "address",
-				"name": "tokenAddress",
-				"type": "address"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "optTime",
-				"type": "uint256"
-			}
-		],
-		"name": "PlaceBid",
-		"type": "event"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "_tokenAddress",
-				"type": "address"
-			},
-			{
-				"internalType": "address",
-				"name": "_priceFeedAddress",
-				"type": "address"
-			}
-		],
-		"name": "setPriceETHFeed",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{
-				"indexed": true,
-				"internalType": "address",
-				"name": "implementation",
-				"type": "address"
-			}
-		],
-		"name": "Upgraded",
-		"type": "event"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "newImplementation",
-				"type": "address"
-			},
-			{
-				"internalType": "bytes",
-				"name": "data",
-				"type": "bytes"
-			}
-		],
-		"name": "upgradeToAndCall",
-		"outputs": [],
-		"stateMutability": "payable",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "admin",
-		"outputs": [
-			{
-				"internalType": "address",
-				"name": "",
-				"type": "address"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "uint256",
-				"name": "",
-				"type": "uint256"
-			}
-		],
-		"name": "auctions",
-		"outputs": [
-			{
-				"internalType": "address",
-				"name": "seller",
-				"type": "address"
-			},
-			{
-				"internalType": "uint256",
-				"name": "duration",
-				"type": "uint256"
-			},
-			{
-				"internalType": "uint256",
-				"name": "startTime",
-				"type": "uint256"
-			},
-			{
-				"internalType": "uint256",
-				"name": "startPrice",
-				"type": "uint256"
-			},
-			{
-				"internalType": "address",
-				"name": "startTokenAddress",
-				"type": "address"
-			},
-			{
-				"internalType": "bool",
-				"name": "ended",
-				"type": "bool"
-			},
-			{
-				"internalType": "uint256",
-				"name": "highestBid",
-				"type": "uint256"
-			},
-			{
-				"internalType": "address",
-				"name": "highestBidder",
-				"type": "address"
-			},
-			{
-				"internalType": "address",
-				"name": "nftContract",
-				"type": "address"
-			},
-			{
-				"internalType": "uint256",
-				"name": "nftId",
-				"type": "uint256"
-			},
-			{
-				"internalType": "address",
-				"name": "tokenAddress",
-				"type": "address"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "uint256",
-				"name": "_amount",
-				"type": "uint256"
-			},
-			{
-				"internalType": "address",
-				"name": "_tokenAddress",
-				"type": "address"
-			}
-		],
-		"name": "calculateValue",
-		"outputs": [
-			{
-				"internalType": "uint256",
-				"name": "",
-				"type": "uint256"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "_tokenAddress",
-				"type": "address"
-			}
-		],
-		"name": "getChainlinkDataFeedLatestAnswer",
-		"outputs": [
-			{
-				"internalType": "int256",
-				"name": "",
-				"type": "int256"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "nextAuctionId",
-		"outputs": [
-			{
-				"internalType": "uint256",
-				"name": "",
-				"type": "uint256"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "",
-				"type": "address"
-			},
-			{
-				"internalType": "address",
-				"name": "",
-				"type": "address"
-			},
-			{
-				"internalType": "uint256",
-				"name": "",
-				"type": "uint256"
-			},
-			{
-				"internalType": "bytes",
-				"name": "",
-				"type": "bytes"
-			}
-		],
-		"name": "onERC721Received",
-		"outputs": [
-			{
-				"internalType": "bytes4",
-				"name": "",
-				"type": "bytes4"
-			}
-		],
-		"stateMutability": "pure",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "",
-				"type": "address"
-			}
-		],
-		"name": "priceFeedDecimals",
-		"outputs": [
-			{
-				"internalType": "uint8",
-				"name": "",
-				"type": "uint8"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "",
-				"type": "address"
-			}
-		],
-		"name": "priceFeeds",
-		"outputs": [
-			{
-				"internalType": "contract AggregatorV3Interface",
-				"name": "",
-				"type": "address"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "proxiableUUID",
-		"outputs": [
-			{
-				"internalType": "bytes32",
-				"name": "",
-				"type": "bytes32"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "UPGRADE_INTERFACE_VERSION",
-		"outputs": [
-			{
-				"internalType": "string",
-				"name": "",
-				"type": "string"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	}
-]`
-
-// NewAuctionContract 初始化合约实例
-func NewAuctionContract(cfg *config.BlockchainConfig) (*AuctionContract, error) {
-	// 连接区块链节点
-	client, err := ethclient.Dial(cfg.RPCEndpoint)
+// NewAuctionContracts 初始化一条链上全部拍卖合约部署的实例，第一个为当前部署；交易由txSigner签名
+// 各部署共用节点连接与交易管理器，同一签名地址的nonce统一分配
+func NewAuctionContracts(cfg *config.BlockchainConfig, txSigner signer.Signer) ([]*AuctionContract, error) {
+	// 连接区块链节点（可配置多个，按健康状况分配请求）
+	client, err := rpcpool.Dial(cfg.PoolName("http"), cfg.HTTPEndpoints(), cfg.RPCPool)
 	if err != nil {
 		log.Error().Err(err).Msg("区块链节点连接失败")
 		return nil, err
 	}
 
 	// 解析合约ABI
-	contractABI, err := abi.JSON(bytes.NewReader([]byte(auctionABI)))
+	contractABI, err := contracts.NFTAuctionMetaData.GetAbi()
 	if err != nil {
 		log.Error().Err(err).Msg("合约ABI解析失败")
 		return nil, err
@@ -621,217 +91,328 @@ func NewAuctionContract(cfg *config.BlockchainConfig) (*AuctionContract, error)
 		log.Error().Err(err).Msg("获取链ID失败")
 		return nil, err
 	}
-
-	return &AuctionContract{
-		client:     client,
-		address:    common.HexToAddress(cfg.ContractAddr),
-		abi:        contractABI,
-		privateKey: cfg.PrivateKey,
-		chainID:    chainID,
-	}, nil
-}
-
-// CreateAuction 调用合约创建拍卖（后端代创建）
-func (c *AuctionContract) CreateAuction(seller common.Address, duration *big.Int, startPrice *big.Int, nftContract common.Address, nftId *big.Int) (string, error) {
-	// 加载私钥
-	privateKey, err := crypto.HexToECDSA(c.privateKey)
-	if err != nil {
-		log.Error().Err(err).Msg("私钥解析失败")
-		return "", err
+	if cfg.ChainID != 0 && chainID.Uint64() != cfg.ChainID {
+		return nil, fmt.Errorf("节点链ID为%s，与配置的%d不一致", chainID, cfg.ChainID)
 	}
 
-	// 获取公钥地址
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Error().Msg("error casting public key to ECDSA")
-		return "", err
+	// 回滚解析包含拍卖合约及其调用的ERC20/ERC721合约的自定义错误
+	erc20ABI, err := contracts.ERC20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
 	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-
-	// 获取nonce
-	nonce, err := c.client.PendingNonceAt(context.Background(), fromAddress)
+	erc721ABI, err := contracts.ERC721MetaData.GetAbi()
 	if err != nil {
-		log.Error().Err(err).Msg("获取nonce失败")
-		return "", err
+		return nil, err
 	}
+	decoder := reverts.NewDecoder(contractABI, erc20ABI, erc721ABI)
 
-	// 获取燃气价格
-	gasPrice, err := c.client.SuggestGasPrice(context.Background())
-	if err != nil {
-		log.Error().Err(err).Msg("获取燃气价格失败")
-		return "", err
+	// 未配置签名器时仅支持只读调用与交易构造
+	var txManager *txmanager.Manager
+	if txSigner != nil {
+		txManager = txmanager.NewManager(client, chainID, txSigner, cfg.TxManager)
+		txManager.SetRevertDecoder(decoder)
 	}
 
-	// 调用合约方法
-	data, err := c.abi.Pack("createAuction", seller, duration, startPrice, nftContract, nftId)
-	if err != nil {
-		log.Error().Err(err).Msg("合约方法打包失败")
-		return "", err
+	var list []*AuctionContract
+	seen := make(map[common.Address]bool)
+	for _, deployment := range cfg.AuctionDeployments() {
+		// 创建合约绑定
+		address := common.HexToAddress(deployment.Address)
+		if seen[address] {
+			return nil, fmt.Errorf("拍卖合约%s重复配置", address.Hex())
+		}
+		seen[address] = true
+		auction, err := contracts.NewNFTAuction(address, client)
+		if err != nil {
+			log.Error().Err(err).Msg("合约绑定创建失败")
+			return nil, err
+		}
+		list = append(list, &AuctionContract{
+			client:    client,
+			address:   address,
+			abi:       contractABI,
+			auction:   auction,
+			chainID:   chainID,
+			txManager: txManager,
+			decoder:   decoder,
+		})
 	}
+	return list, nil
+}
 
-	// 估算燃气限制
-	gasLimit, err := c.client.EstimateGas(context.Background(), ethereum.CallMsg{
-		From: fromAddress,
-		To:   &c.address,
-		Data: data,
-	})
+// InitAuctionContract 初始化并登记一条链的全部拍卖合约部署，首个初始化的链作为默认链；txSigner可为nil
+func InitAuctionContract(cfg *config.BlockchainConfig, txSigner signer.Signer) error {
+	list, err := NewAuctionContracts(cfg, txSigner)
 	if err != nil {
-		log.Error().Err(err).Msg("估算燃气限制失败")
-		return "", err
+		return err
 	}
+	RegisterAuctionContracts(list)
+	return nil
+}
 
-	// 创建未签名交易（数据字段为 ABI 编码后的函数调用数据）
-	tx := types.NewTransaction(
-		nonce,
-		c.address,
-		big.NewInt(0),
-		gasLimit,
-		gasPrice,
-		data,
-	)
-
-	// 签名交易
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(c.chainID), privateKey)
-	if err != nil {
-		log.Error().Err(err).Msg("交易签名失败")
-		return "", err
+// RegisterAuctionContracts 登记一条链的全部拍卖合约部署（第一个为当前部署），同一链重复登记时替换
+func RegisterAuctionContracts(list []*AuctionContract) {
+	if len(list) == 0 {
+		return
+	}
+	current := list[0]
+	chainID := current.chainID.Uint64()
+	if _, ok := auctions[chainID]; !ok {
+		chainOrder = append(chainOrder, chainID)
 	}
+	auctions[chainID] = current
+	deployments[chainID] = list
+	if Auction == nil || Auction.chainID.Uint64() == chainID {
+		Auction = current
+	}
+}
 
-	// 发送交易到区块链
-	err = c.client.SendTransaction(context.Background(), signedTx)
-	if err != nil {
-		log.Error().Err(err).Msg("交易发送失败")
-		return "", err
+// AuctionOn 指定链的拍卖合约实例，chainID为0时返回默认链
+func AuctionOn(chainID uint64) (*AuctionContract, error) {
+	if chainID == 0 {
+		if Auction == nil {
+			return nil, ErrUnknownChain
+		}
+		return Auction, nil
 	}
-	log.Info().Msg("交易hash地址：" + string(signedTx.Hash().Hex()))
+	contract, ok := auctions[chainID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownChain, chainID)
+	}
+	return contract, nil
+}
 
-	// 等待交易回执
-	receipt, err := waitForReceipt(c.client, signedTx.Hash())
-	if err != nil {
-		log.Error().Err(err).Msg("等待交易回执失败")
-		return "", err
+// AuctionAt 指定链上指定地址的拍卖合约部署，address为零地址时返回该链当前部署
+func AuctionAt(chainID uint64, address common.Address) (*AuctionContract, error) {
+	current, err := AuctionOn(chainID)
+	if err != nil || address == (common.Address{}) {
+		return current, err
 	}
-	if receipt.Status == types.ReceiptStatusSuccessful {
-		fmt.Printf("交易成功！区块号：%d，消耗Gas：%d\n", receipt.BlockNumber, receipt.GasUsed)
-	} else {
-		fmt.Println("交易失败！")
+	for _, contract := range deployments[current.chainID.Uint64()] {
+		if contract.address == address {
+			return contract, nil
+		}
 	}
-	return string(signedTx.Hash().Hex()), nil
+	return nil, fmt.Errorf("%w: %s", ErrUnknownContract, address.Hex())
 }
 
-// PlaceBid 调用合约提交竞拍
-func (c *AuctionContract) PlaceBid(auctionID, bidAmount *big.Int, tokenAddress common.Address, bidderPrivateKey string) (string, error) {
-	// 解析私钥
-	privateKey, err := crypto.HexToECDSA(bidderPrivateKey)
-	if err != nil {
-		return "", err
+// AuctionDeploymentsOn 指定链的全部拍卖合约部署，第一个为当前部署
+func AuctionDeploymentsOn(chainID uint64) []*AuctionContract {
+	return deployments[chainID]
+}
+
+// AuctionContracts 全部链当前部署的拍卖合约实例，按初始化顺序
+func AuctionContracts() []*AuctionContract {
+	list := make([]*AuctionContract, 0, len(chainOrder))
+	for _, chainID := range chainOrder {
+		list = append(list, auctions[chainID])
 	}
+	return list
+}
 
-	// 获取公钥地址
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Error().Msg("error casting public key to ECDSA")
-		return "", err
+// DefaultChainID 默认链ID，未初始化时返回0
+func DefaultChainID() uint64 {
+	if Auction == nil {
+		return 0
 	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
+	return Auction.chainID.Uint64()
+}
 
-	// 获取燃气价格
-	gasPrice, err := c.client.SuggestGasPrice(context.Background())
+// Address 拍卖合约地址
+func (c *AuctionContract) Address() common.Address {
+	return c.address
+}
+
+// ChainID 链ID
+func (c *AuctionContract) ChainID() *big.Int {
+	return c.chainID
+}
+
+// AuctionKey 该合约上拍卖auctionID的标识
+func (c *AuctionContract) AuctionKey(auctionID uint64) models.AuctionKey {
+	return models.AuctionKey{ChainID: c.chainID.Uint64(), Contract: c.address.Hex(), ID: auctionID}
+}
+
+// Client 区块链客户端
+func (c *AuctionContract) Client() Backend {
+	return c.client
+}
+
+// DecodeRevert 从eth_call/eth_estimateGas错误中解析回滚原因，非回滚错误返回false
+func (c *AuctionContract) DecodeRevert(err error) (*reverts.Error, bool) {
+	return c.decoder.FromError(err)
+}
+
+// PackCreateAuction 打包createAuction调用数据
+func (c *AuctionContract) PackCreateAuction(seller common.Address, duration *big.Int, startPrice *big.Int, nftContract common.Address, nftId *big.Int) ([]byte, error) {
+	return packCalldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.auction.CreateAuction(opts, seller, duration, startPrice, nftContract, nftId)
+	})
+}
+
+// PackPlaceBid 打包placeBid调用数据
+func (c *AuctionContract) PackPlaceBid(auctionID, bidAmount *big.Int, tokenAddress common.Address) ([]byte, error) {
+	return packCalldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.auction.PlaceBid(opts, auctionID, bidAmount, tokenAddress)
+	})
+}
+
+// PackEndAuction 打包endAuction调用数据
+func (c *AuctionContract) PackEndAuction(auctionID *big.Int) ([]byte, error) {
+	return packCalldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.auction.EndAuction(opts, auctionID)
+	})
+}
+
+// packCalldata 通过类型化绑定的交易方法取调用数据，方法或参数变更时编译期即可发现；
+// 交易不签名、不发送，nonce与gas使用占位值，因此不会请求节点
+func packCalldata(transact func(opts *bind.TransactOpts) (*types.Transaction, error)) ([]byte, error) {
+	tx, err := transact(&bind.TransactOpts{
+		Nonce:    new(big.Int),
+		GasPrice: new(big.Int),
+		GasLimit: 1,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+		NoSend: true,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("获取燃气价格失败")
-		return "", err
+		return nil, err
 	}
+	return tx.Data(), nil
+}
 
-	// 获取nonce
-	nonce, err := c.client.PendingNonceAt(context.Background(), fromAddress)
-	if err != nil {
-		log.Error().Err(err).Msg("获取nonce失败")
-		return "", err
+// BidValue 出价交易需携带的ETH金额：ETH支付为出价金额，ERC20支付为0
+func BidValue(bidAmount *big.Int, tokenAddress common.Address) *big.Int {
+	if tokenAddress == (common.Address{}) {
+		return bidAmount
 	}
+	return big.NewInt(0)
+}
 
+// CreateAuction 调用合约创建拍卖（后端代创建）
+func (c *AuctionContract) CreateAuction(ctx context.Context, seller common.Address, duration *big.Int, startPrice *big.Int, nftContract common.Address, nftId *big.Int) (string, error) {
 	// 调用合约方法
-	data, err := c.abi.Pack("placeBid", auctionID, bidAmount, tokenAddress)
+	data, err := c.PackCreateAuction(seller, duration, startPrice, nftContract, nftId)
 	if err != nil {
 		log.Error().Err(err).Msg("合约方法打包失败")
 		return "", err
 	}
 
-	// 估算燃气限制（智能合约调用需估算，而非固定 21000）
-	gasLimit, err := c.client.EstimateGas(context.Background(), ethereum.CallMsg{
-		From:  fromAddress,
-		To:    &c.address,
-		Data:  data,
-		Value: bidAmount,
+	return c.sendAndWait(ctx, txmanager.TxRequest{
+		Purpose: PurposeCreateAuction,
+		To:      c.address,
+		Data:    data,
 	})
+}
+
+// PlaceBid 调用合约提交竞拍（以后端签名地址出价）
+func (c *AuctionContract) PlaceBid(ctx context.Context, auctionID, bidAmount *big.Int, tokenAddress common.Address) (string, error) {
+	// 调用合约方法
+	data, err := c.PackPlaceBid(auctionID, bidAmount, tokenAddress)
 	if err != nil {
-		log.Error().Err(err).Msg("估算燃气限制失败")
+		log.Error().Err(err).Msg("合约方法打包失败")
 		return "", err
 	}
 
-	// 创建未签名交易, 判断是否用eth支付
-	var tx *types.Transaction
-	ethZeroAddress := common.HexToAddress("0x0000000000000000000000000000000000000000")
-	if tokenAddress == ethZeroAddress {
-		tx = types.NewTransaction(
-			nonce,
-			c.address,
-			bidAmount,
-			gasLimit,
-			gasPrice,
-			data,
-		)
-	} else {
-		tx = types.NewTransaction(
-			nonce,
-			c.address,
-			big.NewInt(0),
-			gasLimit,
-			gasPrice,
-			data,
-		)
-	}
+	// 判断是否用eth支付，eth支付需携带转账金额
+	return c.sendAndWait(ctx, txmanager.TxRequest{
+		Purpose: PurposePlaceBid,
+		To:      c.address,
+		Data:    data,
+		Value:   BidValue(bidAmount, tokenAddress),
+	})
+}
+
+// OnchainAuction 合约auctions()视图返回的拍卖状态
+type OnchainAuction struct {
+	Seller            common.Address
+	Duration          *big.Int
+	StartTime         *big.Int
+	StartPrice        *big.Int
+	StartTokenAddress common.Address
+	Ended             bool
+	HighestBid        *big.Int
+	HighestBidder     common.Address
+	NftContract       common.Address
+	NftId             *big.Int
+	TokenAddress      common.Address
+}
+
+// EndTime 链上拍卖结束时间（秒）
+func (a *OnchainAuction) EndTime() *big.Int {
+	return new(big.Int).Add(a.StartTime, a.Duration)
+}
 
-	// 签名交易
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(c.chainID), privateKey)
+// GetAuction 查询链上拍卖状态
+func (c *AuctionContract) GetAuction(ctx context.Context, auctionID *big.Int) (*OnchainAuction, error) {
+	result, err := c.auction.Auctions(&bind.CallOpts{Context: ctx}, auctionID)
 	if err != nil {
-		log.Error().Err(err).Msg("交易签名失败")
-		return "", err
+		return nil, c.decoder.Wrap(err)
 	}
+	auction := OnchainAuction(result)
+	return &auction, nil
+}
 
-	err = c.client.SendTransaction(context.Background(), signedTx)
+// EndAuction 发送结束拍卖交易（不等待上链），返回交易记录
+func (c *AuctionContract) EndAuction(ctx context.Context, auctionID *big.Int) (*models.Transaction, error) {
+	data, err := c.PackEndAuction(auctionID)
 	if err != nil {
-		log.Error().Err(err).Msg("交易发送失败")
-		return "", err
+		log.Error().Err(err).Msg("合约方法打包失败")
+		return nil, err
 	}
-	log.Info().Msg("交易hash地址：" + string(signedTx.Hash().Hex()))
 
-	// 等待交易回执
-	receipt, err := waitForReceipt(c.client, signedTx.Hash())
-	if err != nil {
-		log.Error().Err(err).Msg("等待交易回执失败")
-		return "", err
+	if c.txManager == nil {
+		return nil, ErrNoSigner
 	}
-	if receipt.Status == types.ReceiptStatusSuccessful {
-		fmt.Printf("交易成功！区块号：%d，消耗Gas：%d\n", receipt.BlockNumber, receipt.GasUsed)
-	} else {
-		fmt.Println("交易失败！")
+	return c.txManager.Send(ctx, txmanager.TxRequest{
+		Purpose:   PurposeEndAuction,
+		AuctionID: auctionID.Uint64(),
+		To:        c.address,
+		Data:      data,
+	})
+}
+
+// WaitMined 等待后端发送的交易上链（超时、加价替换由交易管理器处理）
+func (c *AuctionContract) WaitMined(ctx context.Context, record *models.Transaction) (*types.Receipt, error) {
+	if c.txManager == nil {
+		return nil, ErrNoSigner
 	}
+	return c.txManager.WaitMined(ctx, record)
+}
 
-	return string(signedTx.Hash().Hex()), nil
+// Sender 后端签名地址，未配置签名器时返回零地址
+func (c *AuctionContract) Sender() common.Address {
+	if c.txManager == nil {
+		return common.Address{}
+	}
+	return c.txManager.From()
 }
 
-func waitForReceipt(client *ethclient.Client, txHash common.Hash) (*types.Receipt, error) {
-	for {
-		receipt, err := client.TransactionReceipt(context.Background(), txHash)
-		if err == nil {
-			return receipt, nil
-		}
-		if err != ethereum.NotFound {
-			return nil, err
+// HeaderByNumber 查询区块头，number为nil时返回最新区块
+func (c *AuctionContract) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return c.client.HeaderByNumber(ctx, number)
+}
+
+// sendAndWait 通过交易管理器发送交易并等待回执，返回最终上链的交易哈希
+func (c *AuctionContract) sendAndWait(ctx context.Context, req txmanager.TxRequest) (string, error) {
+	if c.txManager == nil {
+		return "", ErrNoSigner
+	}
+	receipt, record, err := c.txManager.SendAndWait(ctx, req)
+	if err != nil {
+		// 回滚时err为*reverts.Error，可用errors.As获取结构化原因
+		log.Error().Err(err).Str("purpose", req.Purpose).Msg("交易发送或等待回执失败")
+		if record != nil {
+			return record.Hash, err
 		}
-		// 等待一段时间后再次查询
-		time.Sleep(1 * time.Second)
+		return "", err
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		log.Error().Str("hash", record.Hash).Uint64("block", receipt.BlockNumber.Uint64()).Msg("交易执行失败")
+		return record.Hash, fmt.Errorf("交易%s执行失败", record.Hash)
 	}
+	log.Info().Str("hash", record.Hash).Uint64("block", receipt.BlockNumber.Uint64()).Uint64("gas_used", receipt.GasUsed).Msg("交易成功")
+	return record.Hash, nil
 }
@@ -0,0 +1,10 @@
+// Package contracts 拍卖合约、ERC721、ERC20与Chainlink价格预言机（AggregatorV3）的类型化绑定
+//
+// 绑定代码由同目录下的ABI文件生成，ABI是唯一来源；合约事件或方法变更时
+// 更新ABI文件并执行 go generate，调用方在编译期即可发现字段变化。
+package contracts
+
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi NFTAuction.abi.json --pkg contracts --type NFTAuction --out nft_auction.go
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi ERC721.abi.json --pkg contracts --type ERC721 --out erc721.go
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi ERC20.abi.json --pkg contracts --type ERC20 --out erc20.go
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi AggregatorV3.abi.json --pkg contracts --type AggregatorV3 --out aggregator_v3.go
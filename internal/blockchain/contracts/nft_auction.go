@@ -0,0 +1,1402 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// NFTAuctionMetaData contains all meta data concerning the NFTAuction contract.
+var NFTAuctionMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"target\",\"type\":\"address\"}],\"name\":\"AddressEmptyCode\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"_duration\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_startPrice\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"_nftContract\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"_nftId\",\"type\":\"uint256\"}],\"name\":\"createAuction\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"implementation\",\"type\":\"address\"}],\"name\":\"ERC1967InvalidImplementation\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"ERC1967NonPayable\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"FailedCall\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"InvalidInitialization\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"NotInitializing\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"UUPSUnauthorizedCallContext\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"slot\",\"type\":\"bytes32\"}],\"name\":\"UUPSUnsupportedProxiableUUID\",\"type\":\"error\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"auctionId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"duration\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"startPrice\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"startTokenAddress\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"startTime\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"nftContract\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"nftId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"optTime\",\"type\":\"uint256\"}],\"name\":\"CreateAuction\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_auctionId\",\"type\":\"uint256\"}],\"name\":\"endAuction\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"auctionId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"winner\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"tokenAddress\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"optTime\",\"type\":\"uint256\"}],\"name\":\"EndAuction\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"initialize\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint64\",\"name\":\"version\",\"type\":\"uint64\"}],\"name\":\"Initialized\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_auctionId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_amount\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"_tokenAddress\",\"type\":\"address\"}],\"name\":\"placeBid\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"auctionId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"bidder\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"tokenAddress\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"optTime\",\"type\":\"uint256\"}],\"name\":\"PlaceBid\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_tokenAddress\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_priceFeedAddress\",\"type\":\"address\"}],\"name\":\"setPriceETHFeed\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"implementation\",\"type\":\"address\"}],\"name\":\"Upgraded\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"newImplementation\",\"type\":\"address\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"upgradeToAndCall\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"admin\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"auctions\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"duration\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startTime\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startPrice\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"startTokenAddress\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"ended\",\"type\":\"bool\"},{\"internalType\":\"uint256\",\"name\":\"highestBid\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"highestBidder\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"nftContract\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"nftId\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"tokenAddress\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_amount\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"_tokenAddress\",\"type\":\"address\"}],\"name\":\"calculateValue\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_tokenAddress\",\"type\":\"address\"}],\"name\":\"getChainlinkDataFeedLatestAnswer\",\"outputs\":[{\"internalType\":\"int256\",\"name\":\"\",\"type\":\"int256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"nextAuctionId\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"\",\"type\":\"bytes\"}],\"name\":\"onERC721Received\",\"outputs\":[{\"internalType\":\"bytes4\",\"name\":\"\",\"type\":\"bytes4\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"priceFeedDecimals\",\"outputs\":[{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"priceFeeds\",\"outputs\":[{\"internalType\":\"contractAggregatorV3Interface\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"proxiableUUID\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"UPGRADE_INTERFACE_VERSION\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// NFTAuctionABI is the input ABI used to generate the binding from.
+// Deprecated: Use NFTAuctionMetaData.ABI instead.
+var NFTAuctionABI = NFTAuctionMetaData.ABI
+
+// NFTAuction is an auto generated Go binding around an Ethereum contract.
+type NFTAuction struct {
+	NFTAuctionCaller     // Read-only binding to the contract
+	NFTAuctionTransactor // Write-only binding to the contract
+	NFTAuctionFilterer   // Log filterer for contract events
+}
+
+// NFTAuctionCaller is an auto generated read-only Go binding around an Ethereum contract.
+type NFTAuctionCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// NFTAuctionTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type NFTAuctionTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// NFTAuctionFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type NFTAuctionFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// NFTAuctionSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type NFTAuctionSession struct {
+	Contract     *NFTAuction       // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// NFTAuctionCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type NFTAuctionCallerSession struct {
+	Contract *NFTAuctionCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts     // Call options to use throughout this session
+}
+
+// NFTAuctionTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type NFTAuctionTransactorSession struct {
+	Contract     *NFTAuctionTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts     // Transaction auth options to use throughout this session
+}
+
+// NFTAuctionRaw is an auto generated low-level Go binding around an Ethereum contract.
+type NFTAuctionRaw struct {
+	Contract *NFTAuction // Generic contract binding to access the raw methods on
+}
+
+// NFTAuctionCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type NFTAuctionCallerRaw struct {
+	Contract *NFTAuctionCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// NFTAuctionTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type NFTAuctionTransactorRaw struct {
+	Contract *NFTAuctionTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewNFTAuction creates a new instance of NFTAuction, bound to a specific deployed contract.
+func NewNFTAuction(address common.Address, backend bind.ContractBackend) (*NFTAuction, error) {
+	contract, err := bindNFTAuction(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &NFTAuction{NFTAuctionCaller: NFTAuctionCaller{contract: contract}, NFTAuctionTransactor: NFTAuctionTransactor{contract: contract}, NFTAuctionFilterer: NFTAuctionFilterer{contract: contract}}, nil
+}
+
+// NewNFTAuctionCaller creates a new read-only instance of NFTAuction, bound to a specific deployed contract.
+func NewNFTAuctionCaller(address common.Address, caller bind.ContractCaller) (*NFTAuctionCaller, error) {
+	contract, err := bindNFTAuction(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &NFTAuctionCaller{contract: contract}, nil
+}
+
+// NewNFTAuctionTransactor creates a new write-only instance of NFTAuction, bound to a specific deployed contract.
+func NewNFTAuctionTransactor(address common.Address, transactor bind.ContractTransactor) (*NFTAuctionTransactor, error) {
+	contract, err := bindNFTAuction(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &NFTAuctionTransactor{contract: contract}, nil
+}
+
+// NewNFTAuctionFilterer creates a new log filterer instance of NFTAuction, bound to a specific deployed contract.
+func NewNFTAuctionFilterer(address common.Address, filterer bind.ContractFilterer) (*NFTAuctionFilterer, error) {
+	contract, err := bindNFTAuction(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &NFTAuctionFilterer{contract: contract}, nil
+}
+
+// bindNFTAuction binds a generic wrapper to an already deployed contract.
+func bindNFTAuction(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := NFTAuctionMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_NFTAuction *NFTAuctionRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _NFTAuction.Contract.NFTAuctionCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_NFTAuction *NFTAuctionRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NFTAuction.Contract.NFTAuctionTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_NFTAuction *NFTAuctionRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _NFTAuction.Contract.NFTAuctionTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_NFTAuction *NFTAuctionCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _NFTAuction.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_NFTAuction *NFTAuctionTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NFTAuction.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_NFTAuction *NFTAuctionTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _NFTAuction.Contract.contract.Transact(opts, method, params...)
+}
+
+// UPGRADEINTERFACEVERSION is a free data retrieval call binding the contract method 0xad3cb1cc.
+//
+// Solidity: function UPGRADE_INTERFACE_VERSION() view returns(string)
+func (_NFTAuction *NFTAuctionCaller) UPGRADEINTERFACEVERSION(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _NFTAuction.contract.Call(opts, &out, "UPGRADE_INTERFACE_VERSION")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// UPGRADEINTERFACEVERSION is a free data retrieval call binding the contract method 0xad3cb1cc.
+//
+// Solidity: function UPGRADE_INTERFACE_VERSION() view returns(string)
+func (_NFTAuction *NFTAuctionSession) UPGRADEINTERFACEVERSION() (string, error) {
+	return _NFTAuction.Contract.UPGRADEINTERFACEVERSION(&_NFTAuction.CallOpts)
+}
+
+// UPGRADEINTERFACEVERSION is a free data retrieval call binding the contract method 0xad3cb1cc.
+//
+// Solidity: function UPGRADE_INTERFACE_VERSION() view returns(string)
+func (_NFTAuction *NFTAuctionCallerSession) UPGRADEINTERFACEVERSION() (string, error) {
+	return _NFTAuction.Contract.UPGRADEINTERFACEVERSION(&_NFTAuction.CallOpts)
+}
+
+// Admin is a free data retrieval call binding the contract method 0xf851a440.
+//
+// Solidity: function admin() view returns(address)
+func (_NFTAuction *NFTAuctionCaller) Admin(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _NFTAuction.contract.Call(opts, &out, "admin")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Admin is a free data retrieval call binding the contract method 0xf851a440.
+//
+// Solidity: function admin() view returns(address)
+func (_NFTAuction *NFTAuctionSession) Admin() (common.Address, error) {
+	return _NFTAuction.Contract.Admin(&_NFTAuction.CallOpts)
+}
+
+// Admin is a free data retrieval call binding the contract method 0xf851a440.
+//
+// Solidity: function admin() view returns(address)
+func (_NFTAuction *NFTAuctionCallerSession) Admin() (common.Address, error) {
+	return _NFTAuction.Contract.Admin(&_NFTAuction.CallOpts)
+}
+
+// Auctions is a free data retrieval call binding the contract method 0x571a26a0.
+//
+// Solidity: function auctions(uint256 ) view returns(address seller, uint256 duration, uint256 startTime, uint256 startPrice, address startTokenAddress, bool ended, uint256 highestBid, address highestBidder, address nftContract, uint256 nftId, address tokenAddress)
+func (_NFTAuction *NFTAuctionCaller) Auctions(opts *bind.CallOpts, arg0 *big.Int) (struct {
+	Seller            common.Address
+	Duration          *big.Int
+	StartTime         *big.Int
+	StartPrice        *big.Int
+	StartTokenAddress common.Address
+	Ended             bool
+	HighestBid        *big.Int
+	HighestBidder     common.Address
+	NftContract       common.Address
+	NftId             *big.Int
+	TokenAddress      common.Address
+}, error) {
+	var out []interface{}
+	err := _NFTAuction.contract.Call(opts, &out, "auctions", arg0)
+
+	outstruct := new(struct {
+		Seller            common.Address
+		Duration          *big.Int
+		StartTime         *big.Int
+		StartPrice        *big.Int
+		StartTokenAddress common.Address
+		Ended             bool
+		HighestBid        *big.Int
+		HighestBidder     common.Address
+		NftContract       common.Address
+		NftId             *big.Int
+		TokenAddress      common.Address
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Seller = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Duration = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.StartTime = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.StartPrice = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.StartTokenAddress = *abi.ConvertType(out[4], new(common.Address)).(*common.Address)
+	outstruct.Ended = *abi.ConvertType(out[5], new(bool)).(*bool)
+	outstruct.HighestBid = *abi.ConvertType(out[6], new(*big.Int)).(**big.Int)
+	outstruct.HighestBidder = *abi.ConvertType(out[7], new(common.Address)).(*common.Address)
+	outstruct.NftContract = *abi.ConvertType(out[8], new(common.Address)).(*common.Address)
+	outstruct.NftId = *abi.ConvertType(out[9], new(*big.Int)).(**big.Int)
+	outstruct.TokenAddress = *abi.ConvertType(out[10], new(common.Address)).(*common.Address)
+
+	return *outstruct, err
+
+}
+
+// Auctions is a free data retrieval call binding the contract method 0x571a26a0.
+//
+// Solidity: function auctions(uint256 ) view returns(address seller, uint256 duration, uint256 startTime, uint256 startPrice, address startTokenAddress, bool ended, uint256 highestBid, address highestBidder, address nftContract, uint256 nftId, address tokenAddress)
+func (_NFTAuction *NFTAuctionSession) Auctions(arg0 *big.Int) (struct {
+	Seller            common.Address
+	Duration          *big.Int
+	StartTime         *big.Int
+	StartPrice        *big.Int
+	StartTokenAddress common.Address
+	Ended             bool
+	HighestBid        *big.Int
+	HighestBidder     common.Address
+	NftContract       common.Address
+	NftId             *big.Int
+	TokenAddress      common.Address
+}, error) {
+	return _NFTAuction.Contract.Auctions(&_NFTAuction.CallOpts, arg0)
+}
+
+// Auctions is a free data retrieval call binding the contract method 0x571a26a0.
+//
+// Solidity: function auctions(uint256 ) view returns(address seller, uint256 duration, uint256 startTime, uint256 startPrice, address startTokenAddress, bool ended, uint256 highestBid, address highestBidder, address nftContract, uint256 nftId, address tokenAddress)
+func (_NFTAuction *NFTAuctionCallerSession) Auctions(arg0 *big.Int) (struct {
+	Seller            common.Address
+	Duration          *big.Int
+	StartTime         *big.Int
+	StartPrice        *big.Int
+	StartTokenAddress common.Address
+	Ended             bool
+	HighestBid        *big.Int
+	HighestBidder     common.Address
+	NftContract       common.Address
+	NftId             *big.Int
+	TokenAddress      common.Address
+}, error) {
+	return _NFTAuction.Contract.Auctions(&_NFTAuction.CallOpts, arg0)
+}
+
+// CalculateValue is a free data retrieval call binding the contract method 0xbf9e0e18.
+//
+// Solidity: function calculateValue(uint256 _amount, address _tokenAddress) view returns(uint256)
+func (_NFTAuction *NFTAuctionCaller) CalculateValue(opts *bind.CallOpts, _amount *big.Int, _tokenAddress common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _NFTAuction.contract.Call(opts, &out, "calculateValue", _amount, _tokenAddress)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// CalculateValue is a free data retrieval call binding the contract method 0xbf9e0e18.
+//
+// Solidity: function calculateValue(uint256 _amount, address _tokenAddress) view returns(uint256)
+func (_NFTAuction *NFTAuctionSession) CalculateValue(_amount *big.Int, _tokenAddress common.Address) (*big.Int, error) {
+	return _NFTAuction.Contract.CalculateValue(&_NFTAuction.CallOpts, _amount, _tokenAddress)
+}
+
+// CalculateValue is a free data retrieval call binding the contract method 0xbf9e0e18.
+//
+// Solidity: function calculateValue(uint256 _amount, address _tokenAddress) view returns(uint256)
+func (_NFTAuction *NFTAuctionCallerSession) CalculateValue(_amount *big.Int, _tokenAddress common.Address) (*big.Int, error) {
+	return _NFTAuction.Contract.CalculateValue(&_NFTAuction.CallOpts, _amount, _tokenAddress)
+}
+
+// GetChainlinkDataFeedLatestAnswer is a free data retrieval call binding the contract method 0xe7078f92.
+//
+// Solidity: function getChainlinkDataFeedLatestAnswer(address _tokenAddress) view returns(int256)
+func (_NFTAuction *NFTAuctionCaller) GetChainlinkDataFeedLatestAnswer(opts *bind.CallOpts, _tokenAddress common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _NFTAuction.contract.Call(opts, &out, "getChainlinkDataFeedLatestAnswer", _tokenAddress)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetChainlinkDataFeedLatestAnswer is a free data retrieval call binding the contract method 0xe7078f92.
+//
+// Solidity: function getChainlinkDataFeedLatestAnswer(address _tokenAddress) view returns(int256)
+func (_NFTAuction *NFTAuctionSession) GetChainlinkDataFeedLatestAnswer(_tokenAddress common.Address) (*big.Int, error) {
+	return _NFTAuction.Contract.GetChainlinkDataFeedLatestAnswer(&_NFTAuction.CallOpts, _tokenAddress)
+}
+
+// GetChainlinkDataFeedLatestAnswer is a free data retrieval call binding the contract method 0xe7078f92.
+//
+// Solidity: function getChainlinkDataFeedLatestAnswer(address _tokenAddress) view returns(int256)
+func (_NFTAuction *NFTAuctionCallerSession) GetChainlinkDataFeedLatestAnswer(_tokenAddress common.Address) (*big.Int, error) {
+	return _NFTAuction.Contract.GetChainlinkDataFeedLatestAnswer(&_NFTAuction.CallOpts, _tokenAddress)
+}
+
+// NextAuctionId is a free data retrieval call binding the contract method 0xfc528482.
+//
+// Solidity: function nextAuctionId() view returns(uint256)
+func (_NFTAuction *NFTAuctionCaller) NextAuctionId(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _NFTAuction.contract.Call(opts, &out, "nextAuctionId")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// NextAuctionId is a free data retrieval call binding the contract method 0xfc528482.
+//
+// Solidity: function nextAuctionId() view returns(uint256)
+func (_NFTAuction *NFTAuctionSession) NextAuctionId() (*big.Int, error) {
+	return _NFTAuction.Contract.NextAuctionId(&_NFTAuction.CallOpts)
+}
+
+// NextAuctionId is a free data retrieval call binding the contract method 0xfc528482.
+//
+// Solidity: function nextAuctionId() view returns(uint256)
+func (_NFTAuction *NFTAuctionCallerSession) NextAuctionId() (*big.Int, error) {
+	return _NFTAuction.Contract.NextAuctionId(&_NFTAuction.CallOpts)
+}
+
+// OnERC721Received is a free data retrieval call binding the contract method 0x150b7a02.
+//
+// Solidity: function onERC721Received(address , address , uint256 , bytes ) pure returns(bytes4)
+func (_NFTAuction *NFTAuctionCaller) OnERC721Received(opts *bind.CallOpts, arg0 common.Address, arg1 common.Address, arg2 *big.Int, arg3 []byte) ([4]byte, error) {
+	var out []interface{}
+	err := _NFTAuction.contract.Call(opts, &out, "onERC721Received", arg0, arg1, arg2, arg3)
+
+	if err != nil {
+		return *new([4]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([4]byte)).(*[4]byte)
+
+	return out0, err
+
+}
+
+// OnERC721Received is a free data retrieval call binding the contract method 0x150b7a02.
+//
+// Solidity: function onERC721Received(address , address , uint256 , bytes ) pure returns(bytes4)
+func (_NFTAuction *NFTAuctionSession) OnERC721Received(arg0 common.Address, arg1 common.Address, arg2 *big.Int, arg3 []byte) ([4]byte, error) {
+	return _NFTAuction.Contract.OnERC721Received(&_NFTAuction.CallOpts, arg0, arg1, arg2, arg3)
+}
+
+// OnERC721Received is a free data retrieval call binding the contract method 0x150b7a02.
+//
+// Solidity: function onERC721Received(address , address , uint256 , bytes ) pure returns(bytes4)
+func (_NFTAuction *NFTAuctionCallerSession) OnERC721Received(arg0 common.Address, arg1 common.Address, arg2 *big.Int, arg3 []byte) ([4]byte, error) {
+	return _NFTAuction.Contract.OnERC721Received(&_NFTAuction.CallOpts, arg0, arg1, arg2, arg3)
+}
+
+// PriceFeedDecimals is a free data retrieval call binding the contract method 0x630c9775.
+//
+// Solidity: function priceFeedDecimals(address ) view returns(uint8)
+func (_NFTAuction *NFTAuctionCaller) PriceFeedDecimals(opts *bind.CallOpts, arg0 common.Address) (uint8, error) {
+	var out []interface{}
+	err := _NFTAuction.contract.Call(opts, &out, "priceFeedDecimals", arg0)
+
+	if err != nil {
+		return *new(uint8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	return out0, err
+
+}
+
+// PriceFeedDecimals is a free data retrieval call binding the contract method 0x630c9775.
+//
+// Solidity: function priceFeedDecimals(address ) view returns(uint8)
+func (_NFTAuction *NFTAuctionSession) PriceFeedDecimals(arg0 common.Address) (uint8, error) {
+	return _NFTAuction.Contract.PriceFeedDecimals(&_NFTAuction.CallOpts, arg0)
+}
+
+// PriceFeedDecimals is a free data retrieval call binding the contract method 0x630c9775.
+//
+// Solidity: function priceFeedDecimals(address ) view returns(uint8)
+func (_NFTAuction *NFTAuctionCallerSession) PriceFeedDecimals(arg0 common.Address) (uint8, error) {
+	return _NFTAuction.Contract.PriceFeedDecimals(&_NFTAuction.CallOpts, arg0)
+}
+
+// PriceFeeds is a free data retrieval call binding the contract method 0x9dcb511a.
+//
+// Solidity: function priceFeeds(address ) view returns(address)
+func (_NFTAuction *NFTAuctionCaller) PriceFeeds(opts *bind.CallOpts, arg0 common.Address) (common.Address, error) {
+	var out []interface{}
+	err := _NFTAuction.contract.Call(opts, &out, "priceFeeds", arg0)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// PriceFeeds is a free data retrieval call binding the contract method 0x9dcb511a.
+//
+// Solidity: function priceFeeds(address ) view returns(address)
+func (_NFTAuction *NFTAuctionSession) PriceFeeds(arg0 common.Address) (common.Address, error) {
+	return _NFTAuction.Contract.PriceFeeds(&_NFTAuction.CallOpts, arg0)
+}
+
+// PriceFeeds is a free data retrieval call binding the contract method 0x9dcb511a.
+//
+// Solidity: function priceFeeds(address ) view returns(address)
+func (_NFTAuction *NFTAuctionCallerSession) PriceFeeds(arg0 common.Address) (common.Address, error) {
+	return _NFTAuction.Contract.PriceFeeds(&_NFTAuction.CallOpts, arg0)
+}
+
+// ProxiableUUID is a free data retrieval call binding the contract method 0x52d1902d.
+//
+// Solidity: function proxiableUUID() view returns(bytes32)
+func (_NFTAuction *NFTAuctionCaller) ProxiableUUID(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _NFTAuction.contract.Call(opts, &out, "proxiableUUID")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ProxiableUUID is a free data retrieval call binding the contract method 0x52d1902d.
+//
+// Solidity: function proxiableUUID() view returns(bytes32)
+func (_NFTAuction *NFTAuctionSession) ProxiableUUID() ([32]byte, error) {
+	return _NFTAuction.Contract.ProxiableUUID(&_NFTAuction.CallOpts)
+}
+
+// ProxiableUUID is a free data retrieval call binding the contract method 0x52d1902d.
+//
+// Solidity: function proxiableUUID() view returns(bytes32)
+func (_NFTAuction *NFTAuctionCallerSession) ProxiableUUID() ([32]byte, error) {
+	return _NFTAuction.Contract.ProxiableUUID(&_NFTAuction.CallOpts)
+}
+
+// CreateAuction is a paid mutator transaction binding the contract method 0x80acf05a.
+//
+// Solidity: function createAuction(address _seller, uint256 _duration, uint256 _startPrice, address _nftContract, uint256 _nftId) returns()
+func (_NFTAuction *NFTAuctionTransactor) CreateAuction(opts *bind.TransactOpts, _seller common.Address, _duration *big.Int, _startPrice *big.Int, _nftContract common.Address, _nftId *big.Int) (*types.Transaction, error) {
+	return _NFTAuction.contract.Transact(opts, "createAuction", _seller, _duration, _startPrice, _nftContract, _nftId)
+}
+
+// CreateAuction is a paid mutator transaction binding the contract method 0x80acf05a.
+//
+// Solidity: function createAuction(address _seller, uint256 _duration, uint256 _startPrice, address _nftContract, uint256 _nftId) returns()
+func (_NFTAuction *NFTAuctionSession) CreateAuction(_seller common.Address, _duration *big.Int, _startPrice *big.Int, _nftContract common.Address, _nftId *big.Int) (*types.Transaction, error) {
+	return _NFTAuction.Contract.CreateAuction(&_NFTAuction.TransactOpts, _seller, _duration, _startPrice, _nftContract, _nftId)
+}
+
+// CreateAuction is a paid mutator transaction binding the contract method 0x80acf05a.
+//
+// Solidity: function createAuction(address _seller, uint256 _duration, uint256 _startPrice, address _nftContract, uint256 _nftId) returns()
+func (_NFTAuction *NFTAuctionTransactorSession) CreateAuction(_seller common.Address, _duration *big.Int, _startPrice *big.Int, _nftContract common.Address, _nftId *big.Int) (*types.Transaction, error) {
+	return _NFTAuction.Contract.CreateAuction(&_NFTAuction.TransactOpts, _seller, _duration, _startPrice, _nftContract, _nftId)
+}
+
+// EndAuction is a paid mutator transaction binding the contract method 0xb9a2de3a.
+//
+// Solidity: function endAuction(uint256 _auctionId) returns()
+func (_NFTAuction *NFTAuctionTransactor) EndAuction(opts *bind.TransactOpts, _auctionId *big.Int) (*types.Transaction, error) {
+	return _NFTAuction.contract.Transact(opts, "endAuction", _auctionId)
+}
+
+// EndAuction is a paid mutator transaction binding the contract method 0xb9a2de3a.
+//
+// Solidity: function endAuction(uint256 _auctionId) returns()
+func (_NFTAuction *NFTAuctionSession) EndAuction(_auctionId *big.Int) (*types.Transaction, error) {
+	return _NFTAuction.Contract.EndAuction(&_NFTAuction.TransactOpts, _auctionId)
+}
+
+// EndAuction is a paid mutator transaction binding the contract method 0xb9a2de3a.
+//
+// Solidity: function endAuction(uint256 _auctionId) returns()
+func (_NFTAuction *NFTAuctionTransactorSession) EndAuction(_auctionId *big.Int) (*types.Transaction, error) {
+	return _NFTAuction.Contract.EndAuction(&_NFTAuction.TransactOpts, _auctionId)
+}
+
+// Initialize is a paid mutator transaction binding the contract method 0x8129fc1c.
+//
+// Solidity: function initialize() returns()
+func (_NFTAuction *NFTAuctionTransactor) Initialize(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NFTAuction.contract.Transact(opts, "initialize")
+}
+
+// Initialize is a paid mutator transaction binding the contract method 0x8129fc1c.
+//
+// Solidity: function initialize() returns()
+func (_NFTAuction *NFTAuctionSession) Initialize() (*types.Transaction, error) {
+	return _NFTAuction.Contract.Initialize(&_NFTAuction.TransactOpts)
+}
+
+// Initialize is a paid mutator transaction binding the contract method 0x8129fc1c.
+//
+// Solidity: function initialize() returns()
+func (_NFTAuction *NFTAuctionTransactorSession) Initialize() (*types.Transaction, error) {
+	return _NFTAuction.Contract.Initialize(&_NFTAuction.TransactOpts)
+}
+
+// PlaceBid is a paid mutator transaction binding the contract method 0xad6561ec.
+//
+// Solidity: function placeBid(uint256 _auctionId, uint256 _amount, address _tokenAddress) payable returns()
+func (_NFTAuction *NFTAuctionTransactor) PlaceBid(opts *bind.TransactOpts, _auctionId *big.Int, _amount *big.Int, _tokenAddress common.Address) (*types.Transaction, error) {
+	return _NFTAuction.contract.Transact(opts, "placeBid", _auctionId, _amount, _tokenAddress)
+}
+
+// PlaceBid is a paid mutator transaction binding the contract method 0xad6561ec.
+//
+// Solidity: function placeBid(uint256 _auctionId, uint256 _amount, address _tokenAddress) payable returns()
+func (_NFTAuction *NFTAuctionSession) PlaceBid(_auctionId *big.Int, _amount *big.Int, _tokenAddress common.Address) (*types.Transaction, error) {
+	return _NFTAuction.Contract.PlaceBid(&_NFTAuction.TransactOpts, _auctionId, _amount, _tokenAddress)
+}
+
+// PlaceBid is a paid mutator transaction binding the contract method 0xad6561ec.
+//
+// Solidity: function placeBid(uint256 _auctionId, uint256 _amount, address _tokenAddress) payable returns()
+func (_NFTAuction *NFTAuctionTransactorSession) PlaceBid(_auctionId *big.Int, _amount *big.Int, _tokenAddress common.Address) (*types.Transaction, error) {
+	return _NFTAuction.Contract.PlaceBid(&_NFTAuction.TransactOpts, _auctionId, _amount, _tokenAddress)
+}
+
+// SetPriceETHFeed is a paid mutator transaction binding the contract method 0xa48e3815.
+//
+// Solidity: function setPriceETHFeed(address _tokenAddress, address _priceFeedAddress) returns()
+func (_NFTAuction *NFTAuctionTransactor) SetPriceETHFeed(opts *bind.TransactOpts, _tokenAddress common.Address, _priceFeedAddress common.Address) (*types.Transaction, error) {
+	return _NFTAuction.contract.Transact(opts, "setPriceETHFeed", _tokenAddress, _priceFeedAddress)
+}
+
+// SetPriceETHFeed is a paid mutator transaction binding the contract method 0xa48e3815.
+//
+// Solidity: function setPriceETHFeed(address _tokenAddress, address _priceFeedAddress) returns()
+func (_NFTAuction *NFTAuctionSession) SetPriceETHFeed(_tokenAddress common.Address, _priceFeedAddress common.Address) (*types.Transaction, error) {
+	return _NFTAuction.Contract.SetPriceETHFeed(&_NFTAuction.TransactOpts, _tokenAddress, _priceFeedAddress)
+}
+
+// SetPriceETHFeed is a paid mutator transaction binding the contract method 0xa48e3815.
+//
+// Solidity: function setPriceETHFeed(address _tokenAddress, address _priceFeedAddress) returns()
+func (_NFTAuction *NFTAuctionTransactorSession) SetPriceETHFeed(_tokenAddress common.Address, _priceFeedAddress common.Address) (*types.Transaction, error) {
+	return _NFTAuction.Contract.SetPriceETHFeed(&_NFTAuction.TransactOpts, _tokenAddress, _priceFeedAddress)
+}
+
+// UpgradeToAndCall is a paid mutator transaction binding the contract method 0x4f1ef286.
+//
+// Solidity: function upgradeToAndCall(address newImplementation, bytes data) payable returns()
+func (_NFTAuction *NFTAuctionTransactor) UpgradeToAndCall(opts *bind.TransactOpts, newImplementation common.Address, data []byte) (*types.Transaction, error) {
+	return _NFTAuction.contract.Transact(opts, "upgradeToAndCall", newImplementation, data)
+}
+
+// UpgradeToAndCall is a paid mutator transaction binding the contract method 0x4f1ef286.
+//
+// Solidity: function upgradeToAndCall(address newImplementation, bytes data) payable returns()
+func (_NFTAuction *NFTAuctionSession) UpgradeToAndCall(newImplementation common.Address, data []byte) (*types.Transaction, error) {
+	return _NFTAuction.Contract.UpgradeToAndCall(&_NFTAuction.TransactOpts, newImplementation, data)
+}
+
+// UpgradeToAndCall is a paid mutator transaction binding the contract method 0x4f1ef286.
+//
+// Solidity: function upgradeToAndCall(address newImplementation, bytes data) payable returns()
+func (_NFTAuction *NFTAuctionTransactorSession) UpgradeToAndCall(newImplementation common.Address, data []byte) (*types.Transaction, error) {
+	return _NFTAuction.Contract.UpgradeToAndCall(&_NFTAuction.TransactOpts, newImplementation, data)
+}
+
+// NFTAuctionCreateAuctionIterator is returned from FilterCreateAuction and is used to iterate over the raw logs and unpacked data for CreateAuction events raised by the NFTAuction contract.
+type NFTAuctionCreateAuctionIterator struct {
+	Event *NFTAuctionCreateAuction // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *NFTAuctionCreateAuctionIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(NFTAuctionCreateAuction)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(NFTAuctionCreateAuction)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *NFTAuctionCreateAuctionIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *NFTAuctionCreateAuctionIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// NFTAuctionCreateAuction represents a CreateAuction event raised by the NFTAuction contract.
+type NFTAuctionCreateAuction struct {
+	AuctionId         *big.Int
+	Seller            common.Address
+	Duration          *big.Int
+	StartPrice        *big.Int
+	StartTokenAddress common.Address
+	StartTime         *big.Int
+	NftContract       common.Address
+	NftId             *big.Int
+	OptTime           *big.Int
+	Raw               types.Log // Blockchain specific contextual infos
+}
+
+// FilterCreateAuction is a free log retrieval operation binding the contract event 0x38c758d48e834ec972a619b42a5bb0497a864bdb13bd57264c3fb3feff0799e7.
+//
+// Solidity: event CreateAuction(uint256 indexed auctionId, address seller, uint256 duration, uint256 startPrice, address startTokenAddress, uint256 startTime, address nftContract, uint256 nftId, uint256 optTime)
+func (_NFTAuction *NFTAuctionFilterer) FilterCreateAuction(opts *bind.FilterOpts, auctionId []*big.Int) (*NFTAuctionCreateAuctionIterator, error) {
+
+	var auctionIdRule []interface{}
+	for _, auctionIdItem := range auctionId {
+		auctionIdRule = append(auctionIdRule, auctionIdItem)
+	}
+
+	logs, sub, err := _NFTAuction.contract.FilterLogs(opts, "CreateAuction", auctionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &NFTAuctionCreateAuctionIterator{contract: _NFTAuction.contract, event: "CreateAuction", logs: logs, sub: sub}, nil
+}
+
+// WatchCreateAuction is a free log subscription operation binding the contract event 0x38c758d48e834ec972a619b42a5bb0497a864bdb13bd57264c3fb3feff0799e7.
+//
+// Solidity: event CreateAuction(uint256 indexed auctionId, address seller, uint256 duration, uint256 startPrice, address startTokenAddress, uint256 startTime, address nftContract, uint256 nftId, uint256 optTime)
+func (_NFTAuction *NFTAuctionFilterer) WatchCreateAuction(opts *bind.WatchOpts, sink chan<- *NFTAuctionCreateAuction, auctionId []*big.Int) (event.Subscription, error) {
+
+	var auctionIdRule []interface{}
+	for _, auctionIdItem := range auctionId {
+		auctionIdRule = append(auctionIdRule, auctionIdItem)
+	}
+
+	logs, sub, err := _NFTAuction.contract.WatchLogs(opts, "CreateAuction", auctionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(NFTAuctionCreateAuction)
+				if err := _NFTAuction.contract.UnpackLog(event, "CreateAuction", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCreateAuction is a log parse operation binding the contract event 0x38c758d48e834ec972a619b42a5bb0497a864bdb13bd57264c3fb3feff0799e7.
+//
+// Solidity: event CreateAuction(uint256 indexed auctionId, address seller, uint256 duration, uint256 startPrice, address startTokenAddress, uint256 startTime, address nftContract, uint256 nftId, uint256 optTime)
+func (_NFTAuction *NFTAuctionFilterer) ParseCreateAuction(log types.Log) (*NFTAuctionCreateAuction, error) {
+	event := new(NFTAuctionCreateAuction)
+	if err := _NFTAuction.contract.UnpackLog(event, "CreateAuction", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// NFTAuctionEndAuctionIterator is returned from FilterEndAuction and is used to iterate over the raw logs and unpacked data for EndAuction events raised by the NFTAuction contract.
+type NFTAuctionEndAuctionIterator struct {
+	Event *NFTAuctionEndAuction // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *NFTAuctionEndAuctionIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(NFTAuctionEndAuction)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(NFTAuctionEndAuction)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *NFTAuctionEndAuctionIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *NFTAuctionEndAuctionIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// NFTAuctionEndAuction represents a EndAuction event raised by the NFTAuction contract.
+type NFTAuctionEndAuction struct {
+	AuctionId    *big.Int
+	Winner       common.Address
+	Amount       *big.Int
+	TokenAddress common.Address
+	OptTime      *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterEndAuction is a free log retrieval operation binding the contract event 0x110c99ce57b4cd783943e6095b440ff0d6a0e79cf6d6ccba60d17a8eb3ab6dc7.
+//
+// Solidity: event EndAuction(uint256 indexed auctionId, address winner, uint256 amount, address tokenAddress, uint256 optTime)
+func (_NFTAuction *NFTAuctionFilterer) FilterEndAuction(opts *bind.FilterOpts, auctionId []*big.Int) (*NFTAuctionEndAuctionIterator, error) {
+
+	var auctionIdRule []interface{}
+	for _, auctionIdItem := range auctionId {
+		auctionIdRule = append(auctionIdRule, auctionIdItem)
+	}
+
+	logs, sub, err := _NFTAuction.contract.FilterLogs(opts, "EndAuction", auctionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &NFTAuctionEndAuctionIterator{contract: _NFTAuction.contract, event: "EndAuction", logs: logs, sub: sub}, nil
+}
+
+// WatchEndAuction is a free log subscription operation binding the contract event 0x110c99ce57b4cd783943e6095b440ff0d6a0e79cf6d6ccba60d17a8eb3ab6dc7.
+//
+// Solidity: event EndAuction(uint256 indexed auctionId, address winner, uint256 amount, address tokenAddress, uint256 optTime)
+func (_NFTAuction *NFTAuctionFilterer) WatchEndAuction(opts *bind.WatchOpts, sink chan<- *NFTAuctionEndAuction, auctionId []*big.Int) (event.Subscription, error) {
+
+	var auctionIdRule []interface{}
+	for _, auctionIdItem := range auctionId {
+		auctionIdRule = append(auctionIdRule, auctionIdItem)
+	}
+
+	logs, sub, err := _NFTAuction.contract.WatchLogs(opts, "EndAuction", auctionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(NFTAuctionEndAuction)
+				if err := _NFTAuction.contract.UnpackLog(event, "EndAuction", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseEndAuction is a log parse operation binding the contract event 0x110c99ce57b4cd783943e6095b440ff0d6a0e79cf6d6ccba60d17a8eb3ab6dc7.
+//
+// Solidity: event EndAuction(uint256 indexed auctionId, address winner, uint256 amount, address tokenAddress, uint256 optTime)
+func (_NFTAuction *NFTAuctionFilterer) ParseEndAuction(log types.Log) (*NFTAuctionEndAuction, error) {
+	event := new(NFTAuctionEndAuction)
+	if err := _NFTAuction.contract.UnpackLog(event, "EndAuction", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// NFTAuctionInitializedIterator is returned from FilterInitialized and is used to iterate over the raw logs and unpacked data for Initialized events raised by the NFTAuction contract.
+type NFTAuctionInitializedIterator struct {
+	Event *NFTAuctionInitialized // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *NFTAuctionInitializedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(NFTAuctionInitialized)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(NFTAuctionInitialized)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *NFTAuctionInitializedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *NFTAuctionInitializedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// NFTAuctionInitialized represents a Initialized event raised by the NFTAuction contract.
+type NFTAuctionInitialized struct {
+	Version uint64
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterInitialized is a free log retrieval operation binding the contract event 0xc7f505b2f371ae2175ee4913f4499e1f2633a7b5936321eed1cdaeb6115181d2.
+//
+// Solidity: event Initialized(uint64 version)
+func (_NFTAuction *NFTAuctionFilterer) FilterInitialized(opts *bind.FilterOpts) (*NFTAuctionInitializedIterator, error) {
+
+	logs, sub, err := _NFTAuction.contract.FilterLogs(opts, "Initialized")
+	if err != nil {
+		return nil, err
+	}
+	return &NFTAuctionInitializedIterator{contract: _NFTAuction.contract, event: "Initialized", logs: logs, sub: sub}, nil
+}
+
+// WatchInitialized is a free log subscription operation binding the contract event 0xc7f505b2f371ae2175ee4913f4499e1f2633a7b5936321eed1cdaeb6115181d2.
+//
+// Solidity: event Initialized(uint64 version)
+func (_NFTAuction *NFTAuctionFilterer) WatchInitialized(opts *bind.WatchOpts, sink chan<- *NFTAuctionInitialized) (event.Subscription, error) {
+
+	logs, sub, err := _NFTAuction.contract.WatchLogs(opts, "Initialized")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(NFTAuctionInitialized)
+				if err := _NFTAuction.contract.UnpackLog(event, "Initialized", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseInitialized is a log parse operation binding the contract event 0xc7f505b2f371ae2175ee4913f4499e1f2633a7b5936321eed1cdaeb6115181d2.
+//
+// Solidity: event Initialized(uint64 version)
+func (_NFTAuction *NFTAuctionFilterer) ParseInitialized(log types.Log) (*NFTAuctionInitialized, error) {
+	event := new(NFTAuctionInitialized)
+	if err := _NFTAuction.contract.UnpackLog(event, "Initialized", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// NFTAuctionPlaceBidIterator is returned from FilterPlaceBid and is used to iterate over the raw logs and unpacked data for PlaceBid events raised by the NFTAuction contract.
+type NFTAuctionPlaceBidIterator struct {
+	Event *NFTAuctionPlaceBid // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *NFTAuctionPlaceBidIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(NFTAuctionPlaceBid)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(NFTAuctionPlaceBid)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *NFTAuctionPlaceBidIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *NFTAuctionPlaceBidIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// NFTAuctionPlaceBid represents a PlaceBid event raised by the NFTAuction contract.
+type NFTAuctionPlaceBid struct {
+	AuctionId    *big.Int
+	Bidder       common.Address
+	Amount       *big.Int
+	TokenAddress common.Address
+	OptTime      *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterPlaceBid is a free log retrieval operation binding the contract event 0x3ce1cf1b5d0ae43f12d719a1a1966f0be27c2056a62d132dccc818ab34bdd771.
+//
+// Solidity: event PlaceBid(uint256 indexed auctionId, address bidder, uint256 amount, address tokenAddress, uint256 optTime)
+func (_NFTAuction *NFTAuctionFilterer) FilterPlaceBid(opts *bind.FilterOpts, auctionId []*big.Int) (*NFTAuctionPlaceBidIterator, error) {
+
+	var auctionIdRule []interface{}
+	for _, auctionIdItem := range auctionId {
+		auctionIdRule = append(auctionIdRule, auctionIdItem)
+	}
+
+	logs, sub, err := _NFTAuction.contract.FilterLogs(opts, "PlaceBid", auctionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &NFTAuctionPlaceBidIterator{contract: _NFTAuction.contract, event: "PlaceBid", logs: logs, sub: sub}, nil
+}
+
+// WatchPlaceBid is a free log subscription operation binding the contract event 0x3ce1cf1b5d0ae43f12d719a1a1966f0be27c2056a62d132dccc818ab34bdd771.
+//
+// Solidity: event PlaceBid(uint256 indexed auctionId, address bidder, uint256 amount, address tokenAddress, uint256 optTime)
+func (_NFTAuction *NFTAuctionFilterer) WatchPlaceBid(opts *bind.WatchOpts, sink chan<- *NFTAuctionPlaceBid, auctionId []*big.Int) (event.Subscription, error) {
+
+	var auctionIdRule []interface{}
+	for _, auctionIdItem := range auctionId {
+		auctionIdRule = append(auctionIdRule, auctionIdItem)
+	}
+
+	logs, sub, err := _NFTAuction.contract.WatchLogs(opts, "PlaceBid", auctionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(NFTAuctionPlaceBid)
+				if err := _NFTAuction.contract.UnpackLog(event, "PlaceBid", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParsePlaceBid is a log parse operation binding the contract event 0x3ce1cf1b5d0ae43f12d719a1a1966f0be27c2056a62d132dccc818ab34bdd771.
+//
+// Solidity: event PlaceBid(uint256 indexed auctionId, address bidder, uint256 amount, address tokenAddress, uint256 optTime)
+func (_NFTAuction *NFTAuctionFilterer) ParsePlaceBid(log types.Log) (*NFTAuctionPlaceBid, error) {
+	event := new(NFTAuctionPlaceBid)
+	if err := _NFTAuction.contract.UnpackLog(event, "PlaceBid", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// NFTAuctionUpgradedIterator is returned from FilterUpgraded and is used to iterate over the raw logs and unpacked data for Upgraded events raised by the NFTAuction contract.
+type NFTAuctionUpgradedIterator struct {
+	Event *NFTAuctionUpgraded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *NFTAuctionUpgradedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(NFTAuctionUpgraded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(NFTAuctionUpgraded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *NFTAuctionUpgradedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *NFTAuctionUpgradedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// NFTAuctionUpgraded represents a Upgraded event raised by the NFTAuction contract.
+type NFTAuctionUpgraded struct {
+	Implementation common.Address
+	Raw            types.Log // Blockchain specific contextual infos
+}
+
+// FilterUpgraded is a free log retrieval operation binding the contract event 0xbc7cd75a20ee27fd9adebab32041f755214dbc6bffa90cc0225b39da2e5c2d3b.
+//
+// Solidity: event Upgraded(address indexed implementation)
+func (_NFTAuction *NFTAuctionFilterer) FilterUpgraded(opts *bind.FilterOpts, implementation []common.Address) (*NFTAuctionUpgradedIterator, error) {
+
+	var implementationRule []interface{}
+	for _, implementationItem := range implementation {
+		implementationRule = append(implementationRule, implementationItem)
+	}
+
+	logs, sub, err := _NFTAuction.contract.FilterLogs(opts, "Upgraded", implementationRule)
+	if err != nil {
+		return nil, err
+	}
+	return &NFTAuctionUpgradedIterator{contract: _NFTAuction.contract, event: "Upgraded", logs: logs, sub: sub}, nil
+}
+
+// WatchUpgraded is a free log subscription operation binding the contract event 0xbc7cd75a20ee27fd9adebab32041f755214dbc6bffa90cc0225b39da2e5c2d3b.
+//
+// Solidity: event Upgraded(address indexed implementation)
+func (_NFTAuction *NFTAuctionFilterer) WatchUpgraded(opts *bind.WatchOpts, sink chan<- *NFTAuctionUpgraded, implementation []common.Address) (event.Subscription, error) {
+
+	var implementationRule []interface{}
+	for _, implementationItem := range implementation {
+		implementationRule = append(implementationRule, implementationItem)
+	}
+
+	logs, sub, err := _NFTAuction.contract.WatchLogs(opts, "Upgraded", implementationRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(NFTAuctionUpgraded)
+				if err := _NFTAuction.contract.UnpackLog(event, "Upgraded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseUpgraded is a log parse operation binding the contract event 0xbc7cd75a20ee27fd9adebab32041f755214dbc6bffa90cc0225b39da2e5c2d3b.
+//
+// Solidity: event Upgraded(address indexed implementation)
+func (_NFTAuction *NFTAuctionFilterer) ParseUpgraded(log types.Log) (*NFTAuctionUpgraded, error) {
+	event := new(NFTAuctionUpgraded)
+	if err := _NFTAuction.contract.UnpackLog(event, "Upgraded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,271 @@
+// Package headercache 区块头缓存与链重组检测
+//
+// 监听器解析日志时需要区块时间，逐条调用HeaderByNumber会对同一区块重复请求节点。Cache按区块号缓存
+// 区块哈希、父哈希与时间戳：先查进程内LRU，再查Redis（多个进程/重启后共享），都未命中时才请求节点。
+// 写入新区块头时与已缓存的父区块哈希比对，不一致说明发生了链重组，向前回溯替换失效的缓存并记录日志。
+package headercache
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+	"github.com/ydh2333/NFTAuction-project/internal/redis"
+)
+
+// Header 缓存的区块头字段
+type Header struct {
+	Number     uint64      `json:"number"`
+	Hash       common.Hash `json:"hash"`
+	ParentHash common.Hash `json:"parent_hash"`
+	Time       uint64      `json:"time"`
+}
+
+// Cache 区块头缓存
+type Cache struct {
+	client  chainclient.ChainClient
+	chainID uint64 // Redis中按链区分缓存
+	cfg     config.HeaderCacheConfig
+	lru     *lru.Cache[uint64, Header]
+
+	mu      sync.Mutex // 串行化写入与重组回溯
+	tracked uint64     // Track最后检查的区块号
+}
+
+var (
+	sharedMu sync.Mutex
+	shared   = make(map[chainclient.ChainClient]*Cache)
+)
+
+// For 返回client共享的缓存，同一client上的监听器共用一份缓存，首次调用时按cfg创建
+func For(client chainclient.ChainClient, chainID uint64, cfg config.HeaderCacheConfig) *Cache {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	if c, ok := shared[client]; ok {
+		return c
+	}
+	c := New(client, chainID, cfg)
+	shared[client] = c
+	return c
+}
+
+// New 创建独立的区块头缓存，chainID为client所连接的链
+func New(client chainclient.ChainClient, chainID uint64, cfg config.HeaderCacheConfig) *Cache {
+	if cfg.Size <= 0 {
+		cfg.Size = 4096
+	}
+	if cfg.MaxReorgDepth == 0 {
+		cfg.MaxReorgDepth = 64
+	}
+	return &Cache{
+		client:  client,
+		chainID: chainID,
+		cfg:     cfg,
+		lru:     lru.NewCache[uint64, Header](cfg.Size),
+	}
+}
+
+// Get 查询区块头：LRU → Redis → 节点
+func (c *Cache) Get(ctx context.Context, number uint64) (*Header, error) {
+	if h, ok := c.Cached(number); ok {
+		return h, nil
+	}
+	header, err := c.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, err
+	}
+	return c.Observe(ctx, header), nil
+}
+
+// BlockTime 查询区块时间（Unix秒）
+func (c *Cache) BlockTime(ctx context.Context, number uint64) (uint64, error) {
+	h, err := c.Get(ctx, number)
+	if err != nil {
+		return 0, err
+	}
+	return h.Time, nil
+}
+
+// Cached 只查缓存（LRU、Redis），不请求节点
+func (c *Cache) Cached(number uint64) (*Header, bool) {
+	if h, ok := c.lru.Get(number); ok {
+		return &h, true
+	}
+	if !redis.Ready() {
+		return nil, false
+	}
+	data, ok, err := redis.GetBlockHeader(c.chainID, number)
+	if err != nil {
+		log.Warn().Err(err).Uint64("block", number).Msg("查询Redis区块头缓存失败")
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	var h Header
+	if err := json.Unmarshal(data, &h); err != nil || h.Number != number {
+		return nil, false
+	}
+	c.lru.Add(number, h)
+	return &h, true
+}
+
+// Observe 写入从节点获取的区块头，并检查与已缓存区块的哈希链是否连续
+func (c *Cache) Observe(ctx context.Context, header *types.Header) *Header {
+	h := Header{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash(),
+		ParentHash: header.ParentHash,
+		Time:       header.Time,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var (
+		lowest   = h.Number
+		replaced uint64
+	)
+	if old, ok := c.Cached(h.Number); ok {
+		if old.Hash == h.Hash {
+			return &h
+		}
+		replaced++
+	}
+	c.store(h)
+	if h.Number > 0 {
+		if low, n := c.rewind(ctx, h.Number-1, h.ParentHash); n > 0 {
+			lowest, replaced = low, replaced+n
+		}
+	}
+	if replaced > 0 {
+		log.Warn().Uint64("head", h.Number).Str("hash", h.Hash.Hex()).Uint64("from", lowest).Uint64("depth", replaced).Msg("检测到链重组，已替换失效的区块头缓存")
+	}
+	return &h
+}
+
+// ObserveLog 监听器收到日志时调用：日志所在区块与缓存不一致（或尚未缓存）时从节点刷新该区块头，
+// 因链重组被回滚的日志直接使其区块缓存失效
+func (c *Cache) ObserveLog(ctx context.Context, lg types.Log) {
+	if lg.Removed {
+		c.mu.Lock()
+		c.evict(lg.BlockNumber)
+		c.mu.Unlock()
+		return
+	}
+	if h, ok := c.Cached(lg.BlockNumber); ok && h.Hash == lg.BlockHash {
+		return
+	}
+	header, err := c.client.HeaderByNumber(ctx, new(big.Int).SetUint64(lg.BlockNumber))
+	if err != nil {
+		log.Warn().Err(err).Uint64("block", lg.BlockNumber).Msg("刷新区块头缓存失败")
+		return
+	}
+	c.Observe(ctx, header)
+}
+
+// Track 定期检查最新区块，逐块写入缓存以便及时发现链重组（TrackInterval为0时直接返回）
+func (c *Cache) Track(ctx context.Context) error {
+	if c.cfg.TrackInterval <= 0 {
+		return nil
+	}
+	ticker := time.NewTicker(c.cfg.TrackInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := c.trackOnce(ctx); err != nil {
+				log.Warn().Err(err).Msg("检查最新区块头失败")
+			}
+		}
+	}
+}
+
+// trackOnce 写入上次检查之后的区块头（最多MaxReorgDepth个）
+func (c *Cache) trackOnce(ctx context.Context) error {
+	head, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	from := c.tracked + 1
+	if head >= c.cfg.MaxReorgDepth && from < head-c.cfg.MaxReorgDepth+1 {
+		from = head - c.cfg.MaxReorgDepth + 1
+	}
+	for number := from; number <= head; number++ {
+		header, err := c.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		c.Observe(ctx, header)
+		c.tracked = number
+	}
+	return nil
+}
+
+// rewind 从number开始向前比对：缓存哈希与期望哈希不一致时替换为节点上的规范区块头，
+// 直到一致、未缓存或达到MaxReorgDepth；返回被替换的最低区块号与替换数量（调用方持有mu）
+func (c *Cache) rewind(ctx context.Context, number uint64, expected common.Hash) (uint64, uint64) {
+	var lowest, replaced uint64
+	for replaced < c.cfg.MaxReorgDepth {
+		cached, ok := c.Cached(number)
+		if !ok || cached.Hash == expected {
+			break
+		}
+		c.evict(number)
+		lowest, replaced = number, replaced+1
+		if number == 0 {
+			break
+		}
+
+		header, err := c.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			log.Warn().Err(err).Uint64("block", number).Msg("回溯链重组时获取区块头失败")
+			break
+		}
+		if header.Hash() != expected {
+			// 回溯期间规范链再次变化，剩余部分留给下次写入时检测
+			break
+		}
+		c.store(Header{Number: number, Hash: header.Hash(), ParentHash: header.ParentHash, Time: header.Time})
+		expected = header.ParentHash
+		number--
+	}
+	return lowest, replaced
+}
+
+// store 写入LRU与Redis（Redis写入失败只记录日志）
+func (c *Cache) store(h Header) {
+	c.lru.Add(h.Number, h)
+	if !redis.Ready() {
+		return
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return
+	}
+	if err := redis.SetBlockHeader(c.chainID, h.Number, data, c.cfg.RedisTTL); err != nil {
+		log.Warn().Err(err).Uint64("block", h.Number).Msg("写入Redis区块头缓存失败")
+	}
+}
+
+// evict 删除失效的区块头缓存
+func (c *Cache) evict(number uint64) {
+	c.lru.Remove(number)
+	if !redis.Ready() {
+		return
+	}
+	if err := redis.DelBlockHeader(c.chainID, number); err != nil {
+		log.Warn().Err(err).Uint64("block", number).Msg("删除Redis区块头缓存失败")
+	}
+}
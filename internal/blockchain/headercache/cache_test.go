@@ -0,0 +1,149 @@
+package headercache
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+)
+
+// newChain 出块到height并逐块写入缓存
+func newChain(t *testing.T, height int, maxDepth uint64) (*chainclient.Fake, *Cache) {
+	t.Helper()
+	fake := chainclient.NewFake()
+	for i := 0; i < height; i++ {
+		fake.Mine()
+	}
+	c := New(fake, 1, config.HeaderCacheConfig{MaxReorgDepth: maxDepth})
+	for number := 0; number <= height; number++ {
+		c.Observe(context.Background(), header(t, fake, uint64(number)))
+	}
+	return fake, c
+}
+
+func header(t *testing.T, fake *chainclient.Fake, number uint64) *types.Header {
+	t.Helper()
+	h, err := fake.HeaderByNumber(context.Background(), new(big.Int).SetUint64(number))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// stale 缓存中与规范链哈希不一致的区块号
+func stale(t *testing.T, fake *chainclient.Fake, c *Cache) []uint64 {
+	t.Helper()
+	var numbers []uint64
+	for number := uint64(0); number <= fake.Head().Number.Uint64(); number++ {
+		if h, ok := c.Cached(number); ok && h.Hash != header(t, fake, number).Hash() {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
+
+// TestObserveReorg 写入重组后的新区块头时沿父哈希回溯替换失效缓存
+func TestObserveReorg(t *testing.T) {
+	tests := []struct {
+		name      string
+		height    int
+		maxDepth  uint64
+		depth     int // 回滚的区块数，0表示不重组
+		replace   int // 重组后打包的区块数
+		wantStale []uint64
+	}{
+		{name: "无重组", height: 5},
+		{name: "替换同高度区块", height: 5, depth: 1, replace: 1},
+		{name: "新区块回溯替换祖先", height: 8, depth: 3, replace: 4},
+		{name: "超过最大回溯深度", height: 8, maxDepth: 2, depth: 4, replace: 4, wantStale: []uint64{5}},
+		{name: "父区块未缓存时停止回溯", height: 5, depth: 2, replace: 4, wantStale: []uint64{4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, c := newChain(t, tt.height, tt.maxDepth)
+			if tt.depth > 0 {
+				blocks := make([][]types.Log, tt.replace)
+				if _, err := fake.Reorg(tt.depth, blocks...); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			head := fake.Head()
+			c.Observe(context.Background(), head)
+
+			if got, ok := c.Cached(head.Number.Uint64()); !ok || got.Hash != head.Hash() {
+				t.Fatalf("最新区块未写入缓存")
+			}
+			if got := stale(t, fake, c); !equal(got, tt.wantStale) {
+				t.Errorf("失效缓存 = %v，期望 %v", got, tt.wantStale)
+			}
+		})
+	}
+}
+
+// TestRewind 回溯返回被替换的最低区块号与替换数量
+func TestRewind(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxDepth     uint64
+		depth        int
+		wantLowest   uint64
+		wantReplaced uint64
+	}{
+		{name: "哈希一致", wantLowest: 0, wantReplaced: 0},
+		{name: "替换全部失效区块", depth: 3, wantLowest: 4, wantReplaced: 3},
+		{name: "达到最大回溯深度", maxDepth: 2, depth: 3, wantLowest: 5, wantReplaced: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, c := newChain(t, 6, tt.maxDepth)
+			if tt.depth > 0 {
+				if _, err := fake.Reorg(tt.depth, make([][]types.Log, tt.depth)...); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			lowest, replaced := c.rewind(context.Background(), 6, header(t, fake, 6).Hash())
+			if lowest != tt.wantLowest || replaced != tt.wantReplaced {
+				t.Errorf("rewind = (%d, %d)，期望 (%d, %d)", lowest, replaced, tt.wantLowest, tt.wantReplaced)
+			}
+		})
+	}
+}
+
+// TestObserveLogRemoved 回滚的日志使其区块缓存失效，之后收到的日志从节点刷新区块头
+func TestObserveLogRemoved(t *testing.T) {
+	fake, c := newChain(t, 3, 0)
+	old := header(t, fake, 3)
+
+	c.ObserveLog(context.Background(), types.Log{BlockNumber: 3, BlockHash: old.Hash(), Removed: true})
+	if _, ok := c.Cached(3); ok {
+		t.Fatal("回滚日志所在区块仍在缓存中")
+	}
+
+	if _, err := fake.Reorg(1, nil); err != nil {
+		t.Fatal(err)
+	}
+	current := header(t, fake, 3)
+	c.ObserveLog(context.Background(), types.Log{BlockNumber: 3, BlockHash: current.Hash()})
+	if h, ok := c.Cached(3); !ok || h.Hash != current.Hash() {
+		t.Fatal("新日志所在区块未刷新")
+	}
+}
+
+func equal(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
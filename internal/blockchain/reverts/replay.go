@@ -0,0 +1,42 @@
+package reverts
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Caller 执行eth_call的接口（*ethclient.Client已实现）
+type Caller interface {
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// Replay 在交易所在区块的父区块状态上重放失败交易以获取回滚原因
+// 同区块中排在它之前的交易不会被重放，结果可能与实际执行略有差异
+func (d *Decoder) Replay(ctx context.Context, caller Caller, from common.Address, tx *types.Transaction, receipt *types.Receipt) (*Error, error) {
+	block := receipt.BlockNumber
+	if block != nil && block.Sign() > 0 {
+		block = new(big.Int).Sub(block, big.NewInt(1))
+	}
+	_, err := caller.CallContract(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, block)
+	if err == nil {
+		// 重放成功说明失败依赖同区块内的前序交易（或燃气不足）
+		if receipt.GasUsed >= tx.Gas() {
+			return &Error{Kind: KindUnknown, Name: "OutOfGas", Reason: "燃气耗尽"}, nil
+		}
+		return &Error{Kind: KindUnknown, Reason: "重放未能复现回滚"}, nil
+	}
+	if revert, ok := d.FromError(err); ok {
+		return revert, nil
+	}
+	return nil, err
+}
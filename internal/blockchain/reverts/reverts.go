@@ -0,0 +1,172 @@
+// Package reverts 合约回滚原因解析
+//
+// 将eth_call/eth_estimateGas返回的回滚数据，或失败交易重放得到的回滚数据，解析为结构化错误：
+// Error(string)、Panic(uint256)以及各合约ABI中声明的自定义错误（含参数）。
+package reverts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// 回滚类型
+const (
+	KindError   = "error"   // require/revert("reason")
+	KindPanic   = "panic"   // assert失败、算术溢出等
+	KindCustom  = "custom"  // ABI自定义错误
+	KindUnknown = "unknown" // 无回滚数据或无法识别的选择器
+)
+
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+// Arg 自定义错误参数
+type Arg struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Error 结构化的回滚错误
+type Error struct {
+	Kind      string        `json:"kind"`                 // 回滚类型
+	Name      string        `json:"name"`                 // Error、Panic或自定义错误名
+	Signature string        `json:"signature,omitempty"`  // 错误签名，如AddressEmptyCode(address)
+	Reason    string        `json:"reason"`               // 可读原因
+	Args      []Arg         `json:"args,omitempty"`       // 自定义错误参数
+	PanicCode *big.Int      `json:"panic_code,omitempty"` // Panic错误码
+	Data      hexutil.Bytes `json:"data,omitempty"`       // 原始回滚数据
+}
+
+func (e *Error) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + e.Reason
+}
+
+// Decoder 基于一组合约ABI的回滚解析器
+type Decoder struct {
+	errors map[[4]byte]abi.Error
+}
+
+// NewDecoder 创建解析器，合并各ABI中声明的自定义错误
+func NewDecoder(abis ...*abi.ABI) *Decoder {
+	d := &Decoder{errors: make(map[[4]byte]abi.Error)}
+	for _, contractABI := range abis {
+		if contractABI == nil {
+			continue
+		}
+		for _, customErr := range contractABI.Errors {
+			var selector [4]byte
+			copy(selector[:], customErr.ID[:4])
+			d.errors[selector] = customErr
+		}
+	}
+	return d
+}
+
+// Decode 解析回滚数据
+func (d *Decoder) Decode(data []byte) *Error {
+	revert := &Error{Kind: KindUnknown, Data: data}
+	if len(data) < 4 {
+		revert.Reason = "无回滚原因"
+		return revert
+	}
+
+	selector := data[:4]
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			break
+		}
+		revert.Kind, revert.Name, revert.Signature, revert.Reason = KindError, "Error", "Error(string)", reason
+		return revert
+	case bytes.Equal(selector, panicSelector):
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			break
+		}
+		revert.Kind, revert.Name, revert.Signature, revert.Reason = KindPanic, "Panic", "Panic(uint256)", reason
+		revert.PanicCode = new(big.Int).SetBytes(data[4:])
+		return revert
+	}
+
+	var id [4]byte
+	copy(id[:], selector)
+	if d != nil {
+		if customErr, ok := d.errors[id]; ok {
+			revert.Kind, revert.Name, revert.Signature, revert.Reason = KindCustom, customErr.Name, customErr.Sig, customErr.Name
+			values, err := customErr.Inputs.Unpack(data[4:])
+			if err != nil {
+				return revert
+			}
+			parts := make([]string, 0, len(values))
+			for i, value := range values {
+				input := customErr.Inputs[i]
+				revert.Args = append(revert.Args, Arg{Name: input.Name, Type: input.Type.String(), Value: value})
+				parts = append(parts, fmt.Sprintf("%v", value))
+			}
+			revert.Reason = fmt.Sprintf("%s(%s)", customErr.Name, strings.Join(parts, ", "))
+			return revert
+		}
+	}
+
+	revert.Name = hexutil.Encode(id[:])
+	revert.Reason = "未知错误" + revert.Name
+	return revert
+}
+
+// FromError 从节点返回的错误中解析回滚原因，非回滚错误返回false
+func (d *Decoder) FromError(err error) (*Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var revert *Error
+	if errors.As(err, &revert) {
+		return revert, true
+	}
+	if data, ok := ExtractData(err); ok {
+		return d.Decode(data), true
+	}
+	// 部分节点对无原因的revert不返回data
+	if strings.Contains(err.Error(), "execution reverted") {
+		return &Error{Kind: KindUnknown, Reason: "无回滚原因"}, true
+	}
+	return nil, false
+}
+
+// Wrap 能解析出回滚原因时返回*Error，否则原样返回
+func (d *Decoder) Wrap(err error) error {
+	if revert, ok := d.FromError(err); ok {
+		return revert
+	}
+	return err
+}
+
+// ExtractData 提取JSON-RPC错误中的回滚数据（error.data）
+func ExtractData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return data, true
+}
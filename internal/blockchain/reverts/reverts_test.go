@@ -0,0 +1,112 @@
+package reverts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const testABI = `[
+	{"type":"error","name":"BidTooLow","inputs":[{"name":"bid","type":"uint256"},{"name":"minBid","type":"uint256"}]},
+	{"type":"error","name":"NotSeller","inputs":[{"name":"caller","type":"address"}]}
+]`
+
+func mustDecoder(t *testing.T) (*Decoder, *abi.ABI) {
+	t.Helper()
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewDecoder(&parsed, nil), &parsed
+}
+
+func TestDecode(t *testing.T) {
+	decoder, parsed := mustDecoder(t)
+	stringType, _ := abi.NewType("string", "", nil)
+	uintType, _ := abi.NewType("uint256", "", nil)
+
+	bidTooLow := parsed.Errors["BidTooLow"]
+	customData := append(common.CopyBytes(bidTooLow.ID[:4]), mustPack(t, bidTooLow.Inputs, big.NewInt(1), big.NewInt(2))...)
+
+	tests := []struct {
+		name       string
+		data       []byte
+		wantKind   string
+		wantName   string
+		wantReason string
+		wantArgs   int
+	}{
+		{"无回滚数据", nil, KindUnknown, "", "无回滚原因", 0},
+		{"不足4字节", []byte{0x01, 0x02}, KindUnknown, "", "无回滚原因", 0},
+		{"Error(string)", append(common.CopyBytes(errorSelector), mustPack(t, abi.Arguments{{Type: stringType}}, "auction ended")...), KindError, "Error", "auction ended", 0},
+		{"Panic(uint256)", append(common.CopyBytes(panicSelector), mustPack(t, abi.Arguments{{Type: uintType}}, big.NewInt(0x11))...), KindPanic, "Panic", "arithmetic underflow or overflow", 0},
+		{"自定义错误含参数", customData, KindCustom, "BidTooLow", "BidTooLow(1, 2)", 2},
+		{"自定义错误参数无法解码", common.CopyBytes(bidTooLow.ID[:4]), KindCustom, "BidTooLow", "BidTooLow", 0},
+		{"Error(string)数据损坏", append(common.CopyBytes(errorSelector), 0x01), KindUnknown, "0x08c379a0", "未知错误0x08c379a0", 0},
+		{"未知选择器", []byte{0xde, 0xad, 0xbe, 0xef}, KindUnknown, "0xdeadbeef", "未知错误0xdeadbeef", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decoder.Decode(tt.data)
+			if got.Kind != tt.wantKind || got.Name != tt.wantName || got.Reason != tt.wantReason || len(got.Args) != tt.wantArgs {
+				t.Errorf("Decode = {kind:%s name:%s reason:%q args:%d}, want {kind:%s name:%s reason:%q args:%d}",
+					got.Kind, got.Name, got.Reason, len(got.Args), tt.wantKind, tt.wantName, tt.wantReason, tt.wantArgs)
+			}
+		})
+	}
+}
+
+// dataError 模拟节点返回的带回滚数据的JSON-RPC错误
+type dataError struct {
+	data interface{}
+}
+
+func (e *dataError) Error() string          { return "execution reverted" }
+func (e *dataError) ErrorData() interface{} { return e.data }
+
+func TestFromError(t *testing.T) {
+	decoder, parsed := mustDecoder(t)
+	notSeller := parsed.Errors["NotSeller"]
+	data := append(common.CopyBytes(notSeller.ID[:4]), mustPack(t, notSeller.Inputs, common.HexToAddress("0x1"))...)
+	decoded := &Error{Kind: KindCustom, Name: "NotSeller"}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantOK   bool
+		wantName string
+	}{
+		{"nil", nil, false, ""},
+		{"非回滚错误", errors.New("connection refused"), false, ""},
+		{"已解析的错误", fmt.Errorf("模拟出价失败: %w", decoded), true, "NotSeller"},
+		{"JSON-RPC错误数据", fmt.Errorf("call: %w", &dataError{data: hexutil.Encode(data)}), true, "NotSeller"},
+		{"错误数据不是十六进制", &dataError{data: "oops"}, true, ""},
+		{"无数据的回滚", errors.New("execution reverted"), true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := decoder.FromError(tt.err)
+			if ok != tt.wantOK {
+				t.Fatalf("FromError ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got.Name != tt.wantName {
+				t.Errorf("FromError name = %q, want %q", got.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func mustPack(t *testing.T, args abi.Arguments, values ...interface{}) []byte {
+	t.Helper()
+	packed, err := args.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return packed
+}
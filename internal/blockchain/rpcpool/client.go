@@ -0,0 +1,121 @@
+package rpcpool
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+)
+
+var _ chainclient.ChainClient = (*Pool)(nil)
+
+// ChainID 链ID
+func (p *Pool) ChainID(ctx context.Context) (*big.Int, error) {
+	return call(p, ctx, func(c *ethclient.Client) (*big.Int, error) { return c.ChainID(ctx) })
+}
+
+// BlockNumber 最新区块号
+func (p *Pool) BlockNumber(ctx context.Context) (uint64, error) {
+	return call(p, ctx, func(c *ethclient.Client) (uint64, error) { return c.BlockNumber(ctx) })
+}
+
+// HeaderByNumber 查询区块头，number为nil时返回最新区块
+func (p *Pool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return call(p, ctx, func(c *ethclient.Client) (*types.Header, error) { return c.HeaderByNumber(ctx, number) })
+}
+
+// FilterLogs 查询日志
+func (p *Pool) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return call(p, ctx, func(c *ethclient.Client) ([]types.Log, error) { return c.FilterLogs(ctx, q) })
+}
+
+// CodeAt 查询合约代码
+func (p *Pool) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return call(p, ctx, func(c *ethclient.Client) ([]byte, error) { return c.CodeAt(ctx, contract, blockNumber) })
+}
+
+// PendingCodeAt 查询pending状态的合约代码
+func (p *Pool) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return call(p, ctx, func(c *ethclient.Client) ([]byte, error) { return c.PendingCodeAt(ctx, account) })
+}
+
+// CallContract 只读合约调用
+func (p *Pool) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return call(p, ctx, func(c *ethclient.Client) ([]byte, error) { return c.CallContract(ctx, msg, blockNumber) })
+}
+
+// BalanceAt 查询余额
+func (p *Pool) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return call(p, ctx, func(c *ethclient.Client) (*big.Int, error) { return c.BalanceAt(ctx, account, blockNumber) })
+}
+
+// NonceAt 查询已上链nonce
+func (p *Pool) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return call(p, ctx, func(c *ethclient.Client) (uint64, error) { return c.NonceAt(ctx, account, blockNumber) })
+}
+
+// PendingNonceAt 查询pending nonce
+func (p *Pool) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return call(p, ctx, func(c *ethclient.Client) (uint64, error) { return c.PendingNonceAt(ctx, account) })
+}
+
+// SuggestGasPrice 建议燃气价格
+func (p *Pool) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return call(p, ctx, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasPrice(ctx) })
+}
+
+// SuggestGasTipCap 建议小费上限
+func (p *Pool) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return call(p, ctx, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasTipCap(ctx) })
+}
+
+// EstimateGas 估算燃气
+func (p *Pool) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return call(p, ctx, func(c *ethclient.Client) (uint64, error) { return c.EstimateGas(ctx, msg) })
+}
+
+// SendTransaction 广播已签名交易
+// 节点出错时交易可能已经被该节点广播，切换节点重发同一笔交易会被拒绝："already known"说明交易已在交易池中，
+// "nonce too low"且节点能查到该交易哈希说明交易已上链，两者都视为发送成功
+func (p *Pool) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	retry := false
+	return p.do(ctx, func(c *ethclient.Client) error {
+		err := c.SendTransaction(ctx, tx)
+		if err != nil && retry && sentBefore(ctx, c, tx, err) {
+			log.Info().Str("pool", p.name).Str("hash", tx.Hash().Hex()).Msg("交易已由之前的节点广播")
+			return nil
+		}
+		retry = true
+		return err
+	})
+}
+
+// sentBefore 判断重发被拒绝是否因为同一笔交易已被广播
+func sentBefore(ctx context.Context, c *ethclient.Client, tx *types.Transaction, err error) bool {
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction") {
+		return true
+	}
+	if !strings.Contains(msg, "nonce too low") {
+		return false
+	}
+	found, _, lookupErr := c.TransactionByHash(ctx, tx.Hash())
+	return lookupErr == nil && found.Hash() == tx.Hash()
+}
+
+// TransactionReceipt 查询交易回执
+func (p *Pool) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return call(p, ctx, func(c *ethclient.Client) (*types.Receipt, error) { return c.TransactionReceipt(ctx, txHash) })
+}
+
+// BatchCallContext 在同一节点上批量发送JSON-RPC请求
+func (p *Pool) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	return p.do(ctx, func(c *ethclient.Client) error { return c.Client().BatchCallContext(ctx, batch) })
+}
@@ -0,0 +1,502 @@
+// Package rpcpool 多RPC节点负载均衡与故障切换
+//
+// Pool持有一组同类（HTTP或WebSocket）节点，按权重把请求分配给健康节点；请求因节点原因失败
+// （限流、5xx、连接中断等）时换下一个节点重试，合约回滚等业务错误直接返回。后台定期检查各节点的
+// 最新区块、请求错误率与延迟，落后过多或错误率过高的节点暂时摘除，恢复后自动加回。
+// 每个节点另有独立的令牌桶限流与熔断（resilience.Guard），被限流或熔断的节点本次请求直接跳过。
+// Pool实现了chainclient.ChainClient以及拍卖合约、交易管理所需的接口，可直接替代*ethclient.Client。
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/resilience"
+)
+
+// ErrNoEndpoint 没有可用的节点
+var ErrNoEndpoint = errors.New("没有可用的RPC节点")
+
+// 延迟滑动平均中新样本的权重
+const latencyAlpha = 0.2
+
+// 连续失败达到该次数时立即摘除节点，不等下次健康检查
+const maxConsecutiveErrors = 3
+
+// Pool 一组RPC节点
+type Pool struct {
+	name      string
+	cfg       config.RPCPoolConfig
+	endpoints []*endpoint
+	cancel    context.CancelFunc
+}
+
+// endpoint 单个节点及其统计
+type endpoint struct {
+	url    string
+	weight int
+	guard  *resilience.Guard
+
+	mu            sync.Mutex
+	client        *ethclient.Client // 连接失败时为nil，健康检查时重连
+	healthy       bool
+	head          uint64
+	latency       time.Duration // 请求延迟的滑动平均
+	requests      uint64        // 本检查周期的请求数
+	errors        uint64        // 本检查周期的节点错误数
+	totalRequests uint64
+	totalErrors   uint64
+	consecutive   int // 连续失败次数
+	lastError     string
+	lastErrorAt   time.Time
+	checkedAt     time.Time
+}
+
+// EndpointStats 节点统计
+type EndpointStats struct {
+	URL           string     `json:"url"`
+	Weight        int        `json:"weight"`
+	Healthy       bool       `json:"healthy"`
+	Connected     bool       `json:"connected"`
+	Head          uint64     `json:"head"`
+	HeadLag       uint64     `json:"head_lag"`
+	LatencyMs     int64      `json:"latency_ms"`
+	Requests      uint64     `json:"requests"`       // 本检查周期
+	Errors        uint64     `json:"errors"`         // 本检查周期
+	TotalRequests uint64     `json:"total_requests"` // 启动以来
+	TotalErrors   uint64     `json:"total_errors"`   // 启动以来
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+	CheckedAt     *time.Time `json:"checked_at,omitempty"`
+}
+
+// PoolStats 节点组统计
+type PoolStats struct {
+	Name      string          `json:"name"`
+	Endpoints []EndpointStats `json:"endpoints"`
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []*Pool
+)
+
+// Pools 已建立的全部节点组（运维接口展示统计）
+func Pools() []*Pool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return append([]*Pool(nil), registry...)
+}
+
+// Dial 连接一组节点并启动健康检查，至少一个节点连接成功时返回
+func Dial(name string, endpoints []config.EndpointConfig, cfg config.RPCPoolConfig) (*Pool, error) {
+	if cfg.HealthInterval <= 0 {
+		cfg.HealthInterval = 15 * time.Second
+	}
+	if cfg.CheckTimeout <= 0 {
+		cfg.CheckTimeout = 5 * time.Second
+	}
+	if cfg.MaxErrorRate <= 0 {
+		cfg.MaxErrorRate = 0.5
+	}
+
+	p := &Pool{name: name, cfg: cfg}
+	connected := 0
+	for _, e := range endpoints {
+		if e.URL == "" {
+			continue
+		}
+		ep := &endpoint{
+			url:    e.URL,
+			weight: max(e.Weight, 1),
+			guard:  resilience.For("rpc:"+name+":"+redact(e.URL), cfg.Guard),
+		}
+		client, err := ethclient.Dial(e.URL)
+		if err != nil {
+			log.Warn().Err(err).Str("pool", name).Str("endpoint", redact(e.URL)).Msg("连接RPC节点失败，健康检查时重试")
+			ep.lastError, ep.lastErrorAt = err.Error(), time.Now()
+		} else {
+			ep.client, ep.healthy = client, true
+			connected++
+		}
+		p.endpoints = append(p.endpoints, ep)
+	}
+	if connected == 0 {
+		return nil, fmt.Errorf("%s节点组: %w", name, ErrNoEndpoint)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	go p.healthLoop(ctx)
+
+	registryMu.Lock()
+	registry = append(registry, p)
+	registryMu.Unlock()
+
+	log.Info().Str("pool", name).Int("endpoints", len(p.endpoints)).Int("connected", connected).Msg("RPC节点组初始化成功")
+	return p, nil
+}
+
+// Name 节点组名称
+func (p *Pool) Name() string {
+	return p.name
+}
+
+// Close 停止健康检查并关闭全部连接
+func (p *Pool) Close() {
+	p.cancel()
+	registryMu.Lock()
+	for i, pool := range registry {
+		if pool == p {
+			registry = append(registry[:i], registry[i+1:]...)
+			break
+		}
+	}
+	registryMu.Unlock()
+
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		if ep.client != nil {
+			ep.client.Close()
+			ep.client = nil
+		}
+		ep.healthy = false
+		ep.mu.Unlock()
+	}
+}
+
+// Stats 各节点统计
+func (p *Pool) Stats() PoolStats {
+	var maxHead uint64
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		maxHead = max(maxHead, ep.head)
+		ep.mu.Unlock()
+	}
+
+	stats := PoolStats{Name: p.name, Endpoints: make([]EndpointStats, 0, len(p.endpoints))}
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		s := EndpointStats{
+			URL:           redact(ep.url),
+			Weight:        ep.weight,
+			Healthy:       ep.healthy,
+			Connected:     ep.client != nil,
+			Head:          ep.head,
+			HeadLag:       maxHead - ep.head,
+			LatencyMs:     ep.latency.Milliseconds(),
+			Requests:      ep.requests,
+			Errors:        ep.errors,
+			TotalRequests: ep.totalRequests,
+			TotalErrors:   ep.totalErrors,
+			LastError:     ep.lastError,
+		}
+		if !ep.lastErrorAt.IsZero() {
+			at := ep.lastErrorAt
+			s.LastErrorAt = &at
+		}
+		if !ep.checkedAt.IsZero() {
+			at := ep.checkedAt
+			s.CheckedAt = &at
+		}
+		ep.mu.Unlock()
+		stats.Endpoints = append(stats.Endpoints, s)
+	}
+	return stats
+}
+
+// pick 按权重随机选择一个未尝试过、未熔断的健康节点；没有时退而选择任一已连接节点
+func (p *Pool) pick(tried map[*endpoint]bool) (*endpoint, *ethclient.Client) {
+	type candidate struct {
+		ep     *endpoint
+		client *ethclient.Client
+		weight int
+	}
+	var healthy, connected []candidate
+	for _, ep := range p.endpoints {
+		if tried[ep] {
+			continue
+		}
+		ep.mu.Lock()
+		if ep.client != nil {
+			c := candidate{ep, ep.client, ep.weight}
+			connected = append(connected, c)
+			if ep.healthy && ep.guard.Available() {
+				healthy = append(healthy, c)
+			}
+		}
+		ep.mu.Unlock()
+	}
+
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = connected
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	total := 0
+	for _, c := range candidates {
+		total += c.weight
+	}
+	n := rand.IntN(total)
+	for _, c := range candidates {
+		if n < c.weight {
+			return c.ep, c.client
+		}
+		n -= c.weight
+	}
+	return candidates[0].ep, candidates[0].client
+}
+
+// do 在选中的节点上执行fn，节点原因失败或被限流、熔断时换下一个节点，直到成功或全部节点都已尝试
+func (p *Pool) do(ctx context.Context, fn func(client *ethclient.Client) error) error {
+	tried := make(map[*endpoint]bool, len(p.endpoints))
+	var lastErr error
+	for {
+		ep, client := p.pick(tried)
+		if ep == nil {
+			break
+		}
+		tried[ep] = true
+
+		done, err := ep.guard.Acquire(ctx)
+		if err != nil {
+			if !resilience.IsRejected(err) {
+				return err
+			}
+			lastErr = err
+			continue
+		}
+		start := time.Now()
+		err = fn(client)
+		endpointErr := err != nil && isEndpointError(err) && ctx.Err() == nil
+		done(endpointErr)
+		ep.record(time.Since(start), endpointErr, err)
+		if !endpointErr {
+			return err
+		}
+		lastErr = err
+		log.Warn().Err(err).Str("pool", p.name).Str("endpoint", redact(ep.url)).Msg("RPC节点请求失败，切换节点重试")
+	}
+	if lastErr == nil {
+		return fmt.Errorf("%s节点组: %w", p.name, ErrNoEndpoint)
+	}
+	return lastErr
+}
+
+// call do的泛型包装，返回fn的结果
+func call[T any](p *Pool, ctx context.Context, fn func(client *ethclient.Client) (T, error)) (T, error) {
+	var result T
+	err := p.do(ctx, func(client *ethclient.Client) error {
+		var err error
+		result, err = fn(client)
+		return err
+	})
+	return result, err
+}
+
+// record 记录一次请求
+func (ep *endpoint) record(latency time.Duration, endpointErr bool, err error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.requests++
+	ep.totalRequests++
+	if endpointErr {
+		ep.errors++
+		ep.totalErrors++
+		ep.consecutive++
+		ep.lastError, ep.lastErrorAt = err.Error(), time.Now()
+		if ep.consecutive >= maxConsecutiveErrors {
+			ep.healthy = false
+		}
+		return
+	}
+	ep.consecutive = 0
+	ep.observeLatency(latency)
+}
+
+// observeLatency 更新延迟滑动平均（调用方持有mu）
+func (ep *endpoint) observeLatency(latency time.Duration) {
+	if ep.latency == 0 {
+		ep.latency = latency
+		return
+	}
+	ep.latency = time.Duration(float64(ep.latency)*(1-latencyAlpha) + float64(latency)*latencyAlpha)
+}
+
+// healthLoop 定期检查各节点
+func (p *Pool) healthLoop(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.HealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.checkHealth(ctx)
+		}
+	}
+}
+
+// checkHealth 并发查询各节点最新区块，按区块落后数、本周期错误率与延迟判定健康状态
+func (p *Pool) checkHealth(ctx context.Context) {
+	heads := make([]uint64, len(p.endpoints))
+	errs := make([]error, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range p.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			heads[i], errs[i] = ep.check(ctx, p.cfg.CheckTimeout)
+		}()
+	}
+	wg.Wait()
+
+	var maxHead uint64
+	for i := range p.endpoints {
+		if errs[i] == nil {
+			maxHead = max(maxHead, heads[i])
+		}
+	}
+
+	for i, ep := range p.endpoints {
+		ep.mu.Lock()
+		reason := ""
+		switch {
+		case errs[i] != nil:
+			reason = "健康检查失败: " + errs[i].Error()
+		case maxHead-heads[i] > p.cfg.MaxHeadLag:
+			reason = fmt.Sprintf("最新区块落后%d个", maxHead-heads[i])
+		case ep.requests >= uint64(max(p.cfg.MinRequests, 1)) && float64(ep.errors)/float64(ep.requests) > p.cfg.MaxErrorRate:
+			reason = fmt.Sprintf("错误率%d/%d", ep.errors, ep.requests)
+		case p.cfg.MaxLatency > 0 && ep.latency > p.cfg.MaxLatency:
+			reason = fmt.Sprintf("平均延迟%s", ep.latency.Round(time.Millisecond))
+		}
+		healthy := reason == ""
+		if healthy != ep.healthy {
+			if healthy {
+				log.Info().Str("pool", p.name).Str("endpoint", redact(ep.url)).Msg("RPC节点恢复健康")
+			} else {
+				log.Warn().Str("pool", p.name).Str("endpoint", redact(ep.url)).Str("reason", reason).Msg("RPC节点不健康，暂停分配请求")
+			}
+		}
+		ep.healthy = healthy
+		ep.requests, ep.errors = 0, 0
+		ep.mu.Unlock()
+	}
+}
+
+// check 查询节点最新区块（未连接时先重连）
+func (ep *endpoint) check(ctx context.Context, timeout time.Duration) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ep.mu.Lock()
+	client := ep.client
+	ep.mu.Unlock()
+	if client == nil {
+		dialed, err := ethclient.DialContext(ctx, ep.url)
+		if err != nil {
+			ep.checked(0, 0, err)
+			return 0, err
+		}
+		ep.mu.Lock()
+		if ep.client == nil {
+			ep.client = dialed
+		} else {
+			dialed.Close()
+		}
+		client = ep.client
+		ep.mu.Unlock()
+	}
+
+	start := time.Now()
+	head, err := client.BlockNumber(ctx)
+	ep.checked(head, time.Since(start), err)
+	return head, err
+}
+
+// checked 记录健康检查结果
+func (ep *endpoint) checked(head uint64, latency time.Duration, err error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.checkedAt = time.Now()
+	if err != nil {
+		ep.lastError, ep.lastErrorAt = err.Error(), ep.checkedAt
+		return
+	}
+	ep.head = head
+	ep.consecutive = 0
+	ep.observeLatency(latency)
+}
+
+// isEndpointError 判断错误是否由节点本身引起（换节点重试可能成功）
+// 合约回滚、交易校验失败、查询范围过大等与节点无关的错误返回false
+func isEndpointError(err error) bool {
+	if errors.Is(err, ethereum.NotFound) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, hint := range []string{"query returned more than", "too many results", "block range"} {
+		if strings.Contains(msg, hint) {
+			return false
+		}
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.ErrorCode() {
+		case -32005, -32603, -32601: // 超出限额、节点内部错误、节点不支持该方法
+			return true
+		}
+		for _, hint := range []string{"rate limit", "too many requests", "header not found", "capacity"} {
+			if strings.Contains(msg, hint) {
+				return true
+			}
+		}
+		return false
+	}
+	// 连接中断、超时等传输层错误
+	return true
+}
+
+// redact 隐藏节点地址中可能包含的API Key（路径与查询参数）
+func redact(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "***"
+	}
+	if u.Path == "" || u.Path == "/" {
+		if u.RawQuery == "" {
+			return u.Scheme + "://" + u.Host
+		}
+	}
+	return u.Scheme + "://" + u.Host + "/***"
+}
+
+// AllStats 全部节点组的统计，按名称排序（运维接口使用）
+func AllStats() []PoolStats {
+	pools := Pools()
+	stats := make([]PoolStats, 0, len(pools))
+	for _, p := range pools {
+		stats = append(stats, p.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
@@ -0,0 +1,223 @@
+package rpcpool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ydh2333/NFTAuction-project/config"
+)
+
+// rpcError 节点返回的JSON-RPC错误
+type rpcError struct {
+	code int
+	msg  string
+}
+
+func (e rpcError) Error() string  { return e.msg }
+func (e rpcError) ErrorCode() int { return e.code }
+
+func TestIsEndpointError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"未找到", ethereum.NotFound, false},
+		{"上下文取消", context.Canceled, false},
+		{"HTTP错误", rpc.HTTPError{StatusCode: http.StatusBadGateway}, true},
+		{"查询范围过大", rpcError{-32005, "query returned more than 10000 results"}, false},
+		{"区块范围", rpcError{-32000, "block range is too wide"}, false},
+		{"超出限额", rpcError{-32005, "limit exceeded"}, true},
+		{"节点内部错误", rpcError{-32603, "internal error"}, true},
+		{"方法不支持", rpcError{-32601, "the method does not exist"}, true},
+		{"限流提示", rpcError{-32000, "rate limit reached"}, true},
+		{"区块头缺失", rpcError{-32000, "header not found"}, true},
+		{"合约回滚", rpcError{3, "execution reverted"}, false},
+		{"交易校验失败", rpcError{-32000, "nonce too low"}, false},
+		{"传输层错误", errors.New("read: connection reset by peer"), true},
+		{"包装的传输层错误", fmt.Errorf("请求失败: %w", errors.New("i/o timeout")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isEndpointError(tt.err); got != tt.want {
+				t.Errorf("isEndpointError(%v) = %v，期望 %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkipReplayed(t *testing.T) {
+	block := common.HexToHash("0x01")
+	replayedLog := types.Log{BlockNumber: 10, BlockHash: block, Index: 1}
+
+	tests := []struct {
+		name      string
+		logs      []types.Log
+		want      []bool
+		wantReset bool // 处理完后replayed是否已清空
+	}{
+		{"补齐过的日志跳过", []types.Log{replayedLog}, []bool{true}, false},
+		{"回滚日志不跳过", []types.Log{{BlockNumber: 10, BlockHash: block, Index: 1, Removed: true}}, []bool{false}, false},
+		{"未补齐的日志不跳过", []types.Log{{BlockNumber: 10, BlockHash: block, Index: 2}}, []bool{false}, false},
+		{"同高度不同区块哈希不跳过", []types.Log{{BlockNumber: 10, BlockHash: common.HexToHash("0x02"), Index: 1}}, []bool{false}, false},
+		{"越过补齐区块后清空", []types.Log{{BlockNumber: 11, Index: 0}, replayedLog}, []bool{false, false}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &failoverSubscription{
+				replayed:   map[logKey]bool{{block, 1}: true},
+				replayedTo: 10,
+			}
+			for i, lg := range tt.logs {
+				if got := s.skipReplayed(lg); got != tt.want[i] {
+					t.Errorf("第%d条日志 skipReplayed = %v，期望 %v", i, got, tt.want[i])
+				}
+			}
+			if (s.replayed == nil) != tt.wantReset {
+				t.Errorf("replayed已清空 = %v，期望 %v", s.replayed == nil, tt.wantReset)
+			}
+		})
+	}
+}
+
+// fakeNode 共享交易池的测试节点组：首次收到交易时接收后返回502（模拟广播后连接中断），
+// 之后的节点按reject返回错误
+type fakeNode struct {
+	mu       sync.Mutex
+	reject   string // 重发时返回的错误
+	drop     bool   // 首次收到交易时不接收，只返回502
+	received map[common.Hash]*types.Transaction
+	sends    int
+}
+
+// ethService 单个节点的eth命名空间
+type ethService struct {
+	node *fakeNode
+}
+
+func (s *ethService) ChainId() hexutil.Big {
+	return hexutil.Big(*big.NewInt(1))
+}
+
+func (s *ethService) BlockNumber() hexutil.Uint64 {
+	return 1
+}
+
+func (s *ethService) SendRawTransaction(raw hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return common.Hash{}, err
+	}
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	s.node.sends++
+	if s.node.sends > 1 {
+		return common.Hash{}, errors.New(s.node.reject)
+	}
+	if !s.node.drop {
+		s.node.received[tx.Hash()] = tx
+	}
+	return common.Hash{}, errBroadcastDropped
+}
+
+func (s *ethService) GetTransactionByHash(hash common.Hash) *types.Transaction {
+	s.node.mu.Lock()
+	defer s.node.mu.Unlock()
+	return s.node.received[hash]
+}
+
+var errBroadcastDropped = errors.New("broadcast dropped")
+
+// serve 启动一个节点：errBroadcastDropped改写为502响应
+func (n *fakeNode) serve(t *testing.T) string {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &ethService{node: n}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, r)
+		if bytes.Contains(rec.Body.Bytes(), []byte(errBroadcastDropped.Error())) {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+	}))
+	t.Cleanup(httpServer.Close)
+	return httpServer.URL
+}
+
+func signedTx(t *testing.T) *types.Transaction {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x01")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     7,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// TestSendTransactionFailover 节点广播后连接中断，切换节点重发被拒绝时按拒绝原因判断是否已发送
+func TestSendTransactionFailover(t *testing.T) {
+	tests := []struct {
+		name    string
+		reject  string
+		drop    bool
+		wantErr bool
+	}{
+		{name: "重发时已在交易池", reject: "already known"},
+		{name: "重发时已上链", reject: "nonce too low"},
+		{name: "首个节点未广播且nonce已被占用", reject: "nonce too low", drop: true, wantErr: true},
+		{name: "其他拒绝原因", reject: "insufficient funds for gas * price + value", wantErr: true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &fakeNode{reject: tt.reject, drop: tt.drop, received: make(map[common.Hash]*types.Transaction)}
+			endpoints := []config.EndpointConfig{{URL: node.serve(t)}, {URL: node.serve(t)}}
+			pool, err := Dial(fmt.Sprintf("send-test-%d", i), endpoints, config.RPCPoolConfig{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer pool.Close()
+
+			err = pool.SendTransaction(context.Background(), signedTx(t))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendTransaction() err = %v，期望出错 %v", err, tt.wantErr)
+			}
+			if node.sends != 2 {
+				t.Errorf("发送次数 = %d，期望切换节点后重发一次", node.sends)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package rpcpool
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/resilience"
+)
+
+// 订阅切换节点的重试参数
+const (
+	resubscribeRounds  = 5
+	resubscribeBackoff = 2 * time.Second
+	resubscribeTimeout = 10 * time.Second
+)
+
+// logKey 日志在链上的唯一标识
+type logKey struct {
+	block common.Hash
+	index uint
+}
+
+// failoverSubscription 节点断开时自动切换到其他节点的日志订阅
+// 切换后用eth_getLogs补齐断开期间的日志，全部节点都订阅失败时才通过Err通知订阅方
+type failoverSubscription struct {
+	pool  *Pool
+	query ethereum.FilterQuery
+	out   chan<- types.Log
+	inner chan types.Log
+
+	ep        *endpoint
+	sub       ethereum.Subscription
+	lastBlock uint64 // 最近转发的日志所在区块（初始为订阅时的最新区块）
+
+	replayed   map[logKey]bool // 切换节点后补齐的日志，新订阅再次推送时跳过
+	replayedTo uint64          // 补齐日志的最高区块，新订阅越过该区块后清空replayed
+
+	err  chan error
+	quit chan struct{}
+	once sync.Once
+}
+
+// SubscribeFilterLogs 订阅日志，当前节点断开时切换到其他健康节点继续订阅
+func (p *Pool) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	s := &failoverSubscription{
+		pool:  p,
+		query: q,
+		out:   ch,
+		inner: make(chan types.Log, 128),
+		err:   make(chan error, 1),
+		quit:  make(chan struct{}),
+	}
+	if err := s.subscribe(ctx, nil); err != nil {
+		return nil, err
+	}
+	// 以订阅时的最新区块作为补齐起点，尚未收到日志时断开也能补齐
+	if head, err := p.BlockNumber(ctx); err == nil {
+		s.lastBlock = head
+	}
+	go s.loop()
+	return s, nil
+}
+
+// subscribe 依次在健康节点上订阅，failed为刚断开的节点（本轮不再选择）
+func (s *failoverSubscription) subscribe(ctx context.Context, failed *endpoint) error {
+	tried := map[*endpoint]bool{}
+	if failed != nil {
+		tried[failed] = true
+	}
+	var lastErr error
+	for {
+		ep, client := s.pool.pick(tried)
+		if ep == nil {
+			break
+		}
+		tried[ep] = true
+
+		done, err := ep.guard.Acquire(ctx)
+		if err != nil {
+			if !resilience.IsRejected(err) {
+				return err
+			}
+			lastErr = err
+			continue
+		}
+		start := time.Now()
+		sub, err := client.SubscribeFilterLogs(ctx, s.query, s.inner)
+		endpointErr := err != nil && isEndpointError(err) && ctx.Err() == nil
+		done(endpointErr)
+		ep.record(time.Since(start), endpointErr, err)
+		if err == nil {
+			s.ep, s.sub = ep, sub
+			return nil
+		}
+		if !endpointErr {
+			return err
+		}
+		lastErr = err
+		log.Warn().Err(err).Str("pool", s.pool.name).Str("endpoint", redact(ep.url)).Msg("RPC节点订阅失败，切换节点重试")
+	}
+	if lastErr == nil {
+		return fmt.Errorf("%s节点组: %w", s.pool.name, ErrNoEndpoint)
+	}
+	return lastErr
+}
+
+// loop 转发日志，订阅出错时切换节点
+func (s *failoverSubscription) loop() {
+	for {
+		select {
+		case <-s.quit:
+			s.sub.Unsubscribe()
+			return
+		case lg := <-s.inner:
+			if s.skipReplayed(lg) {
+				continue
+			}
+			if !s.forward(lg) {
+				s.sub.Unsubscribe()
+				return
+			}
+		case err := <-s.sub.Err():
+			if err == nil {
+				// 节点侧正常关闭订阅，同样切换
+				err = fmt.Errorf("订阅被关闭")
+			}
+			s.ep.record(0, true, err)
+			log.Warn().Err(err).Str("pool", s.pool.name).Str("endpoint", redact(s.ep.url)).Msg("RPC节点订阅断开，切换节点")
+			s.sub.Unsubscribe()
+			if err := s.failover(s.ep); err != nil {
+				s.err <- err
+				return
+			}
+			select {
+			case <-s.quit:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// failover 切换节点重新订阅（多轮重试），成功后补齐断开期间的日志
+func (s *failoverSubscription) failover(failed *endpoint) error {
+	var err error
+	for round := 0; round < resubscribeRounds; round++ {
+		if round > 0 {
+			select {
+			case <-time.After(resubscribeBackoff):
+			case <-s.quit:
+				return nil // 订阅方已取消
+			}
+			failed = nil // 之后的轮次所有节点都可以重试
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), resubscribeTimeout)
+		err = s.subscribe(ctx, failed)
+		cancel()
+		if err == nil {
+			log.Info().Str("pool", s.pool.name).Str("endpoint", redact(s.ep.url)).Msg("日志订阅已切换节点")
+			s.backfill()
+			return nil
+		}
+	}
+	return err
+}
+
+// backfill 查询断开期间（最近转发日志所在区块之后）的日志并转发，与新订阅重复的日志只转发一次
+func (s *failoverSubscription) backfill() {
+	if s.lastBlock == 0 {
+		return
+	}
+	q := s.query
+	q.FromBlock = new(big.Int).SetUint64(s.lastBlock + 1)
+	q.ToBlock = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), resubscribeTimeout)
+	defer cancel()
+	logs, err := s.pool.FilterLogs(ctx, q)
+	if err != nil {
+		log.Warn().Err(err).Str("pool", s.pool.name).Uint64("from", s.lastBlock+1).Msg("补齐订阅断开期间的日志失败")
+		return
+	}
+
+	if len(logs) == 0 {
+		return
+	}
+	s.replayed = make(map[logKey]bool, len(logs))
+	for _, lg := range logs {
+		s.replayed[logKey{lg.BlockHash, lg.Index}] = true
+		s.replayedTo = max(s.replayedTo, lg.BlockNumber)
+		if !s.forward(lg) {
+			return
+		}
+	}
+	log.Info().Str("pool", s.pool.name).Int("logs", len(logs)).Msg("已补齐订阅断开期间的日志")
+}
+
+// skipReplayed 新订阅推送的日志已在补齐时转发过则跳过
+func (s *failoverSubscription) skipReplayed(lg types.Log) bool {
+	if s.replayed == nil {
+		return false
+	}
+	if lg.BlockNumber > s.replayedTo {
+		s.replayed = nil
+		return false
+	}
+	return !lg.Removed && s.replayed[logKey{lg.BlockHash, lg.Index}]
+}
+
+// forward 把日志交给订阅方，订阅已取消时返回false
+func (s *failoverSubscription) forward(lg types.Log) bool {
+	select {
+	case s.out <- lg:
+		if !lg.Removed {
+			s.lastBlock = max(s.lastBlock, lg.BlockNumber)
+		}
+		return true
+	case <-s.quit:
+		return false
+	}
+}
+
+// Err 全部节点都无法订阅时返回错误
+func (s *failoverSubscription) Err() <-chan error {
+	return s.err
+}
+
+// Unsubscribe 取消订阅
+func (s *failoverSubscription) Unsubscribe() {
+	s.once.Do(func() { close(s.quit) })
+}
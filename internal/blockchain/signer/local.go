@@ -0,0 +1,51 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// localSigner 持有解密后私钥的签名器（keystore与开发私钥共用）
+type localSigner struct {
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+}
+
+// NewKeystoreSigner 解密go-ethereum keystore文件创建签名器
+func NewKeystoreSigner(path, passphrase string) (Signer, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取keystore文件失败: %w", err)
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("解密keystore失败: %w", err)
+	}
+	return &localSigner{privateKey: key.PrivateKey, address: key.Address}, nil
+}
+
+// NewDevSigner 使用内存中的十六进制私钥创建签名器，仅用于开发/测试环境
+func NewDevSigner(hexKey string) (Signer, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("私钥解析失败: %w", err)
+	}
+	return &localSigner{privateKey: privateKey, address: crypto.PubkeyToAddress(privateKey.PublicKey)}, nil
+}
+
+func (s *localSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *localSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.privateKey)
+}
@@ -0,0 +1,43 @@
+package signer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MessageSigner 支持EIP-191（personal_sign）消息签名的签名器，用于管理员审批等链下身份校验
+type MessageSigner interface {
+	Signer
+	SignMessage(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// SignMessage 按personal_sign规则签名消息，返回65字节签名（v为27/28，与钱包一致）
+func (s *localSigner) SignMessage(_ context.Context, message []byte) ([]byte, error) {
+	sig, err := crypto.Sign(accounts.TextHash(message), s.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+// RecoverMessageSigner 从personal_sign签名中恢复签名地址
+func RecoverMessageSigner(message, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, errors.New("签名长度错误")
+	}
+	normalized := make([]byte, len(sig))
+	copy(normalized, sig)
+	if normalized[crypto.RecoveryIDOffset] >= 27 {
+		normalized[crypto.RecoveryIDOffset] -= 27
+	}
+	pubKey, err := crypto.SigToPub(accounts.TextHash(message), normalized)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
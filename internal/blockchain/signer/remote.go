@@ -0,0 +1,217 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 远程签名服务协议：
+//   GET  {url}/address → {"address": "0x..."}
+//   POST {url}/sign    ← {"chainId": "11155111", "tx": "0x<未签名交易二进制编码>"}
+//                      → {"signedTx": "0x<已签名交易二进制编码>"}
+// 请求头 Authorization: Bearer <token>（签名服务必须配置token）
+
+type addressResponse struct {
+	Address common.Address `json:"address"`
+}
+
+type signRequest struct {
+	ChainID string        `json:"chainId"`
+	Tx      hexutil.Bytes `json:"tx"`
+}
+
+type signResponse struct {
+	SignedTx hexutil.Bytes `json:"signedTx"`
+}
+
+// RemoteSigner 通过HTTP调用远程签名服务的签名器
+type RemoteSigner struct {
+	url        string
+	token      string
+	address    common.Address
+	httpClient *http.Client
+}
+
+// NewRemoteSigner 创建远程签名器，并从签名服务获取签名地址
+func NewRemoteSigner(ctx context.Context, url, token string) (*RemoteSigner, error) {
+	if url == "" {
+		return nil, fmt.Errorf("未配置远程签名服务地址")
+	}
+	s := &RemoteSigner{
+		url:        strings.TrimRight(url, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	var resp addressResponse
+	if err := s.do(ctx, http.MethodGet, "/address", nil, &resp); err != nil {
+		return nil, fmt.Errorf("获取远程签名地址失败: %w", err)
+	}
+	if resp.Address == (common.Address{}) {
+		return nil, fmt.Errorf("远程签名服务返回空地址")
+	}
+	s.address = resp.Address
+	return s, nil
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx 请求远程服务签名，并校验签名地址与交易内容未被篡改
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	unsigned, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("编码交易失败: %w", err)
+	}
+
+	var resp signResponse
+	if err := s.do(ctx, http.MethodPost, "/sign", signRequest{ChainID: chainID.String(), Tx: unsigned}, &resp); err != nil {
+		return nil, fmt.Errorf("远程签名失败: %w", err)
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(resp.SignedTx); err != nil {
+		return nil, fmt.Errorf("解析已签名交易失败: %w", err)
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("恢复签名地址失败: %w", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("签名地址不匹配: 期望%s，实际%s", s.address.Hex(), sender.Hex())
+	}
+	if signedTx.ChainId().Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("签名链ID不匹配: 期望%s，实际%s", chainID, signedTx.ChainId())
+	}
+	if !sameTxContent(signedTx, tx) {
+		return nil, fmt.Errorf("远程签名返回的交易内容与请求不一致")
+	}
+	return signedTx, nil
+}
+
+// sameTxContent 比较已签名交易与请求签名的交易内容（类型、nonce、接收方、金额、数据、燃气限额与燃气费）
+func sameTxContent(signed, tx *types.Transaction) bool {
+	return signed.Type() == tx.Type() &&
+		signed.Nonce() == tx.Nonce() &&
+		signed.To() != nil && tx.To() != nil && *signed.To() == *tx.To() &&
+		signed.Value().Cmp(tx.Value()) == 0 &&
+		bytes.Equal(signed.Data(), tx.Data()) &&
+		signed.Gas() == tx.Gas() &&
+		signed.GasFeeCap().Cmp(tx.GasFeeCap()) == 0 &&
+		signed.GasTipCap().Cmp(tx.GasTipCap()) == 0
+}
+
+// do 发送HTTP请求并解析JSON响应
+func (s *RemoteSigner) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("签名服务返回状态码%d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, out)
+}
+
+// NewRemoteHandler 将本地签名器包装为远程签名服务（本地替代真实签名服务，如开发环境或签名机）
+// 签名服务可以用私钥签任意交易，未配置访问令牌时拒绝创建
+func NewRemoteHandler(s Signer, token string) (http.Handler, error) {
+	if token == "" {
+		return nil, fmt.Errorf("签名服务未配置访问令牌")
+	}
+	authorized := func(r *http.Request) bool {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/address", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, addressResponse{Address: s.Address()})
+	})
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !authorized(r) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req signRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		chainID, ok := new(big.Int).SetString(req.ChainID, 10)
+		if !ok {
+			http.Error(w, "invalid chainId", http.StatusBadRequest)
+			return
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(req.Tx); err != nil {
+			http.Error(w, "invalid tx", http.StatusBadRequest)
+			return
+		}
+
+		signedTx, err := s.SignTx(r.Context(), tx, chainID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		raw, err := signedTx.MarshalBinary()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, signResponse{SignedTx: raw})
+	})
+	return mux, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
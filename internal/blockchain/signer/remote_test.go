@@ -0,0 +1,142 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const testKey = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+
+// tamperSigner 签名前按tamper修改交易或链ID，模拟被篡改的签名服务
+type tamperSigner struct {
+	Signer
+	tamper func(tx *types.DynamicFeeTx, chainID *big.Int) *big.Int
+}
+
+func (s *tamperSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	inner := &types.DynamicFeeTx{
+		ChainID:   tx.ChainId(),
+		Nonce:     tx.Nonce(),
+		GasTipCap: tx.GasTipCap(),
+		GasFeeCap: tx.GasFeeCap(),
+		Gas:       tx.Gas(),
+		To:        tx.To(),
+		Value:     tx.Value(),
+		Data:      tx.Data(),
+	}
+	if s.tamper != nil {
+		chainID = s.tamper(inner, new(big.Int).Set(chainID))
+		inner.ChainID = chainID
+	}
+	return s.Signer.SignTx(ctx, types.NewTx(inner), chainID)
+}
+
+func TestRemoteSignerRejectsTampering(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(tx *types.DynamicFeeTx, chainID *big.Int) *big.Int
+		wantErr bool
+	}{
+		{"未篡改", nil, false},
+		{"提高燃气费上限", func(tx *types.DynamicFeeTx, chainID *big.Int) *big.Int {
+			tx.GasFeeCap = new(big.Int).Mul(tx.GasFeeCap, big.NewInt(10))
+			return chainID
+		}, true},
+		{"提高小费", func(tx *types.DynamicFeeTx, chainID *big.Int) *big.Int {
+			tx.GasTipCap = new(big.Int).Add(tx.GasTipCap, big.NewInt(1))
+			return chainID
+		}, true},
+		{"更换链ID", func(tx *types.DynamicFeeTx, chainID *big.Int) *big.Int {
+			return big.NewInt(1)
+		}, true},
+		{"更换接收方", func(tx *types.DynamicFeeTx, chainID *big.Int) *big.Int {
+			to := common.HexToAddress("0xbad")
+			tx.To = &to
+			return chainID
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local, err := NewDevSigner(testKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			handler, err := NewRemoteHandler(&tamperSigner{Signer: local, tamper: tt.tamper}, "secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			remote, err := NewRemoteSigner(context.Background(), server.URL, "secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			chainID := big.NewInt(1337)
+			to := common.HexToAddress("0x1")
+			tx := types.NewTx(&types.DynamicFeeTx{
+				ChainID:   chainID,
+				Nonce:     3,
+				GasTipCap: big.NewInt(1e9),
+				GasFeeCap: big.NewInt(2e9),
+				Gas:       21000,
+				To:        &to,
+				Value:     big.NewInt(1),
+			})
+			if _, err := remote.SignTx(context.Background(), tx, chainID); (err != nil) != tt.wantErr {
+				t.Errorf("SignTx error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoteHandlerAuth(t *testing.T) {
+	local, err := NewDevSigner(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRemoteHandler(local, ""); err == nil {
+		t.Fatal("NewRemoteHandler without token should fail")
+	}
+
+	handler, err := NewRemoteHandler(local, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	for _, token := range []string{"", "wrong"} {
+		if _, err := NewRemoteSigner(context.Background(), server.URL, token); err == nil {
+			t.Errorf("NewRemoteSigner with token %q should be rejected", token)
+		}
+	}
+
+	// 必须带Bearer前缀，裸令牌或其他认证方式拒绝
+	for header, want := range map[string]int{
+		"Bearer secret": http.StatusOK,
+		"secret":        http.StatusUnauthorized,
+		"Basic secret":  http.StatusUnauthorized,
+		"Bearer ":       http.StatusUnauthorized,
+	} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/address", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", header)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Authorization %q: status %d, want %d", header, resp.StatusCode, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Package signer 交易签名抽象
+//
+// 后端不再在配置或函数参数中持有明文私钥，所有签名通过Signer完成：
+// 加密keystore文件（口令来自环境变量或文件）、开发环境内存私钥（来自环境变量）、
+// 以及通过HTTP访问的远程签名服务。
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/config"
+)
+
+// 签名器类型
+const (
+	TypeKeystore = "keystore" // go-ethereum加密keystore文件
+	TypeDev      = "dev"      // 开发环境内存私钥
+	TypeRemote   = "remote"   // 远程HTTP签名服务
+)
+
+// Signer 交易签名器
+type Signer interface {
+	// Address 签名地址
+	Address() common.Address
+	// SignTx 使用chainID对应的签名规则签名交易
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// FromConfig 根据配置创建签名器
+func FromConfig(cfg *config.SignerConfig) (Signer, error) {
+	switch cfg.Type {
+	case TypeKeystore:
+		passphrase, err := readSecret(cfg.PassphraseEnv, cfg.PassphraseFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取keystore口令失败: %w", err)
+		}
+		return NewKeystoreSigner(cfg.KeystorePath, passphrase)
+	case TypeDev:
+		hexKey, err := readSecret(cfg.DevKeyEnv, "")
+		if err != nil {
+			return nil, fmt.Errorf("读取开发私钥失败: %w", err)
+		}
+		return NewDevSigner(hexKey)
+	case TypeRemote:
+		token, _ := readSecret(cfg.RemoteTokenEnv, "")
+		return NewRemoteSigner(context.Background(), cfg.RemoteURL, token)
+	case "":
+		return nil, fmt.Errorf("未配置签名器")
+	default:
+		return nil, fmt.Errorf("不支持的签名器类型: %s", cfg.Type)
+	}
+}
+
+// readSecret 从环境变量或文件读取敏感信息，优先环境变量
+func readSecret(envName, filePath string) (string, error) {
+	if envName != "" {
+		if value, ok := os.LookupEnv(envName); ok {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	if filePath != "" {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	if envName != "" {
+		return "", fmt.Errorf("环境变量%s未设置", envName)
+	}
+	return "", fmt.Errorf("未配置环境变量或文件")
+}
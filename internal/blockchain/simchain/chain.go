@@ -0,0 +1,188 @@
+// Package simchain 进程内模拟链，用于离线驱动监听器做端到端验证
+//
+// Chain以chainclient.Fake保存区块与日志，并在进程内提供最小的以太坊JSON-RPC服务（eth_subscribe logs、
+// eth_getLogs、eth_getBlockByNumber、eth_call、eth_chainId、eth_blockNumber）。Client返回的
+// *ethclient.Client与连接真实节点时行为一致，可覆盖客户端的JSON编解码；只关心监听逻辑时也可以
+// 直接把Chain.Fake作为ChainClient传给监听器。
+//
+// JSON-RPC无法由服务端向客户端推送订阅错误，FailSubscriptions只对直接订阅Fake的一方生效，
+// 经Client订阅的一方只会停止收到日志；验证断线重连时应直接使用Fake。
+package simchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/chainclient"
+)
+
+// Chain 进程内模拟链
+type Chain struct {
+	*chainclient.Fake
+
+	chainID *big.Int
+	server  *rpc.Server
+	client  *ethclient.Client
+}
+
+// New 创建模拟链，创世区块时间为当前时间
+func New(chainID int64) *Chain {
+	c := &Chain{
+		Fake:    chainclient.NewFake(),
+		chainID: big.NewInt(chainID),
+		server:  rpc.NewServer(),
+	}
+	if err := c.server.RegisterName("eth", &ethAPI{chain: c}); err != nil {
+		panic(fmt.Sprintf("注册模拟链RPC服务失败: %v", err))
+	}
+	c.client = ethclient.NewClient(rpc.DialInProc(c.server))
+	return c
+}
+
+// Client 返回经JSON-RPC连接模拟链的客户端
+func (c *Chain) Client() *ethclient.Client {
+	return c.client
+}
+
+// Close 关闭客户端与RPC服务（同时结束所有订阅）
+func (c *Chain) Close() {
+	c.client.Close()
+	c.server.Stop()
+}
+
+// WaitSubscriptions 等待日志订阅数量达到n（监听器启动后再出块，避免日志丢失）
+func (c *Chain) WaitSubscriptions(ctx context.Context, n int) error {
+	return WaitFor(ctx, func() error {
+		if got := c.Subscriptions(); got < n {
+			return fmt.Errorf("订阅数量%d，期望%d", got, n)
+		}
+		return nil
+	})
+}
+
+// ethAPI 模拟链的eth命名空间RPC方法
+type ethAPI struct {
+	chain *Chain
+}
+
+// filterQuery eth_getLogs/eth_subscribe的过滤参数
+type filterQuery struct {
+	BlockHash *common.Hash     `json:"blockHash"`
+	FromBlock *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`
+	Addresses []common.Address `json:"address"`
+	Topics    [][]common.Hash  `json:"topics"`
+}
+
+// toFilterQuery 转换为ethereum.FilterQuery，latest等标签解析为最新区块
+func (q filterQuery) toFilterQuery(head uint64) ethereum.FilterQuery {
+	resolve := func(number *rpc.BlockNumber) *big.Int {
+		if number == nil {
+			return nil
+		}
+		if *number < 0 {
+			return new(big.Int).SetUint64(head)
+		}
+		return big.NewInt(number.Int64())
+	}
+	return ethereum.FilterQuery{
+		BlockHash: q.BlockHash,
+		FromBlock: resolve(q.FromBlock),
+		ToBlock:   resolve(q.ToBlock),
+		Addresses: q.Addresses,
+		Topics:    q.Topics,
+	}
+}
+
+// callArgs eth_call的调用参数
+type callArgs struct {
+	From  *common.Address `json:"from"`
+	To    *common.Address `json:"to"`
+	Data  hexutil.Bytes   `json:"data"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+// Logs eth_subscribe("logs")，转发Fake订阅的日志；客户端取消订阅或连接关闭后取消Fake订阅
+func (api *ethAPI) Logs(ctx context.Context, q filterQuery) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	logs := make(chan types.Log)
+	fakeSub, err := api.chain.SubscribeFilterLogs(context.Background(), q.toFilterQuery(api.chain.Head().Number.Uint64()), logs)
+	if err != nil {
+		return nil, err
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer fakeSub.Unsubscribe()
+		for {
+			select {
+			case lg := <-logs:
+				notifier.Notify(rpcSub.ID, lg)
+			case <-rpcSub.Err():
+				return
+			case <-fakeSub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// GetLogs eth_getLogs
+func (api *ethAPI) GetLogs(ctx context.Context, q filterQuery) ([]types.Log, error) {
+	return api.chain.FilterLogs(ctx, q.toFilterQuery(api.chain.Head().Number.Uint64()))
+}
+
+// GetBlockByNumber eth_getBlockByNumber（只返回区块头字段），区块不存在时返回null
+func (api *ethAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (*types.Header, error) {
+	var n *big.Int
+	if number >= 0 {
+		n = big.NewInt(number.Int64())
+	}
+	header, err := api.chain.HeaderByNumber(ctx, n)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	return header, err
+}
+
+// Call eth_call
+func (api *ethAPI) Call(ctx context.Context, args callArgs, block *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if args.To == nil {
+		return nil, errors.New("模拟链不支持合约创建调用")
+	}
+	msg := ethereum.CallMsg{To: args.To, Data: args.Input}
+	if len(msg.Data) == 0 {
+		msg.Data = args.Data
+	}
+	var number *big.Int
+	if block != nil {
+		if n, ok := block.Number(); ok && n >= 0 {
+			number = big.NewInt(n.Int64())
+		}
+	}
+	return api.chain.CallContract(ctx, msg, number)
+}
+
+// ChainId eth_chainId
+func (api *ethAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(api.chain.chainID)
+}
+
+// BlockNumber eth_blockNumber
+func (api *ethAPI) BlockNumber(ctx context.Context) (hexutil.Uint64, error) {
+	number, err := api.chain.Fake.BlockNumber(ctx)
+	return hexutil.Uint64(number), err
+}
@@ -0,0 +1,81 @@
+package simchain
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+)
+
+// EncodeEvent 按ABI将事件编码为日志：indexed参数写入主题，其余参数ABI编码为data
+// event为abigen生成的事件结构体（或其指针），字段按参数名的驼峰形式取值，Raw字段忽略
+func EncodeEvent(contractABI *abi.ABI, address common.Address, name string, event interface{}) (types.Log, error) {
+	ev, ok := contractABI.Events[name]
+	if !ok {
+		return types.Log{}, fmt.Errorf("ABI中未找到事件%s", name)
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(event))
+	if v.Kind() != reflect.Struct {
+		return types.Log{}, fmt.Errorf("事件%s参数须为结构体", name)
+	}
+
+	topics := []common.Hash{ev.ID}
+	var data []interface{}
+	for _, input := range ev.Inputs {
+		field := v.FieldByName(abi.ToCamelCase(input.Name))
+		if !field.IsValid() {
+			return types.Log{}, fmt.Errorf("事件%s缺少字段%s", name, abi.ToCamelCase(input.Name))
+		}
+		if !input.Indexed {
+			data = append(data, field.Interface())
+			continue
+		}
+		rules, err := abi.MakeTopics([]interface{}{field.Interface()})
+		if err != nil {
+			return types.Log{}, fmt.Errorf("编码事件%s主题%s失败: %w", name, input.Name, err)
+		}
+		topics = append(topics, rules[0][0])
+	}
+
+	packed, err := ev.Inputs.NonIndexed().Pack(data...)
+	if err != nil {
+		return types.Log{}, fmt.Errorf("编码事件%s数据失败: %w", name, err)
+	}
+	return types.Log{Address: address, Topics: topics, Data: packed}, nil
+}
+
+// CreateAuctionLog 编码拍卖合约CreateAuction事件
+func CreateAuctionLog(address common.Address, event contracts.NFTAuctionCreateAuction) (types.Log, error) {
+	return encodeAuctionEvent(address, "CreateAuction", event)
+}
+
+// PlaceBidLog 编码拍卖合约PlaceBid事件
+func PlaceBidLog(address common.Address, event contracts.NFTAuctionPlaceBid) (types.Log, error) {
+	return encodeAuctionEvent(address, "PlaceBid", event)
+}
+
+// EndAuctionLog 编码拍卖合约EndAuction事件
+func EndAuctionLog(address common.Address, event contracts.NFTAuctionEndAuction) (types.Log, error) {
+	return encodeAuctionEvent(address, "EndAuction", event)
+}
+
+// TransferLog 编码ERC721合约Transfer事件（From为零地址即safeMint）
+func TransferLog(address common.Address, event contracts.ERC721Transfer) (types.Log, error) {
+	parsed, err := contracts.ERC721MetaData.GetAbi()
+	if err != nil {
+		return types.Log{}, err
+	}
+	return EncodeEvent(parsed, address, "Transfer", event)
+}
+
+func encodeAuctionEvent(address common.Address, name string, event interface{}) (types.Log, error) {
+	parsed, err := contracts.NFTAuctionMetaData.GetAbi()
+	if err != nil {
+		return types.Log{}, err
+	}
+	return EncodeEvent(parsed, address, name, event)
+}
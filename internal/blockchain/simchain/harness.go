@@ -0,0 +1,243 @@
+package simchain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/ERC721"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/NFTAuction"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"golang.org/x/sync/errgroup"
+)
+
+// 模拟链默认参数
+const (
+	ChainID = 31337
+
+	waitTimeout  = 10 * time.Second
+	waitInterval = 50 * time.Millisecond
+)
+
+// 模拟合约地址
+var (
+	AuctionAddress = common.HexToAddress("0x00000000000000000000000000000000000a0c71")
+	ERC721Address  = common.HexToAddress("0x0000000000000000000000000000000000000721")
+)
+
+// WaitFor 轮询直到check返回nil或超时（默认10秒），用于等待监听器异步处理完成后断言数据库/Redis状态
+func WaitFor(ctx context.Context, check func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("等待超时: %w", err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Harness 模拟链 + 真实的NFTAuction/ERC721监听器 + 本地元数据服务
+//
+// 监听器写入的数据库/Redis由调用方初始化（如testutil.InitDB、testutil.InitRedis），Harness只负责链侧。
+type Harness struct {
+	Chain    *Chain
+	Metadata *httptest.Server // tokenURI指向的元数据服务
+
+	auction *NFTAuction.Listener
+	erc721  *ERC721.ERC721Listener
+
+	mu        sync.Mutex
+	metadata  map[uint64]ERC721.NFTMetadata // tokenID → 元数据
+	tokenURIs map[uint64]string             // tokenID → tokenURI（未设置时指向Metadata服务）
+
+	cancel context.CancelFunc
+	group  *errgroup.Group
+}
+
+// NewHarness 创建模拟链与监听器，cfg中的链ID与合约地址替换为模拟链的值
+func NewHarness(cfg config.BlockchainConfig) (*Harness, error) {
+	cfg.ChainID = ChainID
+	cfg.ContractAddr = AuctionAddress.Hex()
+	cfg.ERC721ContractAddr = ERC721Address.Hex()
+	cfg.AuctionABIVersions = nil
+	cfg.AuctionContracts = nil
+	cfg.ERC721Artifact = ""
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 3600 // 过期检查间隔（秒），避免与断言竞争
+	}
+
+	h := &Harness{
+		Chain:     New(ChainID),
+		metadata:  make(map[uint64]ERC721.NFTMetadata),
+		tokenURIs: make(map[uint64]string),
+	}
+	h.Metadata = httptest.NewServer(http.HandlerFunc(h.serveMetadata))
+
+	if err := h.mockContracts(); err != nil {
+		h.Close()
+		return nil, err
+	}
+
+	var err error
+	if h.auction, err = NFTAuction.NewListenerWithClient(&cfg, h.Chain.Client()); err != nil {
+		h.Close()
+		return nil, err
+	}
+	if h.erc721, err = ERC721.NewERC721ListenerWithClient(&cfg, h.Chain.Client()); err != nil {
+		h.Close()
+		return nil, err
+	}
+	return h, nil
+}
+
+// Start 后台启动监听器，并等待全部订阅建立
+func (h *Harness) Start(ctx context.Context) error {
+	ctx, h.cancel = context.WithCancel(ctx)
+	h.group, ctx = errgroup.WithContext(ctx)
+	h.group.Go(func() error { return h.auction.Start(ctx) })
+	h.group.Go(func() error { return h.erc721.StartListeningSafeMint(ctx) })
+	return h.Chain.WaitSubscriptions(ctx, h.auction.Subscriptions()+h.erc721.Subscriptions())
+}
+
+// SetResumeBlock 设置监听器订阅后补齐日志的起始区块（模拟回填结束后、启动监听前出块），须在Start之前调用
+func (h *Harness) SetResumeBlock(block uint64) {
+	h.auction.SetResumeBlock(block)
+	h.erc721.SetResumeBlock(block)
+}
+
+// Close 停止监听器并关闭模拟链与元数据服务
+func (h *Harness) Close() {
+	if h.cancel != nil {
+		h.cancel()
+		h.group.Wait()
+	}
+	h.Chain.Close()
+	h.Metadata.Close()
+}
+
+// SetMetadata 设置tokenID的元数据（由Metadata服务返回）
+func (h *Harness) SetMetadata(tokenID uint64, metadata ERC721.NFTMetadata) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.metadata[tokenID] = metadata
+}
+
+// SetTokenURI 覆盖tokenID的tokenURI（如模拟不可访问的链接）
+func (h *Harness) SetTokenURI(tokenID uint64, uri string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tokenURIs[tokenID] = uri
+}
+
+// Mint 出块一条ERC721 Transfer(0x0 → to)日志
+func (h *Harness) Mint(to common.Address, tokenID uint64) error {
+	lg, err := TransferLog(ERC721Address, contracts.ERC721Transfer{To: to, TokenId: new(big.Int).SetUint64(tokenID)})
+	if err != nil {
+		return err
+	}
+	h.Chain.Mine(lg)
+	return nil
+}
+
+// CreateAuction 出块一条CreateAuction日志
+func (h *Harness) CreateAuction(event contracts.NFTAuctionCreateAuction) error {
+	lg, err := CreateAuctionLog(AuctionAddress, event)
+	if err != nil {
+		return err
+	}
+	h.Chain.Mine(lg)
+	return nil
+}
+
+// PlaceBid 出块一条PlaceBid日志
+func (h *Harness) PlaceBid(event contracts.NFTAuctionPlaceBid) error {
+	lg, err := PlaceBidLog(AuctionAddress, event)
+	if err != nil {
+		return err
+	}
+	h.Chain.Mine(lg)
+	return nil
+}
+
+// EndAuction 出块一条EndAuction日志
+func (h *Harness) EndAuction(event contracts.NFTAuctionEndAuction) error {
+	lg, err := EndAuctionLog(AuctionAddress, event)
+	if err != nil {
+		return err
+	}
+	h.Chain.Mine(lg)
+	return nil
+}
+
+// mockContracts 模拟监听器用到的合约只读调用：ERC721.tokenURI、拍卖合约admin与UPGRADE_INTERFACE_VERSION
+func (h *Harness) mockContracts() error {
+	erc721ABI, err := contracts.ERC721MetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	tokenURI := erc721ABI.Methods["tokenURI"]
+	h.Chain.HandleCall(ERC721Address, func(data []byte, _ uint64) ([]byte, error) {
+		if len(data) < 4 || string(data[:4]) != string(tokenURI.ID) {
+			return nil, fmt.Errorf("ERC721未模拟方法0x%x", data[:min(len(data), 4)])
+		}
+		args, err := tokenURI.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, err
+		}
+		return tokenURI.Outputs.Pack(h.tokenURI(args[0].(*big.Int).Uint64()))
+	})
+
+	auctionABI, err := contracts.NFTAuctionMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	if err := h.Chain.MockMethod(AuctionAddress, auctionABI, "admin", common.Address{}); err != nil {
+		return err
+	}
+	return h.Chain.MockMethod(AuctionAddress, auctionABI, "UPGRADE_INTERFACE_VERSION", "5.0.0")
+}
+
+// tokenURI 返回tokenID的元数据链接
+func (h *Harness) tokenURI(tokenID uint64) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if uri, ok := h.tokenURIs[tokenID]; ok {
+		return uri
+	}
+	return fmt.Sprintf("%s/metadata/%d", h.Metadata.URL, tokenID)
+}
+
+// serveMetadata 元数据服务：/metadata/{tokenID}
+func (h *Harness) serveMetadata(w http.ResponseWriter, r *http.Request) {
+	var tokenID uint64
+	if _, err := fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/metadata/"), "%d", &tokenID); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	h.mu.Lock()
+	metadata, ok := h.metadata[tokenID]
+	h.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(metadata)
+}
@@ -0,0 +1,306 @@
+package simchain_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/ERC721"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/simchain"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/redis"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/internal/testutil"
+)
+
+// 端到端验证：模拟链按真实ABI出块 safeMint → CreateAuction → PlaceBid×2 → EndAuction，
+// 由真实监听器写入SQLite/miniredis后逐步断言，不访问任何RPC节点、数据库服务和外部元数据服务。
+var (
+	seller  = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	bidder1 = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	bidder2 = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	ethAddr = common.Address{}
+)
+
+// newHarness 初始化测试库与Redis并创建模拟链上的监听器
+func newHarness(t *testing.T) (context.Context, *simchain.Harness) {
+	t.Helper()
+	testutil.InitDB(t)
+	testutil.InitRedis(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(cancel)
+
+	h, err := simchain.NewHarness(config.BlockchainConfig{})
+	if err != nil {
+		t.Fatalf("创建模拟链失败: %v", err)
+	}
+	t.Cleanup(h.Close)
+	return ctx, h
+}
+
+// startHarness 创建并启动模拟链上的监听器
+func startHarness(t *testing.T) (context.Context, *simchain.Harness) {
+	t.Helper()
+	ctx, h := newHarness(t)
+	if err := h.Start(ctx); err != nil {
+		t.Fatalf("启动监听器失败: %v", err)
+	}
+	return ctx, h
+}
+
+func TestAuctionLifecycle(t *testing.T) {
+	tests := []struct {
+		name string
+		id   uint64
+	}{
+		{"小编号", 1},
+		{"超过int32的编号", 1_000_000_007},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, h := startHarness(t)
+			if err := runLifecycle(ctx, h, tt.id); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestCatchUpAfterBackfill 回填结束后、订阅建立前出块的日志在订阅后补齐，订阅再次收到的同一区块日志不重复处理
+func TestCatchUpAfterBackfill(t *testing.T) {
+	ctx, h := newHarness(t)
+
+	// 回填到创世区块后、启动监听前出块
+	h.SetMetadata(1, ERC721.NFTMetadata{Name: "Missed", Image: "ipfs://bafy/1.png"})
+	if err := h.Mint(seller, 1); err != nil {
+		t.Fatal(err)
+	}
+	start := h.Chain.Head().Time
+	if err := h.CreateAuction(contracts.NFTAuctionCreateAuction{
+		AuctionId:         big.NewInt(1),
+		Seller:            seller,
+		Duration:          big.NewInt(3600),
+		StartPrice:        big.NewInt(100),
+		StartTokenAddress: ethAddr,
+		StartTime:         new(big.Int).SetUint64(start),
+		NftContract:       simchain.ERC721Address,
+		NftId:             big.NewInt(1),
+		OptTime:           new(big.Int).SetUint64(start),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.PlaceBid(contracts.NFTAuctionPlaceBid{
+		AuctionId:    big.NewInt(1),
+		Bidder:       bidder1,
+		Amount:       big.NewInt(150),
+		TokenAddress: ethAddr,
+		OptTime:      new(big.Int).SetUint64(h.Chain.Head().Time),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	h.SetResumeBlock(1)
+	if err := h.Start(ctx); err != nil {
+		t.Fatalf("启动监听器失败: %v", err)
+	}
+	if err := simchain.WaitFor(ctx, func() error {
+		if _, err := repository.NewNFTRepository().GetNFTByTokenID(simchain.ChainID, simchain.ERC721Address.Hex(), 1); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("safeMint未补齐: %v", err)
+	}
+	if err := waitHighestBid(ctx, 1, bidder1, 150, 1); err != nil {
+		t.Fatalf("拍卖事件未补齐: %v", err)
+	}
+
+	// 补齐之后的实时日志正常处理，出价只记录一次
+	if err := h.PlaceBid(contracts.NFTAuctionPlaceBid{
+		AuctionId:    big.NewInt(1),
+		Bidder:       bidder2,
+		Amount:       big.NewInt(200),
+		TokenAddress: ethAddr,
+		OptTime:      new(big.Int).SetUint64(h.Chain.Head().Time),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitHighestBid(ctx, 1, bidder2, 200, 2); err != nil {
+		t.Fatalf("实时出价: %v", err)
+	}
+	bids, err := repository.NewBidRepository().GetByAuctionID(auctionKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bids) != 2 {
+		t.Errorf("出价记录%d条，期望2条", len(bids))
+	}
+}
+
+// runLifecycle 依次出块并断言，tokenID与拍卖ID均为id
+func runLifecycle(ctx context.Context, h *simchain.Harness, id uint64) error {
+	tokenID := new(big.Int).SetUint64(id)
+	auctionID := new(big.Int).SetUint64(id)
+
+	// 1. safeMint：元数据经清洗后入库
+	h.SetMetadata(id, ERC721.NFTMetadata{
+		Name:        "Sim <b>NFT</b><script>alert(1)</script>",
+		Description: "simulated",
+		Image:       "ipfs://bafy/sim.png",
+	})
+	if err := h.Mint(seller, id); err != nil {
+		return err
+	}
+	if err := simchain.WaitFor(ctx, func() error {
+		nft, err := repository.NewNFTRepository().GetNFTByTokenID(simchain.ChainID, simchain.ERC721Address.Hex(), uint(id))
+		if err != nil {
+			return err
+		}
+		if nft.OwnerAddress != seller.Hex() {
+			return fmt.Errorf("NFT持有者%s，期望%s", nft.OwnerAddress, seller.Hex())
+		}
+		if strings.Contains(nft.Name, "<") || !strings.Contains(nft.Name, "Sim") {
+			return fmt.Errorf("NFT名称未清洗: %q", nft.Name)
+		}
+		if nft.ContractAddress != simchain.ERC721Address.Hex() {
+			return fmt.Errorf("NFT合约%s，期望%s", nft.ContractAddress, simchain.ERC721Address.Hex())
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("safeMint: %w", err)
+	}
+
+	// 2. CreateAuction
+	start := h.Chain.Head().Time
+	if err := h.CreateAuction(contracts.NFTAuctionCreateAuction{
+		AuctionId:         auctionID,
+		Seller:            seller,
+		Duration:          big.NewInt(3600),
+		StartPrice:        big.NewInt(100),
+		StartTokenAddress: ethAddr,
+		StartTime:         new(big.Int).SetUint64(start),
+		NftContract:       simchain.ERC721Address,
+		NftId:             tokenID,
+		OptTime:           new(big.Int).SetUint64(start),
+	}); err != nil {
+		return err
+	}
+	if err := simchain.WaitFor(ctx, func() error {
+		auction, err := repository.NewAuctionRepository().GetByID(auctionKey(id))
+		if err != nil {
+			return err
+		}
+		if auction.CreatorAddress != seller.Hex() || auction.StartPrice != 100 || auction.NFTTokenID != uint(id) {
+			return fmt.Errorf("拍卖数据不一致: %+v", auction)
+		}
+		if got := auction.EndTime.Sub(auction.StartTime); got != time.Hour {
+			return fmt.Errorf("拍卖时长%s，期望1h", got)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("CreateAuction: %w", err)
+	}
+
+	// 3. 两次出价：最高价与出价者更新，Redis热度累加
+	for i, bid := range []struct {
+		bidder common.Address
+		amount int64
+	}{{bidder1, 150}, {bidder2, 200}} {
+		if err := h.PlaceBid(contracts.NFTAuctionPlaceBid{
+			AuctionId:    auctionID,
+			Bidder:       bid.bidder,
+			Amount:       big.NewInt(bid.amount),
+			TokenAddress: ethAddr,
+			OptTime:      new(big.Int).SetUint64(h.Chain.Head().Time),
+		}); err != nil {
+			return err
+		}
+		if err := waitHighestBid(ctx, id, bid.bidder, uint64(bid.amount), float64(i+1)); err != nil {
+			return fmt.Errorf("PlaceBid#%d: %w", i+1, err)
+		}
+	}
+
+	// 4. EndAuction：状态结束、已结算、仅获胜出价被标记、成交结果保存，热度排行移除
+	if err := h.EndAuction(contracts.NFTAuctionEndAuction{
+		AuctionId:    auctionID,
+		Winner:       bidder2,
+		Amount:       big.NewInt(200),
+		TokenAddress: ethAddr,
+		OptTime:      new(big.Int).SetUint64(h.Chain.Head().Time),
+	}); err != nil {
+		return err
+	}
+	if err := simchain.WaitFor(ctx, func() error {
+		auction, err := repository.NewAuctionRepository().GetByID(auctionKey(id))
+		if err != nil {
+			return err
+		}
+		if auction.Status != models.AuctionStatusEnded || !auction.Settled {
+			return fmt.Errorf("拍卖状态%s，已结算%v", auction.Status, auction.Settled)
+		}
+		winning, err := repository.NewBidRepository().GetHighestBidByAuctionID(auctionKey(id))
+		if err != nil {
+			return err
+		}
+		if !winning.IsWinning || winning.BidderAddress != bidder2.Hex() {
+			return fmt.Errorf("获胜出价未标记: %+v", winning)
+		}
+		bids, err := repository.NewBidRepository().GetByAuctionID(auctionKey(id))
+		if err != nil {
+			return err
+		}
+		for _, bid := range bids {
+			if bid.IsWinning && bid.ID != winning.ID {
+				return fmt.Errorf("非获胜出价被标记: %+v", bid)
+			}
+		}
+		result, err := repository.NewAuctionResultRepository().GetByAuction(auctionKey(id))
+		if err != nil {
+			return err
+		}
+		if result == nil || result.WinnerAddress != bidder2.Hex() || result.FinalPrice != 200 ||
+			result.WinningBidID == nil || *result.WinningBidID != winning.ID {
+			return fmt.Errorf("成交结果不一致: %+v", result)
+		}
+		if _, ok, err := redis.GetAuctionHot(auctionKey(id)); err != nil || ok {
+			return fmt.Errorf("热度排行未移除（err=%v）", err)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("EndAuction: %w", err)
+	}
+	return nil
+}
+
+// waitHighestBid 等待拍卖最高价、出价者与Redis热度达到期望值
+func waitHighestBid(ctx context.Context, id uint64, bidder common.Address, amount uint64, hot float64) error {
+	return simchain.WaitFor(ctx, func() error {
+		auction, err := repository.NewAuctionRepository().GetByID(auctionKey(id))
+		if err != nil {
+			return err
+		}
+		if auction.HighestBid != amount || auction.HighestBidder != bidder.Hex() {
+			return fmt.Errorf("最高价%d/%s，期望%d/%s", auction.HighestBid, auction.HighestBidder, amount, bidder.Hex())
+		}
+		score, _, err := redis.GetAuctionHot(auctionKey(id))
+		if err != nil {
+			return err
+		}
+		if score != hot {
+			return fmt.Errorf("拍卖热度%v，期望%v", score, hot)
+		}
+		return nil
+	})
+}
+
+// auctionKey 模拟链上拍卖id的标识
+func auctionKey(id uint64) models.AuctionKey {
+	return models.AuctionKey{ChainID: simchain.ChainID, Contract: simchain.AuctionAddress.Hex(), ID: id}
+}
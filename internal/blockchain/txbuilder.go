@@ -0,0 +1,186 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/contracts"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/reverts"
+)
+
+// UnsignedTx 待钱包签名的交易
+type UnsignedTx struct {
+	Description string         `json:"description"` // 交易说明
+	ChainID     *hexutil.Big   `json:"chainId"`
+	From        common.Address `json:"from"`
+	To          common.Address `json:"to"`
+	Data        hexutil.Bytes  `json:"data"`
+	Value       *hexutil.Big   `json:"value"`
+	Gas         hexutil.Uint64 `json:"gas"`              // 燃气估算，无法估算（如前置交易未执行）时为0
+	Revert      *reverts.Error `json:"revert,omitempty"` // 估算燃气时的回滚原因，非空说明交易将失败
+}
+
+// NewUnsignedTx 构造待签名交易，estimate为true时估算燃气；估算回滚时记录在Revert中，其它错误返回error
+func (c *AuctionContract) NewUnsignedTx(ctx context.Context, description string, from, to common.Address, data []byte, value *big.Int, estimate bool) (*UnsignedTx, error) {
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	tx := &UnsignedTx{
+		Description: description,
+		ChainID:     (*hexutil.Big)(c.chainID),
+		From:        from,
+		To:          to,
+		Data:        data,
+		Value:       (*hexutil.Big)(value),
+	}
+	if !estimate {
+		return tx, nil
+	}
+
+	gas, err := c.client.EstimateGas(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    &to,
+		Data:  data,
+		Value: value,
+	})
+	if err != nil {
+		revert, ok := c.decoder.FromError(err)
+		if !ok {
+			return nil, err
+		}
+		tx.Revert = revert
+		return tx, nil
+	}
+	tx.Gas = hexutil.Uint64(gas)
+	return tx, nil
+}
+
+// NFTOwner 查询NFT当前持有人
+func (c *AuctionContract) NFTOwner(ctx context.Context, nftContract common.Address, tokenID *big.Int) (common.Address, error) {
+	nft, err := contracts.NewERC721Caller(nftContract, c.client)
+	if err != nil {
+		return common.Address{}, err
+	}
+	owner, err := nft.OwnerOf(&bind.CallOpts{Context: ctx}, tokenID)
+	if err != nil {
+		return common.Address{}, c.decoder.Wrap(err)
+	}
+	return owner, nil
+}
+
+// NFTApprovalTx 检查拍卖合约是否有权转移NFT，未授权时返回ERC721 approve交易，已授权返回nil
+func (c *AuctionContract) NFTApprovalTx(ctx context.Context, owner, nftContract common.Address, tokenID *big.Int) (*UnsignedTx, error) {
+	nft, err := contracts.NewERC721Caller(nftContract, c.client)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	approvedForAll, err := nft.IsApprovedForAll(opts, owner, c.address)
+	if err != nil {
+		return nil, err
+	}
+	if approvedForAll {
+		return nil, nil
+	}
+	approved, err := nft.GetApproved(opts, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if approved == c.address {
+		return nil, nil
+	}
+
+	erc721ABI, err := contracts.ERC721MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	data, err := erc721ABI.Pack("approve", c.address, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	// 也可改用setApprovalForAll(auction, true)一次授权全部NFT
+	return c.NewUnsignedTx(ctx, "ERC721 approve：授权拍卖合约转移NFT", owner, nftContract, data, nil, true)
+}
+
+// ERC20Balance 查询ERC20余额
+func (c *AuctionContract) ERC20Balance(ctx context.Context, token, owner common.Address) (*big.Int, error) {
+	erc20, err := contracts.NewERC20Caller(token, c.client)
+	if err != nil {
+		return nil, err
+	}
+	return erc20.BalanceOf(&bind.CallOpts{Context: ctx}, owner)
+}
+
+// ERC20ApprovalTx 检查拍卖合约的ERC20授权额度，不足时返回ERC20 approve交易，额度足够返回nil
+func (c *AuctionContract) ERC20ApprovalTx(ctx context.Context, owner, token common.Address, amount *big.Int) (*UnsignedTx, error) {
+	erc20, err := contracts.NewERC20Caller(token, c.client)
+	if err != nil {
+		return nil, err
+	}
+	allowance, err := erc20.Allowance(&bind.CallOpts{Context: ctx}, owner, c.address)
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(amount) >= 0 {
+		return nil, nil
+	}
+
+	erc20ABI, err := contracts.ERC20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	data, err := erc20ABI.Pack("approve", c.address, amount)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewUnsignedTx(ctx, "ERC20 approve：授权拍卖合约扣除出价金额", owner, token, data, nil, true)
+}
+
+// ETHBalance 查询ETH余额
+func (c *AuctionContract) ETHBalance(ctx context.Context, owner common.Address) (*big.Int, error) {
+	return c.client.BalanceAt(ctx, owner, nil)
+}
+
+// CallResult eth_call模拟执行结果
+type CallResult struct {
+	BlockNumber uint64         `json:"block_number"`     // 模拟所在区块
+	Success     bool           `json:"success"`          // 是否执行成功
+	Revert      *reverts.Error `json:"revert,omitempty"` // 回滚原因
+}
+
+// SimulatePlaceBid 以出价者身份在最新区块eth_call执行placeBid
+func (c *AuctionContract) SimulatePlaceBid(ctx context.Context, bidder common.Address, auctionID, bidAmount *big.Int, tokenAddress common.Address) (*CallResult, error) {
+	data, err := c.PackPlaceBid(auctionID, bidAmount, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// 固定区块号，保证返回的区块与执行所用状态一致
+	header, err := c.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := &CallResult{BlockNumber: header.Number.Uint64()}
+
+	_, err = c.client.CallContract(ctx, ethereum.CallMsg{
+		From:  bidder,
+		To:    &c.address,
+		Data:  data,
+		Value: BidValue(bidAmount, tokenAddress),
+	}, header.Number)
+	if err != nil {
+		revert, ok := c.DecodeRevert(err)
+		if !ok {
+			return nil, err
+		}
+		result.Revert = revert
+		return result, nil
+	}
+	result.Success = true
+	return result, nil
+}
@@ -0,0 +1,544 @@
+// Package txmanager 后端交易管理
+//
+// 负责本地nonce分配与恢复、EIP-1559（type 2）交易构造、未上链交易的加价替换，
+// 以及可取消、带超时的回执等待，所有交易持久化到transactions表。
+package txmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/reverts"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/signer"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+)
+
+// ErrReceiptTimeout 等待回执超时（交易仍可能在之后上链）
+var ErrReceiptTimeout = errors.New("等待交易回执超时")
+
+// Backend 交易管理依赖的链上接口（*ethclient.Client已实现）
+type Backend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// TxRequest 待发送的合约调用
+type TxRequest struct {
+	Purpose   string         // 交易用途，写入交易表
+	AuctionID uint64         // 关联拍卖ID，无关联时为0
+	To        common.Address // 目标合约
+	Data      []byte         // ABI编码后的调用数据
+	Value     *big.Int       // 转账金额，nil表示0
+	GasLimit  uint64         // 燃气上限，0表示自动估算
+}
+
+// Manager 单个发送地址的交易管理器（并发安全）
+type Manager struct {
+	backend Backend
+	chainID *big.Int
+	signer  signer.Signer
+	from    common.Address
+	cfg     config.TxManagerConfig
+	repo    repository.TransactionRepository
+	decoder *reverts.Decoder // 回滚原因解析器
+
+	mu          sync.Mutex
+	nextNonce   uint64   // 下一个可分配的nonce
+	nonceSynced bool     // nextNonce是否已与链上/交易表同步
+	released    []uint64 // 已归还、小于nextNonce的nonce（升序），优先重新分配，避免留下阻塞后续交易的空缺
+}
+
+// NewManager 创建交易管理器
+func NewManager(backend Backend, chainID *big.Int, txSigner signer.Signer, cfg config.TxManagerConfig) *Manager {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.BumpPercent < 10 {
+		cfg.BumpPercent = 10
+	}
+	return &Manager{
+		backend: backend,
+		chainID: chainID,
+		signer:  txSigner,
+		from:    txSigner.Address(),
+		cfg:     cfg,
+		repo:    repository.NewTransactionRepository(),
+		decoder: reverts.NewDecoder(),
+	}
+}
+
+// SetRevertDecoder 设置回滚原因解析器（用于解析目标合约的自定义错误）
+func (m *Manager) SetRevertDecoder(decoder *reverts.Decoder) {
+	m.decoder = decoder
+}
+
+// From 返回发送地址
+func (m *Manager) From() common.Address {
+	return m.from
+}
+
+// allocateNonce 分配nonce：优先复用已归还的最小nonce；首次使用或需要重新同步时，
+// 以链上pending nonce与交易表中待上链的最大nonce+1的较大值为准
+func (m *Manager) allocateNonce(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.nonceSynced {
+		pending, err := m.backend.PendingNonceAt(ctx, m.from)
+		if err != nil {
+			return 0, fmt.Errorf("获取nonce失败: %w", err)
+		}
+		nonce := pending
+		if maxNonce, ok, err := m.repo.GetMaxPendingNonce(m.chainID.Uint64(), m.from.Hex()); err == nil && ok && maxNonce+1 > nonce {
+			nonce = maxNonce + 1
+		}
+		// 已被链上使用的归还nonce丢弃，其余仍是需要填补的空缺
+		kept := m.released[:0]
+		for _, n := range m.released {
+			if n >= pending && n < nonce {
+				kept = append(kept, n)
+			}
+		}
+		m.released = kept
+		m.nextNonce = nonce
+		m.nonceSynced = true
+	}
+
+	if len(m.released) > 0 {
+		nonce := m.released[0]
+		m.released = m.released[1:]
+		return nonce, nil
+	}
+	nonce := m.nextNonce
+	m.nextNonce++
+	return nonce, nil
+}
+
+// releaseNonce 交易未能发送时归还nonce：归还的是最后分配的nonce时回退nextNonce，
+// 否则记入空闲列表，下次分配时优先复用，避免空缺阻塞后续交易
+func (m *Manager) releaseNonce(nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if nonce >= m.nextNonce {
+		return
+	}
+	i := sort.Search(len(m.released), func(i int) bool { return m.released[i] >= nonce })
+	if i < len(m.released) && m.released[i] == nonce {
+		return
+	}
+	m.released = append(m.released, 0)
+	copy(m.released[i+1:], m.released[i:])
+	m.released[i] = nonce
+
+	// 末尾连续归还的nonce直接回退nextNonce
+	for len(m.released) > 0 && m.released[len(m.released)-1] == m.nextNonce-1 {
+		m.nextNonce--
+		m.released = m.released[:len(m.released)-1]
+	}
+}
+
+// resyncNonce 强制下次分配时重新同步nonce
+func (m *Manager) resyncNonce() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nonceSynced = false
+}
+
+// suggestFees 计算EIP-1559费用：feeCap = 2*baseFee + tip
+func (m *Manager) suggestFees(ctx context.Context) (tipCap, feeCap *big.Int, err error) {
+	tipCap, err = m.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取小费建议失败: %w", err)
+	}
+	head, err := m.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取最新区块失败: %w", err)
+	}
+	if head.BaseFee == nil {
+		return nil, nil, errors.New("链不支持EIP-1559")
+	}
+
+	feeCap = new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tipCap)
+	if maxFeeCap := m.maxFeeCap(); maxFeeCap != nil && feeCap.Cmp(maxFeeCap) > 0 {
+		feeCap = maxFeeCap
+		if tipCap.Cmp(feeCap) > 0 {
+			tipCap = new(big.Int).Set(feeCap)
+		}
+	}
+	return tipCap, feeCap, nil
+}
+
+// maxFeeCap 配置的燃气费上限（wei），未配置返回nil
+func (m *Manager) maxFeeCap() *big.Int {
+	if m.cfg.MaxFeeCapGwei <= 0 {
+		return nil
+	}
+	return new(big.Int).Mul(big.NewInt(m.cfg.MaxFeeCapGwei), big.NewInt(1e9))
+}
+
+// Send 构造、签名并发送交易，返回持久化后的交易记录
+func (m *Manager) Send(ctx context.Context, req TxRequest) (*models.Transaction, error) {
+	value := req.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	gasLimit := req.GasLimit
+	if gasLimit == 0 {
+		estimated, err := m.backend.EstimateGas(ctx, ethereum.CallMsg{
+			From:  m.from,
+			To:    &req.To,
+			Data:  req.Data,
+			Value: value,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("估算燃气限制失败: %w", m.decoder.Wrap(err))
+		}
+		gasLimit = estimated
+	}
+
+	tipCap, feeCap, err := m.suggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// nonce冲突（如其他进程使用了同一地址）时重新同步后重试一次
+	for attempt := 0; ; attempt++ {
+		nonce, err := m.allocateNonce(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		signedTx, err := m.sign(ctx, &types.DynamicFeeTx{
+			ChainID:   m.chainID,
+			Nonce:     nonce,
+			GasTipCap: tipCap,
+			GasFeeCap: feeCap,
+			Gas:       gasLimit,
+			To:        &req.To,
+			Value:     value,
+			Data:      req.Data,
+		})
+		if err != nil {
+			m.releaseNonce(nonce)
+			return nil, err
+		}
+
+		// 先记录再广播：记录失败时交易尚未发出，归还nonce后返回错误，不会出现链上有交易而交易表无记录
+		record := &models.Transaction{
+			ChainID:     m.chainID.Uint64(),
+			OptTime:     time.Now(),
+			Purpose:     req.Purpose,
+			AuctionID:   req.AuctionID,
+			FromAddress: m.from.Hex(),
+			ToAddress:   req.To.Hex(),
+			Nonce:       nonce,
+			Hash:        signedTx.Hash().Hex(),
+			Value:       value.String(),
+			Data:        hexutil.Encode(req.Data),
+			GasLimit:    gasLimit,
+			GasTipCap:   tipCap.String(),
+			GasFeeCap:   feeCap.String(),
+			Status:      models.TransactionStatusPending,
+			SubmittedAt: time.Now(),
+		}
+		if err := m.repo.Create(record); err != nil {
+			m.releaseNonce(nonce)
+			return nil, fmt.Errorf("记录交易失败: %w", err)
+		}
+
+		err = m.broadcast(ctx, signedTx)
+		if err == nil {
+			log.Info().Str("purpose", req.Purpose).Str("hash", record.Hash).Uint64("nonce", nonce).Msg("交易已发送")
+			return record, nil
+		}
+		m.update(record, map[string]interface{}{"status": models.TransactionStatusDropped, "error": err.Error()})
+		if !isNonceError(err) {
+			m.releaseNonce(nonce)
+			return nil, err
+		}
+		m.resyncNonce()
+		if attempt >= 1 {
+			return nil, err
+		}
+		log.Warn().Err(err).Uint64("nonce", nonce).Msg("nonce冲突，重新同步后重试")
+	}
+}
+
+// signAndSend 签名并广播交易
+func (m *Manager) signAndSend(ctx context.Context, txData *types.DynamicFeeTx) (*types.Transaction, error) {
+	signedTx, err := m.sign(ctx, txData)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.broadcast(ctx, signedTx); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+// sign 签名交易
+func (m *Manager) sign(ctx context.Context, txData *types.DynamicFeeTx) (*types.Transaction, error) {
+	signedTx, err := m.signer.SignTx(ctx, types.NewTx(txData), m.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("交易签名失败: %w", err)
+	}
+	return signedTx, nil
+}
+
+// broadcast 广播已签名的交易
+func (m *Manager) broadcast(ctx context.Context, signedTx *types.Transaction) error {
+	if err := m.backend.SendTransaction(ctx, signedTx); err != nil {
+		// 同一笔已签名交易已在节点交易池中（如传输超时后重发），视为发送成功
+		if IsAlreadyKnown(err) {
+			log.Info().Str("hash", signedTx.Hash().Hex()).Msg("交易已在交易池中")
+			return nil
+		}
+		return fmt.Errorf("交易发送失败: %w", err)
+	}
+	return nil
+}
+
+// WaitMined 等待交易上链：超过BumpInterval未上链则加价替换（同nonce），超过ReceiptTimeout返回ErrReceiptTimeout
+// 交易上链但执行失败时同时返回回执和回滚原因（*reverts.Error）
+func (m *Manager) WaitMined(ctx context.Context, record *models.Transaction) (*types.Receipt, error) {
+	if m.cfg.ReceiptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.cfg.ReceiptTimeout)
+		defer cancel()
+	}
+
+	current, err := m.rebuild(record)
+	if err != nil {
+		return nil, err
+	}
+	// 替换后旧交易仍可能被打包，需同时查询所有哈希（超时后重新等待时包括之前替换掉的哈希）
+	hashes := []common.Hash{common.HexToHash(record.Hash)}
+	for _, replaced := range record.ReplacedHashes {
+		hashes = append(hashes, common.HexToHash(replaced))
+	}
+	lastSent := time.Now()
+
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, hash := range hashes {
+			receipt, err := m.backend.TransactionReceipt(ctx, hash)
+			if err == nil {
+				return receipt, m.markMined(ctx, record, hash, receipt)
+			}
+			if !errors.Is(err, ethereum.NotFound) && ctx.Err() == nil {
+				log.Warn().Err(err).Str("hash", hash.Hex()).Msg("查询交易回执失败")
+			}
+		}
+
+		if m.cfg.BumpInterval > 0 && time.Since(lastSent) >= m.cfg.BumpInterval && record.Replacements < m.cfg.MaxBumps {
+			replaced, err := m.bump(ctx, record, current)
+			if err != nil {
+				log.Warn().Err(err).Str("hash", record.Hash).Msg("交易加价替换失败")
+			} else {
+				hashes = append(hashes, replaced.Hash())
+			}
+			lastSent = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				m.update(record, map[string]interface{}{"status": models.TransactionStatusTimeout})
+				return nil, ErrReceiptTimeout
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendAndWait 发送交易并等待上链
+func (m *Manager) SendAndWait(ctx context.Context, req TxRequest) (*types.Receipt, *models.Transaction, error) {
+	record, err := m.Send(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	receipt, err := m.WaitMined(ctx, record)
+	return receipt, record, err
+}
+
+// rebuild 根据交易记录还原未签名交易（用于加价替换）
+func (m *Manager) rebuild(record *models.Transaction) (*types.DynamicFeeTx, error) {
+	data, err := hexutil.Decode(record.Data)
+	if err != nil && record.Data != "" {
+		return nil, fmt.Errorf("解析交易数据失败: %w", err)
+	}
+	value, _ := new(big.Int).SetString(record.Value, 10)
+	tipCap, _ := new(big.Int).SetString(record.GasTipCap, 10)
+	feeCap, _ := new(big.Int).SetString(record.GasFeeCap, 10)
+	if value == nil || tipCap == nil || feeCap == nil {
+		return nil, errors.New("交易记录金额字段格式错误")
+	}
+	to := common.HexToAddress(record.ToAddress)
+	return &types.DynamicFeeTx{
+		ChainID:   m.chainID,
+		Nonce:     record.Nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       record.GasLimit,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	}, nil
+}
+
+// bump 以同一nonce提高费用重新发送，返回替换后的交易
+func (m *Manager) bump(ctx context.Context, record *models.Transaction, current *types.DynamicFeeTx) (*types.Transaction, error) {
+	next := *current
+	next.GasTipCap = bumpFee(current.GasTipCap, m.cfg.BumpPercent)
+	next.GasFeeCap = bumpFee(current.GasFeeCap, m.cfg.BumpPercent)
+
+	// 当前网络费用更高时直接采用网络费用
+	if tipCap, feeCap, err := m.suggestFees(ctx); err == nil {
+		if tipCap.Cmp(next.GasTipCap) > 0 {
+			next.GasTipCap = tipCap
+		}
+		if feeCap.Cmp(next.GasFeeCap) > 0 {
+			next.GasFeeCap = feeCap
+		}
+	}
+	if maxFeeCap := m.maxFeeCap(); maxFeeCap != nil && next.GasFeeCap.Cmp(maxFeeCap) > 0 {
+		return nil, fmt.Errorf("加价后燃气费超过上限%d gwei", m.cfg.MaxFeeCapGwei)
+	}
+	if next.GasTipCap.Cmp(next.GasFeeCap) > 0 {
+		next.GasTipCap = new(big.Int).Set(next.GasFeeCap)
+	}
+
+	signedTx, err := m.signAndSend(ctx, &next)
+	if err != nil {
+		return nil, err
+	}
+
+	*current = next
+	replacedHash := record.Hash
+	record.Replacements++
+	record.ReplacedHashes = append(record.ReplacedHashes, replacedHash)
+	record.Hash = signedTx.Hash().Hex()
+	record.GasTipCap = next.GasTipCap.String()
+	record.GasFeeCap = next.GasFeeCap.String()
+	if record.ID != 0 {
+		// 按字段名更新时不经过gorm序列化器，需手动编码
+		replacedHashes, _ := json.Marshal(record.ReplacedHashes)
+		if err := m.repo.Replace(record.ID, map[string]interface{}{
+			"hash":            record.Hash,
+			"gas_tip_cap":     record.GasTipCap,
+			"gas_fee_cap":     record.GasFeeCap,
+			"replacements":    record.Replacements,
+			"replaced_hashes": string(replacedHashes),
+		}, replacedHash); err != nil {
+			log.Warn().Err(err).Str("hash", record.Hash).Msg("更新交易记录失败")
+		}
+	}
+
+	log.Info().Str("hash", record.Hash).Uint64("nonce", record.Nonce).Int("replacements", record.Replacements).Msg("交易已加价替换")
+	return signedTx, nil
+}
+
+// markMined 根据回执更新交易记录，执行失败时记录并返回回滚原因
+func (m *Manager) markMined(ctx context.Context, record *models.Transaction, hash common.Hash, receipt *types.Receipt) error {
+	status := models.TransactionStatusConfirmed
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = models.TransactionStatusFailed
+	}
+	now := time.Now()
+	record.Status = status
+	record.Hash = hash.Hex()
+	record.BlockNumber = receipt.BlockNumber.Uint64()
+	record.GasUsed = receipt.GasUsed
+	record.ConfirmedAt = &now
+	fields := map[string]interface{}{
+		"status":       status,
+		"hash":         record.Hash,
+		"block_number": record.BlockNumber,
+		"gas_used":     record.GasUsed,
+		"confirmed_at": now,
+	}
+	if receipt.EffectiveGasPrice != nil {
+		record.GasPrice = receipt.EffectiveGasPrice.String()
+		record.GasCost = new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed)).String()
+		fields["gas_price"] = record.GasPrice
+		fields["gas_cost"] = record.GasCost
+	}
+	var failure error
+	if status == models.TransactionStatusFailed {
+		failure = m.failureReason(ctx, record, receipt)
+		record.Error = failure.Error()
+		fields["error"] = record.Error
+	}
+	m.update(record, fields)
+	return failure
+}
+
+// failureReason 重放失败交易获取回滚原因
+func (m *Manager) failureReason(ctx context.Context, record *models.Transaction, receipt *types.Receipt) error {
+	txData, err := m.rebuild(record)
+	if err != nil {
+		return err
+	}
+	revert, err := m.decoder.Replay(ctx, m.backend, m.from, types.NewTx(txData), receipt)
+	if err != nil {
+		log.Warn().Err(err).Str("hash", record.Hash).Msg("重放失败交易获取回滚原因失败")
+		return errors.New("交易执行失败")
+	}
+	return revert
+}
+
+// update 更新交易记录，交易未成功入库时跳过
+func (m *Manager) update(record *models.Transaction, fields map[string]interface{}) {
+	if record.ID == 0 {
+		return
+	}
+	if err := m.repo.Update(record.ID, fields); err != nil {
+		log.Warn().Err(err).Str("hash", record.Hash).Msg("更新交易记录失败")
+	}
+}
+
+// bumpFee 按百分比提高费用（向上取整，保证满足节点替换要求）
+func bumpFee(fee *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// isNonceError 判断是否为nonce冲突导致的发送失败
+// "already known"表示同一笔交易已在交易池中，不属于nonce冲突（见IsAlreadyKnown）
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "nonce too high") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+// IsAlreadyKnown 判断节点是否因同一笔交易（相同哈希）已在交易池中而拒绝
+func IsAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
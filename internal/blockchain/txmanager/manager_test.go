@@ -0,0 +1,266 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/signer"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+)
+
+const testKey = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+
+// fakeBackend 记录发送的交易，按sendErrs依次返回发送错误
+type fakeBackend struct {
+	mu           sync.Mutex
+	pendingNonce uint64
+	sendErrs     []error
+	sent         []*types.Transaction
+}
+
+func (b *fakeBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return b.pendingNonce, nil
+}
+func (b *fakeBackend) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return big.NewInt(1e9), nil
+}
+func (b *fakeBackend) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(1), BaseFee: big.NewInt(1e9)}, nil
+}
+func (b *fakeBackend) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return 21000, nil
+}
+func (b *fakeBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = append(b.sent, tx)
+	if len(b.sendErrs) > 0 {
+		err := b.sendErrs[0]
+		b.sendErrs = b.sendErrs[1:]
+		if isNonceError(err) {
+			b.pendingNonce = tx.Nonce() + 1 // 模拟nonce已被其他进程使用
+		}
+		return err
+	}
+	return nil
+}
+func (b *fakeBackend) TransactionReceipt(context.Context, common.Hash) (*types.Receipt, error) {
+	return nil, ethereum.NotFound
+}
+func (b *fakeBackend) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+// fakeTxRepo 只实现交易管理器用到的方法
+type fakeTxRepo struct {
+	repository.TransactionRepository
+	maxPending *uint64
+	createErr  error
+	created    []*models.Transaction
+}
+
+func (r *fakeTxRepo) GetMaxPendingNonce(uint64, string) (uint64, bool, error) {
+	if r.maxPending == nil {
+		return 0, false, nil
+	}
+	return *r.maxPending, true, nil
+}
+func (r *fakeTxRepo) Create(tx *models.Transaction) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	tx.ID = uint(len(r.created) + 1)
+	r.created = append(r.created, tx)
+	return nil
+}
+func (r *fakeTxRepo) Update(id uint, fields map[string]interface{}) error {
+	if status, ok := fields["status"].(models.TransactionStatus); ok {
+		r.created[id-1].Status = status
+	}
+	return nil
+}
+
+func newTestManager(t *testing.T, backend *fakeBackend, repo *fakeTxRepo) *Manager {
+	t.Helper()
+	txSigner, err := signer.NewDevSigner(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(backend, big.NewInt(1337), txSigner, config.TxManagerConfig{})
+	m.repo = repo
+	return m
+}
+
+func TestNonceAllocateRelease(t *testing.T) {
+	maxPending := uint64(11)
+	tests := []struct {
+		name  string
+		repo  *fakeTxRepo
+		steps []string // "a"分配，"rN"归还nonce N，"s"强制重新同步
+		want  []uint64 // 每次分配得到的nonce
+		next  uint64   // 结束时的nextNonce
+	}{
+		{
+			name:  "首次分配以链上pending nonce为准",
+			repo:  &fakeTxRepo{},
+			steps: []string{"a", "a", "a"},
+			want:  []uint64{5, 6, 7},
+			next:  8,
+		},
+		{
+			name:  "交易表中待上链的nonce更大时从其后分配",
+			repo:  &fakeTxRepo{maxPending: &maxPending},
+			steps: []string{"a", "a"},
+			want:  []uint64{12, 13},
+			next:  14,
+		},
+		{
+			name:  "归还最后分配的nonce时回退",
+			repo:  &fakeTxRepo{},
+			steps: []string{"a", "a", "r6", "a"},
+			want:  []uint64{5, 6, 6},
+			next:  7,
+		},
+		{
+			name:  "归还中间的nonce时优先复用最小的空缺",
+			repo:  &fakeTxRepo{},
+			steps: []string{"a", "a", "a", "a", "r7", "r6", "a", "a", "a"},
+			want:  []uint64{5, 6, 7, 8, 6, 7, 9},
+			next:  10,
+		},
+		{
+			name:  "末尾连续归还时回退到最小的空缺",
+			repo:  &fakeTxRepo{},
+			steps: []string{"a", "a", "a", "r6", "r7", "a"},
+			want:  []uint64{5, 6, 7, 6},
+			next:  7,
+		},
+		{
+			name:  "重复归还与归还未分配的nonce忽略",
+			repo:  &fakeTxRepo{},
+			steps: []string{"a", "a", "r5", "r5", "r9", "a", "a"},
+			want:  []uint64{5, 6, 5, 7},
+			next:  8,
+		},
+		{
+			name: "重新同步后保留仍需填补的空缺",
+			repo: &fakeTxRepo{},
+			// 5、7已发送，7因空缺排队：链上pending nonce为6，交易表中待上链的最大nonce为7
+			steps: []string{"a", "a", "a", "r6", "s", "a", "a"},
+			want:  []uint64{5, 6, 7, 6, 8},
+			next:  9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t, &fakeBackend{pendingNonce: 5}, tt.repo)
+			var got []uint64
+			for _, step := range tt.steps {
+				switch {
+				case step == "a":
+					nonce, err := m.allocateNonce(context.Background())
+					if err != nil {
+						t.Fatal(err)
+					}
+					got = append(got, nonce)
+				case step == "s":
+					m.backend.(*fakeBackend).pendingNonce = 6
+					tt.repo.maxPending = &[]uint64{7}[0]
+					m.resyncNonce()
+				default:
+					var nonce uint64
+					for _, c := range step[1:] {
+						nonce = nonce*10 + uint64(c-'0')
+					}
+					m.releaseNonce(nonce)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allocated %v, want %v", got, tt.want)
+			}
+			if m.nextNonce != tt.next {
+				t.Errorf("nextNonce = %d, want %d", m.nextNonce, tt.next)
+			}
+		})
+	}
+}
+
+func TestSendAlreadyKnown(t *testing.T) {
+	dropped, pending := models.TransactionStatusDropped, models.TransactionStatusPending
+	tests := []struct {
+		name        string
+		sendErrs    []error
+		createErr   error
+		wantErr     bool
+		wantSends   int
+		wantNonce   uint64
+		wantRecords []models.TransactionStatus // 交易表中各记录的最终状态
+	}{
+		{"直接成功", nil, nil, false, 1, 5, []models.TransactionStatus{pending}},
+		{"同一交易已在交易池中视为成功，不换nonce重发", []error{errors.New("already known")}, nil, false, 1, 5, []models.TransactionStatus{pending}},
+		{"nonce冲突时重新同步后换nonce重发", []error{errors.New("nonce too low")}, nil, false, 2, 6, []models.TransactionStatus{dropped, pending}},
+		{"其他错误归还nonce", []error{errors.New("insufficient funds")}, nil, true, 1, 0, []models.TransactionStatus{dropped}},
+		{"记录交易失败时不广播", nil, errors.New("db down"), true, 0, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &fakeBackend{pendingNonce: 5, sendErrs: tt.sendErrs}
+			repo := &fakeTxRepo{createErr: tt.createErr}
+			m := newTestManager(t, backend, repo)
+			record, err := m.Send(context.Background(), TxRequest{Purpose: "test", To: common.HexToAddress("0x1")})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(backend.sent) != tt.wantSends {
+				t.Errorf("sent %d transactions, want %d", len(backend.sent), tt.wantSends)
+			}
+			var statuses []models.TransactionStatus
+			for i, created := range repo.created {
+				statuses = append(statuses, created.Status)
+				if i < len(backend.sent) && created.Hash != backend.sent[i].Hash().Hex() {
+					t.Errorf("record %d hash = %s, want sent hash %s", i, created.Hash, backend.sent[i].Hash().Hex())
+				}
+			}
+			if !reflect.DeepEqual(statuses, tt.wantRecords) {
+				t.Errorf("record statuses = %v, want %v", statuses, tt.wantRecords)
+			}
+			if err != nil {
+				// 归还的nonce被下一笔交易复用
+				if nonce, _ := m.allocateNonce(context.Background()); nonce != 5 {
+					t.Errorf("nonce after failed send = %d, want 5", nonce)
+				}
+				return
+			}
+			if record.Nonce != tt.wantNonce || record.Hash != backend.sent[len(backend.sent)-1].Hash().Hex() {
+				t.Errorf("record nonce=%d hash=%s, want nonce %d and last sent hash", record.Nonce, record.Hash, tt.wantNonce)
+			}
+		})
+	}
+}
+
+func TestBumpFee(t *testing.T) {
+	tests := []struct {
+		fee     int64
+		percent int64
+		want    int64
+	}{
+		{100, 10, 110},
+		{101, 10, 112}, // 向上取整
+		{1_000_000_000, 12, 1_120_000_000},
+		{0, 10, 0},
+	}
+	for _, tt := range tests {
+		if got := bumpFee(big.NewInt(tt.fee), tt.percent); got.Int64() != tt.want {
+			t.Errorf("bumpFee(%d, %d) = %s, want %d", tt.fee, tt.percent, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Package keeper 拍卖结算守护
+//
+// 合约不会自动结束到期拍卖，NFT和资金会一直锁在合约中。Keeper定期扫描已过结束时间
+// 但尚未同步EndAuction事件的拍卖，确认链上ended为false后以后端签名地址调用endAuction，
+// 交易上链后由EndAuction监听器完成数据库状态更新。
+package keeper
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+)
+
+// AuctionKeeper 到期拍卖结算守护
+type AuctionKeeper struct {
+	contract    *blockchain.AuctionContract
+	chainID     uint64 // 合约所在链
+	contractHex string // 合约地址，只处理该合约部署上的拍卖
+	auctionRepo repository.AuctionRepository
+	txRepo      repository.TransactionRepository
+	cfg         config.KeeperConfig
+
+	mu       sync.Mutex
+	inflight map[uint64]string // 拍卖ID → 正在等待上链的endAuction交易哈希
+	wg       sync.WaitGroup
+}
+
+// NewAuctionKeeper 创建结算守护（每个拍卖合约部署一个，同一链的部署共用交易管理器）
+func NewAuctionKeeper(contract *blockchain.AuctionContract, cfg config.KeeperConfig) *AuctionKeeper {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.MaxInflight <= 0 {
+		cfg.MaxInflight = 10
+	}
+	return &AuctionKeeper{
+		contract:    contract,
+		chainID:     contract.ChainID().Uint64(),
+		contractHex: contract.Address().Hex(),
+		auctionRepo: repository.NewAuctionRepository(),
+		txRepo:      repository.NewTransactionRepository(),
+		cfg:         cfg,
+		inflight:    make(map[uint64]string),
+	}
+}
+
+// Start 启动结算守护（阻塞，直到上下文取消）
+func (k *AuctionKeeper) Start(ctx context.Context) error {
+	log.Info().Uint64("chain_id", k.chainID).Str("contract", k.contractHex).Str("sender", k.contract.Sender().Hex()).Dur("interval", k.cfg.Interval).Msg("启动拍卖结算守护")
+
+	ticker := time.NewTicker(k.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		k.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			k.wg.Wait()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce 扫描一批到期未结算的拍卖并提交endAuction
+func (k *AuctionKeeper) runOnce(ctx context.Context) {
+	auctions, err := k.auctionRepo.GetUnsettledExpired(k.chainID, k.contractHex, time.Now(), k.cfg.BatchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("查询待结束拍卖失败")
+		return
+	}
+	if len(auctions) == 0 {
+		return
+	}
+
+	// 以链上最新区块时间判断是否到期，避免本地时钟偏差导致交易回滚
+	head, err := k.contract.HeaderByNumber(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("获取最新区块失败")
+		return
+	}
+
+	for _, auction := range auctions {
+		if ctx.Err() != nil {
+			return
+		}
+		if !k.reserve(auction.ID) {
+			continue
+		}
+		if err := k.process(ctx, auction, head.Time); err != nil {
+			log.Error().Err(err).Uint64("auction_id", auction.ID).Msg("结束拍卖失败")
+			k.release(auction.ID)
+		}
+	}
+}
+
+// process 处理单个拍卖：恢复已有交易、校验链上状态、提交endAuction
+func (k *AuctionKeeper) process(ctx context.Context, auction *models.Auction, blockTime uint64) error {
+	// 1. 之前已提交但未确认的交易（如服务重启），继续跟踪
+	pending, err := k.txRepo.GetPendingByAuction(blockchain.PurposeEndAuction, auction.Key())
+	if err != nil {
+		return err
+	}
+	if pending != nil {
+		k.track(ctx, auction.ID, pending)
+		return nil
+	}
+
+	// 2. 校验链上状态
+	onchain, err := k.contract.GetAuction(ctx, new(big.Int).SetUint64(auction.ID))
+	if err != nil {
+		return err
+	}
+	if onchain.Ended {
+		// 链上已结束，等待EndAuction监听器同步
+		log.Info().Uint64("auction_id", auction.ID).Msg("拍卖链上已结束，等待事件同步")
+		k.release(auction.ID)
+		return nil
+	}
+	if onchain.EndTime().Cmp(new(big.Int).SetUint64(blockTime)) > 0 {
+		// 链上尚未到期
+		k.release(auction.ID)
+		return nil
+	}
+
+	// 3. 提交endAuction
+	record, err := k.contract.EndAuction(ctx, new(big.Int).SetUint64(auction.ID))
+	if err != nil {
+		return err
+	}
+	log.Info().Uint64("auction_id", auction.ID).Str("hash", record.Hash).Msg("已提交结束拍卖交易")
+	k.track(ctx, auction.ID, record)
+	return nil
+}
+
+// track 后台等待交易上链，结束后释放拍卖
+func (k *AuctionKeeper) track(ctx context.Context, auctionID uint64, record *models.Transaction) {
+	k.mu.Lock()
+	k.inflight[auctionID] = record.Hash
+	k.mu.Unlock()
+
+	k.wg.Add(1)
+	go func() {
+		defer k.wg.Done()
+		defer k.release(auctionID)
+
+		receipt, err := k.contract.WaitMined(ctx, record)
+		if err != nil {
+			log.Error().Err(err).Uint64("auction_id", auctionID).Str("hash", record.Hash).Msg("等待结束拍卖交易失败")
+			return
+		}
+		log.Info().Uint64("auction_id", auctionID).Str("hash", record.Hash).Uint64("status", receipt.Status).Msg("结束拍卖交易已上链")
+	}()
+}
+
+// reserve 占用拍卖，避免同一拍卖重复提交；超过并发上限时返回false
+func (k *AuctionKeeper) reserve(auctionID uint64) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.inflight[auctionID]; ok {
+		return false
+	}
+	if len(k.inflight) >= k.cfg.MaxInflight {
+		return false
+	}
+	k.inflight[auctionID] = ""
+	return true
+}
+
+// release 释放拍卖
+func (k *AuctionKeeper) release(auctionID uint64) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.inflight, auctionID)
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// AdminOperationType 合约管理操作类型
+type AdminOperationType string
+
+const (
+	AdminOperationSetPriceFeed AdminOperationType = "setPriceETHFeed"  // 设置币种价格预言机
+	AdminOperationUpgrade      AdminOperationType = "upgradeToAndCall" // 升级实现合约
+)
+
+// AdminOperationStatus 合约管理操作状态
+type AdminOperationStatus string
+
+const (
+	AdminOperationProposed   AdminOperationStatus = "proposed"   // 已提议，等待审批
+	AdminOperationApproved   AdminOperationStatus = "approved"   // 审批人数已满足，等待提交
+	AdminOperationRejected   AdminOperationStatus = "rejected"   // 已驳回
+	AdminOperationSubmitting AdminOperationStatus = "submitting" // 正在发送交易（尚未记录交易哈希）
+	AdminOperationSubmitted  AdminOperationStatus = "submitted"  // 交易已发送，等待上链（等待回执超时时保持此状态继续跟踪）
+	AdminOperationExecuted   AdminOperationStatus = "executed"   // 交易上链且执行成功
+	AdminOperationFailed     AdminOperationStatus = "failed"     // 交易发送或执行失败
+)
+
+// AdminOperationParams 管理操作参数
+type AdminOperationParams struct {
+	Token          string `json:"token,omitempty"`          // setPriceETHFeed：币种地址（零地址为ETH）
+	Feed           string `json:"feed,omitempty"`           // setPriceETHFeed：预言机地址
+	Implementation string `json:"implementation,omitempty"` // upgradeToAndCall：新实现合约地址
+	CallData       string `json:"call_data,omitempty"`      // upgradeToAndCall：升级后调用数据（hex）
+}
+
+// AdminOperation 需多名管理员审批后由后端提交的合约管理操作
+type AdminOperation struct {
+	ID      uint `gorm:"primarykey" json:"id"`
+	OptTime time.Time
+
+	ChainID           uint64                `gorm:"not null;default:0;index" json:"chain_id"`                         // 目标合约所在链ID
+	Type              AdminOperationType    `gorm:"type:varchar(32);not null" json:"type"`                            // 操作类型
+	Params            AdminOperationParams  `gorm:"type:text;serializer:json" json:"params"`                          // 操作参数
+	Status            AdminOperationStatus  `gorm:"type:varchar(16);not null;default:'proposed';index" json:"status"` // 状态
+	Proposer          string                `gorm:"type:varchar(64);not null" json:"proposer"`                        // 提议人
+	Approvals         []string              `gorm:"type:text;serializer:json" json:"approvals"`                       // 已审批的管理员（含提议人）
+	RequiredApprovals int                   `gorm:"not null" json:"required_approvals"`                               // 所需审批人数
+	Validation        string                `gorm:"type:text" json:"validation"`                                      // 最近一次链上校验结果
+	TxHash            string                `gorm:"type:varchar(66)" json:"tx_hash"`                                  // 提交的交易哈希
+	Error             string                `gorm:"type:text" json:"error"`                                           // 失败原因
+	SubmittedAt       *time.Time            `json:"submitted_at"`                                                     // 提交时间
+	ExecutedAt        *time.Time            `json:"executed_at"`                                                      // 上链时间
+	Events            []AdminOperationEvent `gorm:"foreignKey:OperationID" json:"events,omitempty"`                   // 审计记录
+}
+
+// AdminOperationEvent 管理操作审计记录
+type AdminOperationEvent struct {
+	ID      uint `gorm:"primarykey" json:"id"`
+	OptTime time.Time
+
+	OperationID uint   `gorm:"not null;index" json:"operation_id"`      // 关联操作ID
+	Action      string `gorm:"type:varchar(32);not null" json:"action"` // propose/approve/reject/submit/sent/timeout/executed/failed
+	Actor       string `gorm:"type:varchar(64)" json:"actor"`           // 操作人地址（系统动作为空）
+	Signature   string `gorm:"type:varchar(132)" json:"signature"`      // 操作人签名
+	Detail      string `gorm:"type:text" json:"detail"`                 // 详情（校验结果、交易哈希、失败原因等）
+}
@@ -1,35 +1,66 @@
 package models
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// AuctionStatus 拍卖状态
-type AuctionStatus string
+// Auction 拍卖信息，以链ID+拍卖合约地址+合约内拍卖ID为主键（各链、各合约部署的拍卖ID各自从0开始）
+type Auction struct {
+	ChainID         uint64 `gorm:"primaryKey;autoIncrement:false" json:"chain_id"`                 // 所在链ID
+	ContractAddress string `gorm:"primaryKey;type:varchar(64);default:''" json:"contract_address"` // 拍卖合约地址（重新部署后新旧合约并存）
+	ID              uint64 `gorm:"primaryKey;autoIncrement:false" json:"id"`                       // 合约内拍卖ID
+	OptTime         time.Time
 
-const (
-	AuctionStatusPending   AuctionStatus = "pending"   // 未开始
-	AuctionStatusActive    AuctionStatus = "active"    // 进行中
-	AuctionStatusEnded     AuctionStatus = "ended"     // 已结束
-	AuctionStatusCancelled AuctionStatus = "cancelled" // 已取消
-)
+	CreatorAddress    string        `gorm:"not null" json:"creator_address"`                                                                 // 拍卖创建者钱包地址
+	Duration          time.Duration `gorm:"not null" json:"duration"`                                                                        // 拍卖持续时间
+	StartTime         time.Time     `gorm:"not null" json:"start_time"`                                                                      // 拍卖开始时间
+	EndTime           time.Time     `gorm:"not null" json:"end_time"`                                                                        // 拍卖结束时间
+	StartPrice        uint64        `gorm:"not null" json:"start_price"`                                                                     // 起拍价（单位：ETH/USDT等）
+	StartTokenAddress string        `gorm:"not null" json:"start_token_address"`                                                             // 起始货币类型
+	Status            AuctionStatus `gorm:"not null;default:'pending'" json:"status"`                                                        // 拍卖状态
+	HighestBidder     string        `gorm:"not null" json:"highest_bidder"`                                                                  // 当前最高价出价者
+	HighestBid        uint64        `gorm:"not null;default:0" json:"highest_bid"`                                                           // 当前最高价
+	TokenAddress      string        `gorm:"not null" json:"token_address"`                                                                   // 拍卖货币类型
+	NFTTokenID        uint          `gorm:"not null;index" json:"nft_token_id"`                                                              // 关联NFT的ID
+	NFTContract       string        `gorm:"type:varchar(64);not null" json:"nft_contract"`                                                   // NFT合约地址
+	Settled           bool          `gorm:"not null;default:false;index" json:"settled"`                                                     // 链上是否已结束（已同步EndAuction事件）
+	CancelledAt       *time.Time    `json:"cancelled_at"`                                                                                    // 取消时间（取消事件或链上无人出价结束），未取消为空
+	NFT               NFT           `gorm:"foreignKey:ChainID,NFTContract,NFTTokenID;references:ChainID,ContractAddress,TokenID" json:"nft"` // 关联NFT（同一条链、同一NFT合约）
+}
 
-// Auction 拍卖信息
-type Auction struct {
-	ID      uint64 `gorm:"primarykey" json:"id"`
-	OptTime time.Time
+// Key 拍卖的唯一标识
+func (a *Auction) Key() AuctionKey {
+	return AuctionKey{ChainID: a.ChainID, Contract: a.ContractAddress, ID: a.ID}
+}
+
+// AuctionKey 拍卖在全部链、全部合约部署中的唯一标识
+type AuctionKey struct {
+	ChainID  uint64 `json:"chain_id"`
+	Contract string `json:"contract_address"` // 拍卖合约地址（校验和格式）
+	ID       uint64 `json:"id"`
+}
+
+// String 格式为"链ID:合约地址:拍卖ID"（Redis热度排行的member）
+func (k AuctionKey) String() string {
+	return strconv.FormatUint(k.ChainID, 10) + ":" + k.Contract + ":" + strconv.FormatUint(k.ID, 10)
+}
 
-	CreatorAddress    string        `gorm:"not null" json:"creator_address"`                     // 拍卖创建者钱包地址
-	Duration          time.Duration `gorm:"not null" json:"duration"`                            // 拍卖持续时间
-	StartTime         time.Time     `gorm:"not null" json:"start_time"`                          // 拍卖开始时间
-	EndTime           time.Time     `gorm:"not null" json:"end_time"`                            // 拍卖结束时间
-	StartPrice        uint64        `gorm:"not null" json:"start_price"`                         // 起拍价（单位：ETH/USDT等）
-	StartTokenAddress string        `gorm:"not null" json:"start_token_address"`                 // 起始货币类型
-	Status            AuctionStatus `gorm:"not null;default:'pending'" json:"status"`            // 拍卖状态
-	HighestBidder     string        `gorm:"not null" json:"highest_bidder"`                      // 当前最高价出价者
-	HighestBid        uint64        `gorm:"not null;default:0" json:"highest_bid"`               // 当前最高价
-	TokenAddress      string        `gorm:"not null" json:"token_address"`                       // 拍卖货币类型
-	NFTTokenID        uint          `gorm:"not null;index" json:"nft_token_id"`                  // 关联NFT的ID
-	NFTContract       string        `gorm:"not null" json:"nft_contract"`                        // NFT合约地址
-	NFT               NFT           `gorm:"foreignKey:NFTTokenID;references:TokenID" json:"nft"` // 关联NFT
+// ParseAuctionKey 解析String生成的拍卖标识
+func ParseAuctionKey(s string) (AuctionKey, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return AuctionKey{}, fmt.Errorf("拍卖标识%q格式错误", s)
+	}
+	chainID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return AuctionKey{}, fmt.Errorf("拍卖标识%q格式错误: %w", s, err)
+	}
+	auctionID, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return AuctionKey{}, fmt.Errorf("拍卖标识%q格式错误: %w", s, err)
+	}
+	return AuctionKey{ChainID: chainID, Contract: parts[1], ID: auctionID}, nil
 }
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// AuctionResult 拍卖成交结果（由EndAuction事件生成，每个拍卖至多一条）
+type AuctionResult struct {
+	ID      uint `gorm:"primarykey" json:"id"`
+	OptTime time.Time
+
+	ChainID         uint64  `gorm:"not null;default:0;uniqueIndex:idx_result_auction,priority:1" json:"chain_id"`                                // 所在链ID
+	ContractAddress string  `gorm:"type:varchar(64);not null;default:'';uniqueIndex:idx_result_auction,priority:2" json:"contract_address"`      // 拍卖合约地址
+	AuctionID       uint64  `gorm:"not null;uniqueIndex:idx_result_auction,priority:3" json:"auction_id"`                                        // 拍卖ID
+	Auction         Auction `gorm:"foreignKey:ChainID,ContractAddress,AuctionID;references:ChainID,ContractAddress,ID" json:"auction,omitempty"` // 关联拍卖
+
+	SellerAddress string  `gorm:"type:varchar(64);not null;index" json:"seller_address"` // 卖家（拍卖创建者）地址
+	WinnerAddress string  `gorm:"type:varchar(64);not null;index" json:"winner_address"` // 获胜者地址
+	FinalPrice    uint64  `gorm:"not null" json:"final_price"`                           // 成交价（事件Amount）
+	TokenAddress  string  `gorm:"type:varchar(64);not null" json:"token_address"`        // 成交货币类型（零地址为ETH）
+	USDValue      *string `gorm:"type:decimal(65,0)" json:"usd_value"`                   // 成交时的美元价值（合约calculateValue，精度同预言机），节点不支持历史状态查询时为空
+	WinningBidID  *uint   `json:"winning_bid_id"`                                        // 获胜出价记录ID，未同步到对应出价时为空
+
+	SettlementTxHash    string    `gorm:"type:varchar(66);not null" json:"settlement_tx_hash"` // 结算（EndAuction）交易哈希
+	SettlementBlock     uint64    `gorm:"not null" json:"settlement_block"`                    // 结算区块号
+	SettledAt           time.Time `json:"settled_at"`                                          // 结算时间（事件OptTime）
+	NFTTransferred      bool      `gorm:"not null;default:false" json:"nft_transferred"`       // 结算交易中是否确认了NFT转给获胜者的Transfer事件
+	NFTTransferLogIndex *uint     `json:"nft_transfer_log_index"`                              // NFT转移Transfer事件的日志索引
+}
+
+// AuctionKey 成交结果所属拍卖的唯一标识
+func (r *AuctionResult) AuctionKey() AuctionKey {
+	return AuctionKey{ChainID: r.ChainID, Contract: r.ContractAddress, ID: r.AuctionID}
+}
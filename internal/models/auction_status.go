@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+)
+
+// AuctionStatus 拍卖状态
+type AuctionStatus string
+
+const (
+	AuctionStatusPending   AuctionStatus = "pending"   // 未开始
+	AuctionStatusActive    AuctionStatus = "active"    // 进行中
+	AuctionStatusEnded     AuctionStatus = "ended"     // 已结束
+	AuctionStatusCancelled AuctionStatus = "cancelled" // 已取消
+)
+
+// auctionTransitions 允许的状态迁移：pending→active→ended/cancelled，已取消为终态；
+// 事件可能晚于时间检查到达，因此未开始的拍卖可直接结束或取消，本地按时间标记为已结束、链上确认无人出价的拍卖可转为已取消
+var auctionTransitions = map[AuctionStatus][]AuctionStatus{
+	AuctionStatusPending: {AuctionStatusActive, AuctionStatusEnded, AuctionStatusCancelled},
+	AuctionStatusActive:  {AuctionStatusEnded, AuctionStatusCancelled},
+	AuctionStatusEnded:   {AuctionStatusCancelled},
+}
+
+// CanTransitionTo 状态s能否迁移到to
+func (s AuctionStatus) CanTransitionTo(to AuctionStatus) bool {
+	for _, next := range auctionTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AuctionStatusesBefore 可以迁移到to的全部状态
+func AuctionStatusesBefore(to AuctionStatus) []AuctionStatus {
+	var from []AuctionStatus
+	for status := range auctionTransitions {
+		if status.CanTransitionTo(to) {
+			from = append(from, status)
+		}
+	}
+	return from
+}
+
+// AuctionStatusCause 拍卖状态变更原因
+type AuctionStatusCause string
+
+const (
+	AuctionCauseStarted     AuctionStatusCause = "started"      // 到达开始时间
+	AuctionCauseExpired     AuctionStatusCause = "expired"      // 到达结束时间（链上尚未结束）
+	AuctionCauseEndEvent    AuctionStatusCause = "end_event"    // EndAuction事件
+	AuctionCauseCancelEvent AuctionStatusCause = "cancel_event" // CancelAuction事件
+	AuctionCauseNoBidEnded  AuctionStatusCause = "no_bid_ended" // 链上无人出价结束（EndAuction获胜者为零地址或auctions()视图）
+)
+
+// AuctionTransition 一次拍卖状态迁移
+type AuctionTransition struct {
+	To          AuctionStatus
+	Cause       AuctionStatusCause
+	BlockNumber uint64    // 触发迁移的事件所在区块，按时间迁移时为0
+	TxHash      string    // 触发迁移的交易哈希，按时间迁移时为空
+	At          time.Time // 迁移发生时间（事件时间或检查时间）
+}
+
+// AuctionStatusHistory 拍卖状态变更历史
+type AuctionStatusHistory struct {
+	ID      uint `gorm:"primarykey" json:"id"`
+	OptTime time.Time
+
+	ChainID         uint64             `gorm:"not null;default:0;index:idx_status_history_auction,priority:1" json:"chain_id"`                // 所在链ID
+	ContractAddress string             `gorm:"type:varchar(64);not null;index:idx_status_history_auction,priority:2" json:"contract_address"` // 拍卖合约地址
+	AuctionID       uint64             `gorm:"not null;index:idx_status_history_auction,priority:3" json:"auction_id"`                        // 拍卖ID
+	FromStatus      AuctionStatus      `gorm:"type:varchar(16);not null" json:"from_status"`                                                  // 迁移前状态
+	ToStatus        AuctionStatus      `gorm:"type:varchar(16);not null" json:"to_status"`                                                    // 迁移后状态
+	Cause           AuctionStatusCause `gorm:"type:varchar(32);not null" json:"cause"`                                                        // 变更原因
+	BlockNumber     uint64             `gorm:"default:0" json:"block_number"`                                                                 // 事件所在区块
+	TxHash          string             `gorm:"type:varchar(66)" json:"tx_hash"`                                                               // 事件交易哈希
+}
+
+// TableName 状态历史表名
+func (AuctionStatusHistory) TableName() string {
+	return "auction_status_history"
+}
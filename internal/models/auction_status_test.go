@@ -0,0 +1,66 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAuctionStatusCanTransitionTo(t *testing.T) {
+	const (
+		pending   = AuctionStatusPending
+		active    = AuctionStatusActive
+		ended     = AuctionStatusEnded
+		cancelled = AuctionStatusCancelled
+	)
+	tests := []struct {
+		from, to AuctionStatus
+		want     bool
+	}{
+		{pending, pending, false},
+		{pending, active, true},
+		{pending, ended, true},
+		{pending, cancelled, true},
+		{active, pending, false},
+		{active, active, false},
+		{active, ended, true},
+		{active, cancelled, true},
+		{ended, pending, false},
+		{ended, active, false},
+		{ended, ended, false},
+		{ended, cancelled, true},
+		{cancelled, pending, false},
+		{cancelled, active, false},
+		{cancelled, ended, false},
+		{cancelled, cancelled, false},
+		{"unknown", active, false},
+		{active, "unknown", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v，期望 %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuctionStatusesBefore(t *testing.T) {
+	tests := []struct {
+		to   AuctionStatus
+		want []AuctionStatus
+	}{
+		{AuctionStatusPending, nil},
+		{AuctionStatusActive, []AuctionStatus{AuctionStatusPending}},
+		{AuctionStatusEnded, []AuctionStatus{AuctionStatusActive, AuctionStatusPending}},
+		{AuctionStatusCancelled, []AuctionStatus{AuctionStatusActive, AuctionStatusEnded, AuctionStatusPending}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.to), func(t *testing.T) {
+			got := AuctionStatusesBefore(tt.to)
+			slices.Sort(got) // 遍历map的顺序不固定
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AuctionStatusesBefore(%s) = %v，期望 %v", tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -9,10 +9,21 @@ type Bid struct {
 	ID      uint `gorm:"primarykey"`
 	OptTime time.Time
 
-	BidderAddress string  `gorm:"not null" json:"bidder_address"`   // 竞拍者钱包地址
-	Amount        uint64  `gorm:"not null" json:"amount"`           // 竞拍金额
-	TokenAddress  string  `gorm:"not null" json:"token_address"`    // 竞拍的代币类型
-	IsWinning     bool    `gorm:"default:false" json:"is_winning"`  // 是否为最高价，默认为false，结束后标记为true
-	AuctionID     uint64  `gorm:"not null;index" json:"auction_id"` // 关联拍卖ID
-	Auction       Auction `gorm:"foreignKey:AuctionID" json:"auction"`
+	BidderAddress   string  `gorm:"not null" json:"bidder_address"`                                                                // 竞拍者钱包地址
+	Amount          uint64  `gorm:"not null" json:"amount"`                                                                        // 竞拍金额
+	TokenAddress    string  `gorm:"not null" json:"token_address"`                                                                 // 竞拍的代币类型
+	IsWinning       bool    `gorm:"default:false" json:"is_winning"`                                                               // 是否为获胜出价（结算时按EndAuction事件的获胜者与成交价标记，每个拍卖仅一条）
+	ChainID         uint64  `gorm:"not null;default:0;index:idx_bid_auction,priority:1" json:"chain_id"`                           // 所在链ID
+	ContractAddress string  `gorm:"type:varchar(64);not null;default:'';index:idx_bid_auction,priority:2" json:"contract_address"` // 拍卖合约地址
+	AuctionID       uint64  `gorm:"not null;index:idx_bid_auction,priority:3" json:"auction_id"`                                   // 关联拍卖ID
+	Auction         Auction `gorm:"foreignKey:ChainID,ContractAddress,AuctionID;references:ChainID,ContractAddress,ID" json:"auction"`
+
+	// 出价事件在链上的位置，用于重复同步（回填、重启后重放）时去重；历史数据为空
+	TxHash   *string `gorm:"type:varchar(66);uniqueIndex:idx_bid_log" json:"tx_hash"`
+	LogIndex *uint   `gorm:"uniqueIndex:idx_bid_log" json:"log_index"`
+}
+
+// AuctionKey 出价所属拍卖的唯一标识
+func (b *Bid) AuctionKey() AuctionKey {
+	return AuctionKey{ChainID: b.ChainID, Contract: b.ContractAddress, ID: b.AuctionID}
 }
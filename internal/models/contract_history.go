@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// ContractEventType 合约管理事件类型
+type ContractEventType string
+
+const (
+	ContractEventUpgraded    ContractEventType = "upgraded"    // 合约升级（Upgraded）
+	ContractEventInitialized ContractEventType = "initialized" // 合约初始化（Initialized）
+)
+
+// ContractHistory 拍卖合约升级/初始化历史
+type ContractHistory struct {
+	ID      uint `gorm:"primarykey" json:"id"`
+	OptTime time.Time
+
+	ChainID          uint64            `gorm:"not null;default:0;uniqueIndex:idx_contract_history_log,priority:1" json:"chain_id"`       // 所在链ID
+	ContractAddress  string            `gorm:"type:varchar(64);not null;index" json:"contract_address"`                                  // 代理合约地址
+	EventType        ContractEventType `gorm:"type:varchar(32);not null" json:"event_type"`                                              // 事件类型
+	Implementation   string            `gorm:"type:varchar(64)" json:"implementation"`                                                   // 新实现合约地址（Upgraded）
+	Version          uint64            `gorm:"default:0" json:"version"`                                                                 // 初始化版本（Initialized）
+	Admin            string            `gorm:"type:varchar(64)" json:"admin"`                                                            // 事件所在区块的管理员地址快照
+	InterfaceVersion string            `gorm:"type:varchar(32)" json:"interface_version"`                                                // UPGRADE_INTERFACE_VERSION快照
+	BlockNumber      uint64            `gorm:"not null;index" json:"block_number"`                                                       // 区块号
+	BlockTime        time.Time         `json:"block_time"`                                                                               // 区块时间
+	TxHash           string            `gorm:"type:varchar(66);not null;uniqueIndex:idx_contract_history_log,priority:2" json:"tx_hash"` // 交易哈希
+	LogIndex         uint              `gorm:"not null;uniqueIndex:idx_contract_history_log,priority:3" json:"log_index"`                // 日志索引
+}
@@ -9,10 +9,17 @@ type NFT struct {
 	ID      uint `gorm:"primarykey"`
 	OptTime time.Time
 
-	TokenID         uint   `gorm:"type:varchar(64);uniqueIndex;not null" json:"token_id"` // 区块链上的NFT TokenID
-	ContractAddress string `gorm:"type:varchar(64);not null" json:"contract_address"`     // 合约地址，长度限制64字符哈希
-	OwnerAddress    string `gorm:"type:varchar(64);not null" json:"owner_address"`        // 钱包地址
-	Name            string `gorm:"type:varchar(255);not null" json:"name"`                // NFT名称
-	Description     string `gorm:"type:text" json:"description"`                          // NFT描述
-	ImageURL        string `gorm:"type:varchar(512)" json:"image_url"`                    // NFT图片链接
+	ChainID         uint64 `gorm:"not null;default:0;uniqueIndex:idx_nft_chain_token,priority:1" json:"chain_id"`                // 所在链ID
+	TokenID         uint   `gorm:"type:varchar(64);not null;uniqueIndex:idx_nft_chain_token,priority:3" json:"token_id"`         // 区块链上的NFT TokenID
+	ContractAddress string `gorm:"type:varchar(64);not null;uniqueIndex:idx_nft_chain_token,priority:2" json:"contract_address"` // 合约地址，长度限制64字符哈希（不同NFT合约的TokenID可能重复）
+	OwnerAddress    string `gorm:"type:varchar(64);not null" json:"owner_address"`                                               // 钱包地址
+	Name            string `gorm:"type:varchar(255);not null" json:"name"`                                                       // NFT名称
+	Description     string `gorm:"type:text" json:"description"`                                                                 // NFT描述
+	ImageURL        string `gorm:"type:varchar(512)" json:"image_url"`                                                           // NFT图片链接
+
+	ValidationWarnings []string `gorm:"type:text;serializer:json" json:"validation_warnings"` // 元数据校验警告
+
+	MetadataPending  bool       `gorm:"not null;default:false;index" json:"metadata_pending"` // 元数据上游暂时不可用，等待重新获取
+	MetadataAttempts int        `gorm:"not null;default:0" json:"-"`                          // 已重新获取元数据的次数
+	MetadataRetryAt  *time.Time `json:"-"`                                                    // 下次重新获取元数据的时间
 }
@@ -0,0 +1,12 @@
+package models
+
+import (
+	"time"
+)
+
+// SyncProgress 链上数据同步进度（如历史区块回填已完成到的区块）
+type SyncProgress struct {
+	Name      string    `gorm:"type:varchar(64);primarykey" json:"name"` // 同步任务名
+	Block     uint64    `gorm:"not null" json:"block"`                   // 已完成的最高区块
+	UpdatedAt time.Time `json:"updated_at"`
+}
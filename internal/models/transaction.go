@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// TransactionStatus 后端发送交易的状态
+type TransactionStatus string
+
+const (
+	TransactionStatusPending   TransactionStatus = "pending"   // 已发送，等待上链
+	TransactionStatusConfirmed TransactionStatus = "confirmed" // 已上链且执行成功
+	TransactionStatusFailed    TransactionStatus = "failed"    // 已上链但执行失败
+	TransactionStatusTimeout   TransactionStatus = "timeout"   // 等待回执超时（仍可能上链）
+	TransactionStatusDropped   TransactionStatus = "dropped"   // 已记录但广播失败（交易未发出）
+)
+
+// Transaction 后端签名发送的链上交易
+type Transaction struct {
+	ID      uint `gorm:"primarykey" json:"id"`
+	OptTime time.Time
+
+	ChainID        uint64            `gorm:"not null;default:0;index:idx_tx_from_nonce,priority:1" json:"chain_id"`            // 所在链ID（各链nonce独立）
+	Purpose        string            `gorm:"type:varchar(64);not null;index" json:"purpose"`                                   // 交易用途（createAuction/endAuction等）
+	AuctionID      uint64            `gorm:"default:0;index" json:"auction_id"`                                                // 关联拍卖ID（无关联时为0）
+	FromAddress    string            `gorm:"type:varchar(64);not null;index:idx_tx_from_nonce,priority:2" json:"from_address"` // 发送地址
+	ToAddress      string            `gorm:"type:varchar(64);not null" json:"to_address"`                                      // 目标合约地址
+	Nonce          uint64            `gorm:"not null;index:idx_tx_from_nonce,priority:3" json:"nonce"`                         // nonce
+	Hash           string            `gorm:"type:varchar(66);uniqueIndex" json:"hash"`                                         // 当前有效交易哈希（替换后更新）
+	Value          string            `gorm:"type:varchar(78);not null;default:'0'" json:"value"`                               // 转账金额（wei）
+	Data           string            `gorm:"type:text" json:"data"`                                                            // 调用数据（hex）
+	GasLimit       uint64            `gorm:"not null" json:"gas_limit"`                                                        // 燃气上限
+	GasTipCap      string            `gorm:"type:varchar(78)" json:"gas_tip_cap"`                                              // 小费上限（wei）
+	GasFeeCap      string            `gorm:"type:varchar(78)" json:"gas_fee_cap"`                                              // 燃气费上限（wei）
+	Replacements   int               `gorm:"not null;default:0" json:"replacements"`                                           // 加价替换次数
+	Status         TransactionStatus `gorm:"type:varchar(16);not null;default:'pending';index" json:"status"`                  // 交易状态
+	BlockNumber    uint64            `gorm:"default:0" json:"block_number"`                                                    // 上链区块号
+	GasUsed        uint64            `gorm:"default:0" json:"gas_used"`                                                        // 实际消耗燃气
+	GasPrice       string            `gorm:"type:varchar(78)" json:"gas_price"`                                                // 实际燃气单价（effectiveGasPrice，wei）
+	GasCost        string            `gorm:"type:decimal(65,0);default:0" json:"gas_cost"`                                     // 燃气花费（gasUsed*gasPrice，wei）
+	ReplacedHashes []string          `gorm:"type:text;serializer:json" json:"replaced_hashes"`                                 // 被加价替换的历史哈希
+	Error          string            `gorm:"type:text" json:"error"`                                                           // 错误信息
+	SubmittedAt    time.Time         `json:"submitted_at"`                                                                     // 首次发送时间
+	ConfirmedAt    *time.Time        `json:"confirmed_at"`                                                                     // 上链时间
+}
+
+// TransactionHash 交易被加价替换前使用过的哈希（按哈希等值查询交易）
+type TransactionHash struct {
+	ID            uint   `gorm:"primarykey" json:"id"`
+	TransactionID uint   `gorm:"not null;index" json:"transaction_id"`              // 所属交易记录ID
+	Hash          string `gorm:"type:varchar(66);not null;uniqueIndex" json:"hash"` // 被替换的交易哈希
+	CreatedAt     time.Time
+}
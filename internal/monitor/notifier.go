@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// 告警级别
+const (
+	LevelWarning  = "warning"
+	LevelCritical = "critical"
+	LevelResolved = "resolved" // 告警条件已恢复
+)
+
+// Alert 告警内容
+type Alert struct {
+	Key     string    `json:"key"`      // 告警类型，用于去重
+	Level   string    `json:"level"`    // 告警级别
+	Title   string    `json:"title"`    // 标题
+	Message string    `json:"message"`  // 详情
+	ChainID uint64    `json:"chain_id"` // 所在链ID
+	Address string    `json:"address"`  // 相关地址
+	Time    time.Time `json:"time"`     // 触发时间
+}
+
+// Notifier 告警通知渠道
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// LogNotifier 将告警写入日志
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(_ context.Context, alert Alert) error {
+	event := log.Warn()
+	if alert.Level == LevelCritical {
+		event = log.Error()
+	} else if alert.Level == LevelResolved {
+		event = log.Info()
+	}
+	event.Str("key", alert.Key).Str("level", alert.Level).Str("address", alert.Address).Msg(alert.Title + "：" + alert.Message)
+	return nil
+}
+
+// WebhookNotifier 以JSON POST告警到Webhook（如企业微信/钉钉/Slack中转服务）
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier 创建Webhook通知渠道
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook返回状态码%d", resp.StatusCode)
+	}
+	return nil
+}
+
+// MultiNotifier 依次发送到多个渠道，单个渠道失败不影响其他渠道
+type MultiNotifier []Notifier
+
+func (m MultiNotifier) Notify(ctx context.Context, alert Alert) error {
+	var firstErr error
+	for _, n := range m {
+		if err := n.Notify(ctx, alert); err != nil {
+			log.Warn().Err(err).Str("key", alert.Key).Msg("发送告警失败")
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// NotifierFromConfig 根据配置创建通知渠道：总是写日志，配置Webhook时同时推送
+func NotifierFromConfig(webhookURL string) Notifier {
+	notifiers := MultiNotifier{LogNotifier{}}
+	if webhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(webhookURL))
+	}
+	return notifiers
+}
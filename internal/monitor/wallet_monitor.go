@@ -0,0 +1,259 @@
+// Package monitor 后端热钱包监控
+//
+// 后端签名地址为CreateAuction、结算守护endAuction及管理操作支付燃气费。WalletMonitor定期检查
+// 其ETH余额、链上nonce与待上链nonce的差值以及交易表中最早的待上链交易，超过阈值时通过Notifier告警。
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+)
+
+// 告警类型
+const (
+	AlertLowBalance   = "low_balance"
+	AlertStalePending = "stale_pending"
+	AlertNonceGap     = "nonce_gap"
+)
+
+var (
+	// Wallet 默认链（首个初始化的链）的热钱包监控实例（InitWalletMonitor初始化）
+	Wallet *WalletMonitor
+
+	// wallets 各链的热钱包监控实例，按链ID索引
+	wallets = make(map[uint64]*WalletMonitor)
+)
+
+// Backend 监控依赖的链上接口（*ethclient.Client已实现）
+type Backend interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// WalletStatus 热钱包状态快照
+type WalletStatus struct {
+	ChainID         uint64     `json:"chain_id"`
+	Address         string     `json:"address"`
+	Balance         string     `json:"balance"`           // 余额（wei）
+	BalanceETH      float64    `json:"balance_eth"`       // 余额（ETH，仅用于展示）
+	MinBalanceETH   float64    `json:"min_balance_eth"`   // 告警阈值
+	ConfirmedNonce  uint64     `json:"confirmed_nonce"`   // 最新区块nonce
+	PendingNonce    uint64     `json:"pending_nonce"`     // 含交易池的nonce
+	NonceGap        uint64     `json:"nonce_gap"`         // 交易池中未上链的交易数
+	PendingTxs      int        `json:"pending_txs"`       // 交易表中待上链交易数
+	OldestPendingAt *time.Time `json:"oldest_pending_at"` // 最早待上链交易的发送时间
+	OldestPendingTx string     `json:"oldest_pending_tx"` // 最早待上链交易哈希
+	ActiveAlerts    []string   `json:"active_alerts"`     // 当前触发中的告警
+	CheckedAt       time.Time  `json:"checked_at"`        // 最近检查时间
+	LastError       string     `json:"last_error"`        // 最近一次检查错误
+}
+
+// WalletMonitor 热钱包监控
+type WalletMonitor struct {
+	backend  Backend
+	chainID  uint64
+	address  common.Address
+	txRepo   repository.TransactionRepository
+	notifier Notifier
+	cfg      config.MonitorConfig
+
+	mu     sync.RWMutex
+	status WalletStatus
+	fired  map[string]time.Time // 告警类型 → 最近发送时间
+}
+
+// NewWalletMonitor 创建热钱包监控，chainID为backend所连接的链
+func NewWalletMonitor(backend Backend, chainID uint64, address common.Address, notifier Notifier, cfg config.MonitorConfig) *WalletMonitor {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.AlertInterval <= 0 {
+		cfg.AlertInterval = 30 * time.Minute
+	}
+	return &WalletMonitor{
+		backend:  backend,
+		chainID:  chainID,
+		address:  address,
+		txRepo:   repository.NewTransactionRepository(),
+		notifier: notifier,
+		cfg:      cfg,
+		status:   WalletStatus{ChainID: chainID, Address: address.Hex(), MinBalanceETH: cfg.MinBalanceETH},
+		fired:    make(map[string]time.Time),
+	}
+}
+
+// InitWalletMonitor 初始化并登记一条链的热钱包监控实例，首个初始化的链作为默认链
+func InitWalletMonitor(backend Backend, chainID uint64, address common.Address, cfg config.MonitorConfig) *WalletMonitor {
+	m := NewWalletMonitor(backend, chainID, address, NotifierFromConfig(cfg.WebhookURL), cfg)
+	wallets[chainID] = m
+	if Wallet == nil || Wallet.chainID == chainID {
+		Wallet = m
+	}
+	return m
+}
+
+// WalletFor 指定链的热钱包监控实例，chainID为0时返回默认链，未初始化时返回nil
+func WalletFor(chainID uint64) *WalletMonitor {
+	if chainID == 0 {
+		return Wallet
+	}
+	return wallets[chainID]
+}
+
+// Start 启动监控（阻塞，直到上下文取消）
+func (m *WalletMonitor) Start(ctx context.Context) error {
+	log.Info().Uint64("chain_id", m.chainID).Str("address", m.address.Hex()).Dur("interval", m.cfg.Interval).Msg("启动热钱包监控")
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		m.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status 返回最近一次检查的状态快照
+func (m *WalletMonitor) Status() WalletStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	status := m.status
+	status.ActiveAlerts = make([]string, 0, len(m.fired))
+	for key := range m.fired {
+		status.ActiveAlerts = append(status.ActiveAlerts, key)
+	}
+	return status
+}
+
+// Check 执行一次检查并触发告警
+func (m *WalletMonitor) Check(ctx context.Context) {
+	status, err := m.collect(ctx)
+	if err != nil {
+		log.Error().Err(err).Uint64("chain_id", m.chainID).Str("address", m.address.Hex()).Msg("热钱包检查失败")
+		m.mu.Lock()
+		m.status.LastError = err.Error()
+		m.status.CheckedAt = time.Now()
+		m.mu.Unlock()
+		return
+	}
+
+	m.mu.Lock()
+	m.status = *status
+	m.mu.Unlock()
+
+	// 余额不足
+	minBalance := ethToWei(m.cfg.MinBalanceETH)
+	balance, _ := new(big.Int).SetString(status.Balance, 10)
+	m.evaluate(ctx, AlertLowBalance, minBalance.Sign() > 0 && balance.Cmp(minBalance) < 0, LevelCritical, "热钱包余额不足",
+		fmt.Sprintf("余额%.6f ETH，低于阈值%.6f ETH", status.BalanceETH, m.cfg.MinBalanceETH))
+
+	// 交易长时间未上链
+	stale := status.OldestPendingAt != nil && m.cfg.MaxPendingAge > 0 && time.Since(*status.OldestPendingAt) > m.cfg.MaxPendingAge
+	staleMsg := ""
+	if stale {
+		staleMsg = fmt.Sprintf("交易%s已等待%s未上链", status.OldestPendingTx, time.Since(*status.OldestPendingAt).Truncate(time.Second))
+	}
+	m.evaluate(ctx, AlertStalePending, stale, LevelWarning, "交易长时间未上链", staleMsg)
+
+	// 交易池积压
+	m.evaluate(ctx, AlertNonceGap, m.cfg.MaxNonceGap > 0 && status.NonceGap > m.cfg.MaxNonceGap, LevelWarning, "待上链交易积压",
+		fmt.Sprintf("待上链nonce数量%d，超过阈值%d", status.NonceGap, m.cfg.MaxNonceGap))
+}
+
+// collect 查询余额、nonce与待上链交易
+func (m *WalletMonitor) collect(ctx context.Context) (*WalletStatus, error) {
+	balance, err := m.backend.BalanceAt(ctx, m.address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询余额失败: %w", err)
+	}
+	confirmed, err := m.backend.NonceAt(ctx, m.address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询nonce失败: %w", err)
+	}
+	pending, err := m.backend.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		return nil, fmt.Errorf("查询待上链nonce失败: %w", err)
+	}
+	pendingTxs, err := m.txRepo.GetPendingByFrom(m.chainID, m.address.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("查询待上链交易失败: %w", err)
+	}
+
+	status := &WalletStatus{
+		ChainID:        m.chainID,
+		Address:        m.address.Hex(),
+		Balance:        balance.String(),
+		BalanceETH:     weiToETH(balance),
+		MinBalanceETH:  m.cfg.MinBalanceETH,
+		ConfirmedNonce: confirmed,
+		PendingNonce:   pending,
+		PendingTxs:     len(pendingTxs),
+		CheckedAt:      time.Now(),
+	}
+	if pending > confirmed {
+		status.NonceGap = pending - confirmed
+	}
+	for i := range pendingTxs {
+		tx := pendingTxs[i]
+		if status.OldestPendingAt == nil || tx.SubmittedAt.Before(*status.OldestPendingAt) {
+			submittedAt := tx.SubmittedAt
+			status.OldestPendingAt = &submittedAt
+			status.OldestPendingTx = tx.Hash
+		}
+	}
+	return status, nil
+}
+
+// evaluate 条件触发时发送告警（按AlertInterval去重），条件恢复时发送恢复通知
+func (m *WalletMonitor) evaluate(ctx context.Context, key string, triggered bool, level, title, message string) {
+	m.mu.Lock()
+	last, active := m.fired[key]
+	switch {
+	case triggered && (!active || time.Since(last) >= m.cfg.AlertInterval):
+		m.fired[key] = time.Now()
+	case !triggered && active:
+		delete(m.fired, key)
+		level, message = LevelResolved, "已恢复"
+	default:
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+
+	alert := Alert{Key: key, Level: level, Title: title, Message: message, ChainID: m.chainID, Address: m.address.Hex(), Time: time.Now()}
+	if err := m.notifier.Notify(ctx, alert); err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("告警通知失败")
+	}
+}
+
+// weiToETH wei转换为ETH（浮点，仅用于展示和阈值比较）
+func weiToETH(wei *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.Ether)).Float64()
+	return f
+}
+
+// ethToWei ETH转换为wei
+func ethToWei(eth float64) *big.Int {
+	if eth <= 0 || math.IsNaN(eth) || math.IsInf(eth, 0) {
+		return big.NewInt(0)
+	}
+	wei, _ := new(big.Float).Mul(big.NewFloat(eth), big.NewFloat(params.Ether)).Int(nil)
+	return wei
+}
@@ -1,8 +1,6 @@
 package redis
 
 import (
-	"strconv"
-
 	"github.com/redis/go-redis/v9"
 	"github.com/ydh2333/NFTAuction-project/internal/models"
 )
@@ -12,29 +10,42 @@ const (
 	KeyAuctionHotRank = "nft_auction:hot_rank" // 拍卖热度排行Key
 )
 
+// 按链筛选热门拍卖时每次从排行中读取的数量
+const hotRankScanSize = 50
+
 // IncrAuctionHot 增加拍卖热度（出价成功后调用，热度+1）
-// auctionID: 拍卖ID（uint64）
-func IncrAuctionHot(auctionID uint64) error {
+// key: 拍卖标识（链ID:拍卖ID）
+func IncrAuctionHot(key models.AuctionKey) error {
 	// ZINCRBY：有序集合中指定member的score+1
-	return rdb.ZIncrBy(ctx, KeyAuctionHotRank, 1, strconv.FormatUint(auctionID, 10)).Err()
+	return rdb.ZIncrBy(ctx, KeyAuctionHotRank, 1, key.String()).Err()
 }
 
-// GetTop5HotAuctions 获取Top5热门拍卖的拍卖ID（按热度降序，热度相同按最新出价时间降序）
-// 返回：拍卖ID切片（从高到低）、错误
-func GetTop5HotAuctions() ([]uint64, error) {
-	// ZREVRANGE：按score降序取前5个member（0-4），不返回score
-	res, err := rdb.ZRevRange(ctx, KeyAuctionHotRank, 0, 4).Result()
-	if err != nil {
-		return nil, err
-	}
-
-	// 字符串转uint64
-	auctionIDs := make([]uint64, 0, len(res))
-	for _, s := range res {
-		aid, _ := strconv.ParseUint(s, 10, 64)
-		auctionIDs = append(auctionIDs, aid)
+// GetTop5HotAuctions 获取Top5热门拍卖（按热度降序），chainID非0时只返回该链的拍卖
+// 返回：拍卖标识切片（从高到低）、错误
+func GetTop5HotAuctions(chainID uint64) ([]models.AuctionKey, error) {
+	const top = 5
+	keys := make([]models.AuctionKey, 0, top)
+	for start := int64(0); len(keys) < top; start += hotRankScanSize {
+		// ZREVRANGE：按score降序取一段member，不返回score
+		res, err := rdb.ZRevRange(ctx, KeyAuctionHotRank, start, start+hotRankScanSize-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range res {
+			key, err := models.ParseAuctionKey(s)
+			if err != nil || (chainID != 0 && key.ChainID != chainID) {
+				continue
+			}
+			keys = append(keys, key)
+			if len(keys) == top {
+				break
+			}
+		}
+		if len(res) < hotRankScanSize {
+			break
+		}
 	}
-	return auctionIDs, nil
+	return keys, nil
 }
 
 // InitHotRankFromDB 从MySQL初始化Redis热度排行（项目启动时调用）
@@ -46,24 +57,39 @@ func InitHotRankFromDB(bids []models.Bid) error {
 	}
 
 	// 2. 统计每个拍卖的出价次数（热度值）
-	hotMap := make(map[uint64]int64)
+	hotMap := make(map[models.AuctionKey]int64)
 	for _, bid := range bids {
-		hotMap[bid.AuctionID]++
+		hotMap[bid.AuctionKey()]++
 	}
 
 	// 3. 批量写入Redis（ZADD，批量操作提升性能）
 	zs := make([]redis.Z, 0, len(hotMap))
-	for aid, score := range hotMap {
+	for key, score := range hotMap {
 		zs = append(zs, redis.Z{
 			Score:  float64(score),
-			Member: strconv.FormatUint(aid, 10),
+			Member: key.String(),
 		})
 	}
+	if len(zs) == 0 {
+		return nil
+	}
 
 	return rdb.ZAdd(ctx, KeyAuctionHotRank, zs...).Err()
 }
 
 // DelAuctionHot 从热度排行中删除拍卖（拍卖结束/流拍后可选调用，按需）
-func DelAuctionHot(auctionID uint64) error {
-	return rdb.ZRem(ctx, KeyAuctionHotRank, strconv.FormatUint(auctionID, 10)).Err()
+func DelAuctionHot(key models.AuctionKey) error {
+	return rdb.ZRem(ctx, KeyAuctionHotRank, key.String()).Err()
+}
+
+// GetAuctionHot 查询拍卖热度，不在排行中时返回false
+func GetAuctionHot(key models.AuctionKey) (float64, bool, error) {
+	score, err := rdb.ZScore(ctx, KeyAuctionHotRank, key.String()).Result()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return score, true, nil
 }
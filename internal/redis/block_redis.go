@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyBlockHeaderPrefix 区块头缓存Key前缀，后接"链ID:区块号"
+const KeyBlockHeaderPrefix = "nft_auction:block_header:"
+
+// blockHeaderKey 区块头缓存Key
+func blockHeaderKey(chainID, number uint64) string {
+	return KeyBlockHeaderPrefix + strconv.FormatUint(chainID, 10) + ":" + strconv.FormatUint(number, 10)
+}
+
+// SetBlockHeader 缓存区块头（JSON），ttl为0表示不过期
+func SetBlockHeader(chainID, number uint64, data []byte, ttl time.Duration) error {
+	return rdb.Set(ctx, blockHeaderKey(chainID, number), data, ttl).Err()
+}
+
+// GetBlockHeader 查询缓存的区块头，未缓存时返回false
+func GetBlockHeader(chainID, number uint64) ([]byte, bool, error) {
+	data, err := rdb.Get(ctx, blockHeaderKey(chainID, number)).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// DelBlockHeader 删除缓存的区块头（链重组后失效）
+func DelBlockHeader(chainID, number uint64) error {
+	return rdb.Del(ctx, blockHeaderKey(chainID, number)).Err()
+}
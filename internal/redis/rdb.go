@@ -31,6 +31,11 @@ func Init(cfg *config.RedisConfig) {
 	log.Info().Str("addr", cfg.Addr).Int("db", cfg.DB).Msg("Redis初始化成功")
 }
 
+// Ready Redis是否已初始化（离线工具可能不连接Redis）
+func Ready() bool {
+	return rdb != nil
+}
+
 // 关闭Redis连接（优雅关闭用）
 func CloseRedis() {
 	if err := rdb.Close(); err != nil {
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AdminOperationRepository interface {
+	Create(op *models.AdminOperation, event *models.AdminOperationEvent) error
+	GetByID(id uint) (*models.AdminOperation, error)
+	List(chainID uint64, status models.AdminOperationStatus, pageParams utils.PageParams) ([]models.AdminOperation, int64, error)
+	GetByStatus(status models.AdminOperationStatus) ([]models.AdminOperation, error)
+	Transition(id uint, fn func(op *models.AdminOperation) (*models.AdminOperationEvent, error)) (*models.AdminOperation, error)
+}
+
+type adminOperationRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminOperationRepository() AdminOperationRepository {
+	return &adminOperationRepository{db: DB}
+}
+
+// Create 创建管理操作并写入提议审计记录
+func (r *adminOperationRepository) Create(op *models.AdminOperation, event *models.AdminOperationEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Events").Create(op).Error; err != nil {
+			return err
+		}
+		event.OperationID = op.ID
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		log.Error().Err(err).Str("type", string(op.Type)).Msg("创建管理操作失败")
+		return err
+	}
+	return nil
+}
+
+// GetByID 查询管理操作及审计记录
+func (r *adminOperationRepository) GetByID(id uint) (*models.AdminOperation, error) {
+	var op models.AdminOperation
+	if err := r.db.Preload("Events", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("id ASC")
+	}).First(&op, id).Error; err != nil {
+		log.Error().Err(err).Uint("operation_id", id).Msg("查询管理操作失败")
+		return nil, err
+	}
+	return &op, nil
+}
+
+// List 按创建倒序查询管理操作，chainID为0、status为空时不按其过滤
+func (r *adminOperationRepository) List(chainID uint64, status models.AdminOperationStatus, pageParams utils.PageParams) ([]models.AdminOperation, int64, error) {
+	var ops []models.AdminOperation
+	var total int64
+
+	query := r.db.Model(&models.AdminOperation{}).Scopes(byChain("chain_id", chainID))
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		log.Error().Err(err).Msg("统计管理操作失败")
+		return nil, 0, err
+	}
+	if err := query.Order("id DESC").Scopes(utils.Paginate(pageParams)).Find(&ops).Error; err != nil {
+		log.Error().Err(err).Msg("查询管理操作失败")
+		return nil, 0, err
+	}
+	return ops, total, nil
+}
+
+// GetByStatus 查询某状态下的全部管理操作（启动时恢复跟踪已发送的交易）
+func (r *adminOperationRepository) GetByStatus(status models.AdminOperationStatus) ([]models.AdminOperation, error) {
+	var ops []models.AdminOperation
+	if err := r.db.Where("status = ?", status).Order("id ASC").Find(&ops).Error; err != nil {
+		log.Error().Err(err).Str("status", string(status)).Msg("查询管理操作失败")
+		return nil, err
+	}
+	return ops, nil
+}
+
+// Transition 行锁内修改管理操作并写入审计记录，fn返回错误时回滚；避免并发审批互相覆盖
+func (r *adminOperationRepository) Transition(id uint, fn func(op *models.AdminOperation) (*models.AdminOperationEvent, error)) (*models.AdminOperation, error) {
+	var op models.AdminOperation
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&op, id).Error; err != nil {
+			return err
+		}
+		event, err := fn(&op)
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit("Events").Save(&op).Error; err != nil {
+			return err
+		}
+		if event == nil {
+			return nil
+		}
+		event.OperationID = op.ID
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
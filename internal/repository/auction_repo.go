@@ -9,17 +9,27 @@ import (
 	"github.com/ydh2333/NFTAuction-project/internal/models"
 	"github.com/ydh2333/NFTAuction-project/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AuctionRepository interface {
 	Create(auction *models.Auction) error
-	GetByID(id uint) (*models.Auction, error)
-	GetActiveAuctions() ([]*models.Auction, error)
-	UpdateStatus(id uint, status models.AuctionStatus) error
-	UpdateCurrentPrice(auctionID uint, HighestBid uint64, HighestBidder string, TokenAddress string) error
-	GetAuctionCount() (int64, error)
+	CreateBatch(auctions []*models.Auction) error
+	GetByID(key models.AuctionKey) (*models.Auction, error)
+	GetActiveAuctions(chainID uint64) ([]*models.Auction, error)
+	TransitionStatus(key models.AuctionKey, t models.AuctionTransition) (bool, error)
+	Cancel(key models.AuctionKey, t models.AuctionTransition) (bool, error)
+	GetPendingStarted(chainID uint64, contract string, now time.Time, limit int) ([]*models.Auction, error)
+	GetActiveExpired(chainID uint64, contract string, now time.Time, limit int) ([]*models.Auction, error)
+	UpdateCurrentPrice(key models.AuctionKey, HighestBid uint64, HighestBidder string, TokenAddress string) error
+	GetAuctionCount(chainID uint64) (int64, error)
 	SearchAuctions(params AuctionSearchParams, sortParams SortParams, pageParams utils.PageParams) ([]AuctionDetail, error)
-	GetAuctionsByIDs(auctionIDs []uint64) ([]AuctionDetail, error)
+	GetAuctionsByKeys(keys []models.AuctionKey) ([]AuctionDetail, error)
+	GetUnsettledExpired(chainID uint64, contract string, now time.Time, limit int) ([]*models.Auction, error)
+	MarkSettled(key models.AuctionKey) error
+	GetOpenByNFT(chainID uint64, nftContract string, tokenID uint) ([]*models.Auction, error)
+	GetExpiredWithoutBids(chainID uint64, contract string, now time.Time, after AuctionCursor, limit int) ([]*models.Auction, error)
+	GetCancelledByCreator(chainID uint64, creator string) ([]*models.Auction, error)
 }
 
 // auctionRepository 实现AuctionRepository
@@ -45,21 +55,34 @@ func (r *auctionRepository) Create(auction *models.Auction) error {
 	return nil
 }
 
-// GetByID 根据ID查询拍卖
-func (r *auctionRepository) GetByID(id uint) (*models.Auction, error) {
+// CreateBatch 批量创建拍卖记录，链ID+拍卖ID已存在的跳过（回填可重复执行）
+func (r *auctionRepository) CreateBatch(auctions []*models.Auction) error {
+	if len(auctions) == 0 {
+		return nil
+	}
+	if err := r.db.Omit("NFT").Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(auctions, batchSize).Error; err != nil {
+		log.Error().Err(err).Int("count", len(auctions)).Msg("批量创建拍卖记录失败")
+		return err
+	}
+	return nil
+}
+
+// GetByID 根据链ID与拍卖ID查询拍卖
+func (r *auctionRepository) GetByID(key models.AuctionKey) (*models.Auction, error) {
 	var auction models.Auction
-	if err := r.db.Preload("NFT").First(&auction, id).Error; err != nil {
-		log.Error().Err(err).Uint("auction_id", id).Msg("查询拍卖失败")
+	if err := r.db.Preload("NFT").Scopes(byAuctionKey(key)).First(&auction).Error; err != nil {
+		log.Error().Err(err).Str("auction", key.String()).Msg("查询拍卖失败")
 		return nil, err
 	}
 	return &auction, nil
 }
 
-// GetActiveAuctions 查询进行中的拍卖
-func (r *auctionRepository) GetActiveAuctions() ([]*models.Auction, error) {
+// GetActiveAuctions 查询进行中的拍卖，chainID为0时查询全部链
+func (r *auctionRepository) GetActiveAuctions(chainID uint64) ([]*models.Auction, error) {
 	var auctions []*models.Auction
 	now := time.Now()
 	if err := r.db.Preload("NFT").
+		Scopes(byChain("chain_id", chainID)).
 		Where("status = ?", models.AuctionStatusActive).
 		Where("start_time <= ?", now).
 		Where("end_time >= ?", now).
@@ -70,36 +93,186 @@ func (r *auctionRepository) GetActiveAuctions() ([]*models.Auction, error) {
 	return auctions, nil
 }
 
-// UpdateStatus 更新拍卖状态
-func (r *auctionRepository) UpdateStatus(id uint, status models.AuctionStatus) error {
-	if err := r.db.Model(&models.Auction{}).
-		Where("id = ?", id).
-		Update("status", status).Error; err != nil {
-		log.Error().Err(err).Uint("auction_id", id).Msg("更新拍卖状态失败")
-		return err
+// TransitionStatus 按状态机迁移拍卖状态并记录状态历史，当前状态不能迁移到t.To（含拍卖不存在）时不更新并返回false
+func (r *auctionRepository) TransitionStatus(key models.AuctionKey, t models.AuctionTransition) (bool, error) {
+	return r.transition(key, t, map[string]interface{}{"status": t.To})
+}
+
+// Cancel 取消拍卖：迁移到已取消状态并标记链上已结束，当前状态不允许取消时返回false
+func (r *auctionRepository) Cancel(key models.AuctionKey, t models.AuctionTransition) (bool, error) {
+	t.To = models.AuctionStatusCancelled
+	return r.transition(key, t, map[string]interface{}{
+		"status":       models.AuctionStatusCancelled,
+		"settled":      true,
+		"cancelled_at": t.At,
+	})
+}
+
+// transition 锁定拍卖后校验状态迁移，允许时更新fields并写入状态历史（同一事务）
+func (r *auctionRepository) transition(key models.AuctionKey, t models.AuctionTransition, fields map[string]interface{}) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.Auction
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("status").
+			Scopes(byAuctionKey(key)).
+			Take(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !current.Status.CanTransitionTo(t.To) {
+			return nil
+		}
+
+		if err := tx.Model(&models.Auction{}).Scopes(byAuctionKey(key)).Updates(fields).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.AuctionStatusHistory{
+			OptTime:         t.At,
+			ChainID:         key.ChainID,
+			ContractAddress: key.Contract,
+			AuctionID:       key.ID,
+			FromStatus:      current.Status,
+			ToStatus:        t.To,
+			Cause:           t.Cause,
+			BlockNumber:     t.BlockNumber,
+			TxHash:          t.TxHash,
+		}).Error; err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Str("auction", key.String()).Str("to", string(t.To)).Msg("更新拍卖状态失败")
+		return false, err
 	}
-	return nil
+	return applied, nil
+}
+
+// GetPendingStarted 查询某个拍卖合约上已到开始时间但仍未开始的拍卖
+func (r *auctionRepository) GetPendingStarted(chainID uint64, contract string, now time.Time, limit int) ([]*models.Auction, error) {
+	return r.getDue(chainID, contract, models.AuctionStatusPending, "start_time", now, limit)
+}
+
+// GetActiveExpired 查询某个拍卖合约上已过结束时间但仍在进行中的拍卖
+func (r *auctionRepository) GetActiveExpired(chainID uint64, contract string, now time.Time, limit int) ([]*models.Auction, error) {
+	return r.getDue(chainID, contract, models.AuctionStatusActive, "end_time", now, limit)
+}
+
+// getDue 查询状态为status且timeColumn早于now的拍卖（按timeColumn升序）
+func (r *auctionRepository) getDue(chainID uint64, contract string, status models.AuctionStatus, timeColumn string, now time.Time, limit int) ([]*models.Auction, error) {
+	var auctions []*models.Auction
+	if err := r.db.Where("chain_id = ? AND contract_address = ? AND status = ?", chainID, contract, status).
+		Where(timeColumn+" < ?", now).
+		Order(timeColumn + " ASC").
+		Limit(limit).
+		Find(&auctions).Error; err != nil {
+		log.Error().Err(err).Str("status", string(status)).Msg("查询待迁移状态的拍卖失败")
+		return nil, err
+	}
+	return auctions, nil
 }
 
 // UpdateCurrentPrice 更新拍卖当前最高价
-func (r *auctionRepository) UpdateCurrentPrice(auctionID uint, HighestBid uint64, HighestBidder string, TokenAddress string) error {
+func (r *auctionRepository) UpdateCurrentPrice(key models.AuctionKey, HighestBid uint64, HighestBidder string, TokenAddress string) error {
 	if err := r.db.Model(&models.Auction{}).
-		Where("id = ?", auctionID).
+		Scopes(byAuctionKey(key)).
 		Updates(map[string]interface{}{
 			"highest_bid":    HighestBid,
 			"highest_bidder": HighestBidder,
 			"token_address":  TokenAddress,
 		}).Error; err != nil {
-		log.Error().Err(err).Uint("auction_id", auctionID).Msg("更新拍卖当前价失败")
+		log.Error().Err(err).Str("auction", key.String()).Msg("更新拍卖当前价失败")
 		return err
 	}
 	return nil
 }
 
-// getAuctionCount 获取拍卖总数量
-func (r *auctionRepository) GetAuctionCount() (int64, error) {
+// GetUnsettledExpired 查询某个拍卖合约上已过结束时间但尚未结束的拍卖（按结束时间升序）
+func (r *auctionRepository) GetUnsettledExpired(chainID uint64, contract string, now time.Time, limit int) ([]*models.Auction, error) {
+	var auctions []*models.Auction
+	if err := r.db.Where("chain_id = ? AND contract_address = ? AND settled = ? AND status <> ? AND end_time < ?", chainID, contract, false, models.AuctionStatusCancelled, now).
+		Order("end_time ASC").
+		Limit(limit).
+		Find(&auctions).Error; err != nil {
+		log.Error().Err(err).Msg("查询待结束拍卖失败")
+		return nil, err
+	}
+	return auctions, nil
+}
+
+// MarkSettled 标记拍卖已在链上结束
+func (r *auctionRepository) MarkSettled(key models.AuctionKey) error {
+	if err := r.db.Model(&models.Auction{}).
+		Scopes(byAuctionKey(key)).
+		Update("settled", true).Error; err != nil {
+		log.Error().Err(err).Str("auction", key.String()).Msg("标记拍卖已结束失败")
+		return err
+	}
+	return nil
+}
+
+// GetOpenByNFT 查询某条链上NFT尚未结束的拍卖
+func (r *auctionRepository) GetOpenByNFT(chainID uint64, nftContract string, tokenID uint) ([]*models.Auction, error) {
+	var auctions []*models.Auction
+	if err := r.db.Where("chain_id = ? AND nft_contract = ? AND nft_token_id = ? AND settled = ?", chainID, nftContract, tokenID, false).
+		Where("status IN ?", []models.AuctionStatus{models.AuctionStatusPending, models.AuctionStatusActive}).
+		Find(&auctions).Error; err != nil {
+		log.Error().Err(err).Uint("nft_id", tokenID).Msg("查询NFT拍卖失败")
+		return nil, err
+	}
+	return auctions, nil
+}
+
+// AuctionCursor 按结束时间与拍卖ID分页的位置，零值表示从头开始
+type AuctionCursor struct {
+	EndTime time.Time
+	ID      uint64
+}
+
+// CursorOf 以该拍卖为上一页最后一条的分页位置
+func CursorOf(auction *models.Auction) AuctionCursor {
+	return AuctionCursor{EndTime: auction.EndTime, ID: auction.ID}
+}
+
+// GetExpiredWithoutBids 查询某个拍卖合约上已过结束时间、尚未结束且没有出价的拍卖（核对链上是否已无人出价结束）
+// 按结束时间、拍卖ID升序返回after之后的一页，核对后仍未取消的拍卖不会在下一页重复出现
+func (r *auctionRepository) GetExpiredWithoutBids(chainID uint64, contract string, now time.Time, after AuctionCursor, limit int) ([]*models.Auction, error) {
+	var auctions []*models.Auction
+	if err := r.db.Where("chain_id = ? AND contract_address = ? AND settled = ? AND highest_bid = ? AND end_time < ?", chainID, contract, false, 0, now).
+		Where("status IN ?", models.AuctionStatusesBefore(models.AuctionStatusCancelled)).
+		Where("end_time > ? OR (end_time = ? AND id > ?)", after.EndTime, after.EndTime, after.ID).
+		Order("end_time ASC, id ASC").
+		Limit(limit).
+		Find(&auctions).Error; err != nil {
+		log.Error().Err(err).Msg("查询无人出价的过期拍卖失败")
+		return nil, err
+	}
+	return auctions, nil
+}
+
+// GetCancelledByCreator 查询创建者已取消的拍卖（按取消时间倒序），chainID为0时查询全部链
+func (r *auctionRepository) GetCancelledByCreator(chainID uint64, creator string) ([]*models.Auction, error) {
+	var auctions []*models.Auction
+	if err := r.db.Preload("NFT").
+		Where("creator_address = ? AND status = ?", creator, models.AuctionStatusCancelled).
+		Scopes(byChain("chain_id", chainID)).
+		Order("cancelled_at DESC").
+		Find(&auctions).Error; err != nil {
+		log.Error().Err(err).Str("creator", creator).Msg("查询已取消拍卖失败")
+		return nil, err
+	}
+	return auctions, nil
+}
+
+// getAuctionCount 获取拍卖总数量，chainID为0时统计全部链
+func (r *auctionRepository) GetAuctionCount(chainID uint64) (int64, error) {
 	var count int64
-	if err := r.db.Model(&models.Auction{}).Count(&count).Error; err != nil {
+	if err := r.db.Model(&models.Auction{}).Scopes(byChain("chain_id", chainID)).Count(&count).Error; err != nil {
 		log.Error().Err(err).Msg("获取拍卖总数量失败")
 
 		return 0, err
@@ -109,6 +282,7 @@ func (r *auctionRepository) GetAuctionCount() (int64, error) {
 
 // 动态搜索参数
 type AuctionSearchParams struct {
+	ChainID       uint64 // 为0时搜索全部链
 	Name          string
 	TokenID       uint
 	EndTimeMin    time.Time
@@ -123,6 +297,9 @@ type AuctionSearchParams struct {
 // 封装搜索范围
 func SearchAuctions(params AuctionSearchParams) func(tx *gorm.DB) *gorm.DB {
 	return func(tx *gorm.DB) *gorm.DB {
+		if params.ChainID != 0 {
+			tx = tx.Where("auctions.chain_id = ?", params.ChainID)
+		}
 		if params.Name != "" {
 			tx = tx.Where("name LIKE ?", "%"+params.Name+"%")
 		}
@@ -144,6 +321,12 @@ func SearchAuctions(params AuctionSearchParams) func(tx *gorm.DB) *gorm.DB {
 		if params.StartPriceMin != 0 {
 			tx = tx.Where("start_price >= ?", params.StartPriceMin)
 		}
+		// 已取消的拍卖不可出价，未指定状态时不返回，需按status=cancelled单独查询
+		if params.Status != "" {
+			tx = tx.Where("auctions.status = ?", params.Status)
+		} else {
+			tx = tx.Where("auctions.status <> ?", models.AuctionStatusCancelled)
+		}
 		return tx
 	}
 }
@@ -179,24 +362,27 @@ func SortAuctions(params SortParams) func(tx *gorm.DB) *gorm.DB {
 }
 
 type AuctionDetail struct {
-	ImageURL   string
-	Name       string
-	TokenID    string
-	StartTime  time.Time
-	EndTime    time.Time
-	HighestBid uint64
-	StartPrice uint64
-	Status     models.AuctionStatus
-	AuctionID  uint64
+	ChainID         uint64
+	ContractAddress string
+	ImageURL        string
+	Name            string
+	TokenID         string
+	StartTime       time.Time
+	EndTime         time.Time
+	HighestBid      uint64
+	StartPrice      uint64
+	Status          models.AuctionStatus
+	CancelledAt     *time.Time
+	AuctionID       uint64
 }
 
 func (r *auctionRepository) SearchAuctions(params AuctionSearchParams, sortParams SortParams, pageParams utils.PageParams) ([]AuctionDetail, error) {
 	var AuctionDetails []AuctionDetail
 
 	err := r.db.Table("auctions").
-		Joins("JOIN nfts ON nfts.token_id = auctions.nft_token_id").
+		Joins("JOIN nfts ON nfts.chain_id = auctions.chain_id AND nfts.contract_address = auctions.nft_contract AND nfts.token_id = auctions.nft_token_id").
 		Scopes(SearchAuctions(params), SortAuctions(sortParams), utils.Paginate(pageParams)).
-		Select("nfts.image_url, nfts.name, nfts.token_id, auctions.start_time, auctions.end_time, auctions.highest_bid, auctions.start_price, auctions.status, auctions.id AS AuctionID").
+		Select("auctions.chain_id, auctions.contract_address, nfts.image_url, nfts.name, nfts.token_id, auctions.start_time, auctions.end_time, auctions.highest_bid, auctions.start_price, auctions.status, auctions.cancelled_at, auctions.id AS AuctionID").
 		Scan(&AuctionDetails).Error
 
 	if err != nil {
@@ -207,10 +393,17 @@ func (r *auctionRepository) SearchAuctions(params AuctionSearchParams, sortParam
 	return AuctionDetails, nil
 }
 
-// GetAuctionsByIDs 根据拍卖ID获取拍卖，且保持顺序
-func (r *auctionRepository) GetAuctionsByIDs(auctionIDs []uint64) ([]AuctionDetail, error) {
+// GetAuctionsByKeys 根据拍卖标识获取拍卖，且保持顺序
+func (r *auctionRepository) GetAuctionsByKeys(keys []models.AuctionKey) ([]AuctionDetail, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	conds := r.db.Where("1 = 0")
+	for _, key := range keys {
+		conds = conds.Or("chain_id = ? AND contract_address = ? AND id = ?", key.ChainID, key.Contract, key.ID)
+	}
 	var auctions []*models.Auction
-	if err := r.db.Where("id IN (?)", auctionIDs).Find(&auctions).Error; err != nil {
+	if err := r.db.Where(conds).Find(&auctions).Error; err != nil {
 		log.Error().Err(err).Msg("获取拍卖失败")
 		return nil, err
 	}
@@ -218,39 +411,59 @@ func (r *auctionRepository) GetAuctionsByIDs(auctionIDs []uint64) ([]AuctionDeta
 	var AuctionDetails []AuctionDetail
 
 	nftRepository := NewNFTRepository()
-	for auctionID := range auctionIDs {
-		index := getIndex(auctions, uint64(auctionID))
+	for _, key := range keys {
+		index := getIndex(auctions, key)
 		if index == -1 {
 			return nil, errors.New("无效的拍卖ID")
 		}
 
-		nft, err := nftRepository.GetNFTByTokenID(auctions[index].NFTTokenID)
+		nft, err := nftRepository.GetNFTByTokenID(key.ChainID, auctions[index].NFTContract, auctions[index].NFTTokenID)
 		if err != nil {
 			log.Error().Err(err).Msg("获取NFT失败")
 			return nil, err
 		}
 
 		AuctionDetails = append(AuctionDetails, AuctionDetail{
-			ImageURL:   nft.ImageURL,
-			Name:       nft.Name,
-			TokenID:    fmt.Sprint(auctions[index].NFTTokenID),
-			StartTime:  auctions[index].StartTime,
-			EndTime:    auctions[index].EndTime,
-			HighestBid: auctions[index].HighestBid,
-			StartPrice: auctions[index].StartPrice,
-			Status:     auctions[index].Status,
-			AuctionID:  uint64(auctions[index].ID),
+			ChainID:         auctions[index].ChainID,
+			ContractAddress: auctions[index].ContractAddress,
+			ImageURL:        nft.ImageURL,
+			Name:            nft.Name,
+			TokenID:         fmt.Sprint(auctions[index].NFTTokenID),
+			StartTime:       auctions[index].StartTime,
+			EndTime:         auctions[index].EndTime,
+			HighestBid:      auctions[index].HighestBid,
+			StartPrice:      auctions[index].StartPrice,
+			Status:          auctions[index].Status,
+			CancelledAt:     auctions[index].CancelledAt,
+			AuctionID:       uint64(auctions[index].ID),
 		})
 
 	}
 	return AuctionDetails, nil
 }
 
-func getIndex(auctions []*models.Auction, ID uint64) int64 {
+func getIndex(auctions []*models.Auction, key models.AuctionKey) int64 {
 	for i, v := range auctions {
-		if v.ID == ID {
+		if v.Key() == key {
 			return int64(i)
 		}
 	}
 	return -1
 }
+
+// byAuctionKey 按链ID、拍卖合约地址与拍卖ID定位拍卖
+func byAuctionKey(key models.AuctionKey) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("chain_id = ? AND contract_address = ? AND id = ?", key.ChainID, key.Contract, key.ID)
+	}
+}
+
+// byChain 按链过滤，chainID为0时不过滤
+func byChain(column string, chainID uint64) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if chainID == 0 {
+			return tx
+		}
+		return tx.Where(column+" = ?", chainID)
+	}
+}
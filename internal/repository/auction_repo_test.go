@@ -0,0 +1,122 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/internal/testutil"
+	"github.com/ydh2333/NFTAuction-project/utils"
+)
+
+// TestGetExpiredWithoutBidsPaging 按游标翻页：结束时间相同的拍卖按ID续接，不重复也不遗漏，末页后从头开始
+func TestGetExpiredWithoutBidsPaging(t *testing.T) {
+	testutil.InitDB(t)
+	const contract = "0x00000000000000000000000000000000000000A1"
+	now := time.Now()
+	earlier, later := now.Add(-2*time.Hour), now.Add(-time.Hour)
+
+	rows := []*models.Auction{
+		{ID: 1, EndTime: later},
+		{ID: 2, EndTime: earlier},
+		{ID: 3, EndTime: later},
+		{ID: 4, EndTime: earlier},
+		{ID: 5, EndTime: later},
+		{ID: 6, EndTime: later, HighestBid: 10},                          // 有出价
+		{ID: 7, EndTime: now.Add(time.Hour)},                             // 未到结束时间
+		{ID: 8, EndTime: earlier, Status: models.AuctionStatusCancelled}, // 已取消
+	}
+	for _, a := range rows {
+		a.ChainID, a.ContractAddress, a.CreatorAddress = 1, contract, "0xseller"
+		if a.Status == "" {
+			a.Status = models.AuctionStatusActive
+		}
+		if err := repository.DB.Create(a).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := repository.NewAuctionRepository()
+	var cursor repository.AuctionCursor
+	var got []uint64
+	for page := 0; page < 3; page++ {
+		auctions, err := repo.GetExpiredWithoutBids(1, contract, now, cursor, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range auctions {
+			got = append(got, a.ID)
+		}
+		if len(auctions) < 2 {
+			break
+		}
+		cursor = repository.CursorOf(auctions[len(auctions)-1])
+	}
+
+	want := []uint64{2, 4, 1, 3, 5}
+	if len(got) != len(want) {
+		t.Fatalf("翻页结果 = %v，期望 %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("翻页结果 = %v，期望 %v", got, want)
+		}
+	}
+
+	first, err := repo.GetExpiredWithoutBids(1, contract, now, repository.AuctionCursor{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 2 || first[0].ID != 2 || first[1].ID != 4 {
+		t.Errorf("游标归零后应从头开始，实际 %d 条", len(first))
+	}
+}
+
+// TestAuctionNFTScopedByContract 不同NFT合约的TokenID相同时，拍卖只关联自己NFT合约中的NFT
+func TestAuctionNFTScopedByContract(t *testing.T) {
+	testutil.InitDB(t)
+	const (
+		contract = "0x00000000000000000000000000000000000000A1"
+		cats     = "0x00000000000000000000000000000000000000C1"
+		dogs     = "0x00000000000000000000000000000000000000D1"
+	)
+	for _, nft := range []*models.NFT{
+		{ChainID: 1, ContractAddress: cats, TokenID: 7, OwnerAddress: "0xowner", Name: "Cat #7"},
+		{ChainID: 1, ContractAddress: dogs, TokenID: 7, OwnerAddress: "0xowner", Name: "Dog #7"},
+	} {
+		if err := repository.DB.Create(nft).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	auction := &models.Auction{ChainID: 1, ContractAddress: contract, ID: 1, CreatorAddress: "0xseller",
+		NFTContract: dogs, NFTTokenID: 7, Status: models.AuctionStatusActive, EndTime: time.Now().Add(time.Hour)}
+	if err := repository.DB.Omit("NFT").Create(auction).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	repo := repository.NewAuctionRepository()
+	found, err := repo.SearchAuctions(repository.AuctionSearchParams{ChainID: 1}, repository.SortParams{}, utils.PageParams{Page: 1, Size: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Name != "Dog #7" {
+		t.Errorf("SearchAuctions = %+v，期望只关联Dog #7", found)
+	}
+
+	details, err := repo.GetAuctionsByKeys([]models.AuctionKey{auction.Key()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details) != 1 || details[0].Name != "Dog #7" {
+		t.Errorf("GetAuctionsByKeys = %+v，期望关联Dog #7", details)
+	}
+
+	got, err := repo.GetByID(auction.Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NFT.Name != "Dog #7" {
+		t.Errorf("GetByID关联NFT = %q，期望 Dog #7", got.NFT.Name)
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AuctionResultRepository interface {
+	Save(result *models.AuctionResult) error
+	GetByAuction(key models.AuctionKey) (*models.AuctionResult, error)
+	GetByParticipant(chainID uint64, address string) ([]*models.AuctionResult, error)
+}
+
+type auctionResultRepository struct {
+	db *gorm.DB
+}
+
+func NewAuctionResultRepository() AuctionResultRepository {
+	return &auctionResultRepository{db: DB}
+}
+
+func NewAuctionResultRepositoryWithTx(tx *gorm.DB) AuctionResultRepository {
+	return &auctionResultRepository{db: tx}
+}
+
+// Save 保存拍卖成交结果，同一拍卖已存在时覆盖（重复同步EndAuction事件结果相同）
+func (r *auctionResultRepository) Save(result *models.AuctionResult) error {
+	if err := r.db.Omit("Auction").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "chain_id"}, {Name: "contract_address"}, {Name: "auction_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"opt_time", "seller_address", "winner_address", "final_price", "token_address", "usd_value", "winning_bid_id",
+			"settlement_tx_hash", "settlement_block", "settled_at", "nft_transferred", "nft_transfer_log_index",
+		}),
+	}).Create(result).Error; err != nil {
+		log.Error().Err(err).Str("auction", result.AuctionKey().String()).Msg("保存拍卖成交结果失败")
+		return err
+	}
+	return nil
+}
+
+// GetByAuction 查询拍卖的成交结果，未成交（进行中、已取消或未同步结算事件）时返回nil
+func (r *auctionResultRepository) GetByAuction(key models.AuctionKey) (*models.AuctionResult, error) {
+	var result models.AuctionResult
+	if err := r.db.Where("chain_id = ? AND contract_address = ? AND auction_id = ?", key.ChainID, key.Contract, key.ID).
+		First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Error().Err(err).Str("auction", key.String()).Msg("查询拍卖成交结果失败")
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetByParticipant 查询用户作为卖家或获胜者的成交结果（按结算时间倒序），chainID为0时查询全部链
+func (r *auctionResultRepository) GetByParticipant(chainID uint64, address string) ([]*models.AuctionResult, error) {
+	var results []*models.AuctionResult
+	if err := r.db.Preload("Auction.NFT").
+		Where("seller_address = ? OR winner_address = ?", address, address).
+		Scopes(byChain("chain_id", chainID)).
+		Order("settled_at DESC").
+		Find(&results).Error; err != nil {
+		log.Error().Err(err).Str("address", address).Msg("查询用户成交结果失败")
+		return nil, err
+	}
+	return results, nil
+}
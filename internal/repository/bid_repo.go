@@ -4,14 +4,16 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/ydh2333/NFTAuction-project/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BidRepository interface {
 	Create(bid *models.Bid) error
-	GetByAuctionID(auctionID uint) ([]*models.Bid, error)
-	GetHighestBidByAuctionID(auctionID uint) (*models.Bid, error)
-	MarkWinningBid(bidID uint) error
-	GetBidCount() (int64, error)
+	CreateBatch(bids []*models.Bid) error
+	GetByAuctionID(key models.AuctionKey) ([]*models.Bid, error)
+	GetHighestBidByAuctionID(key models.AuctionKey) (*models.Bid, error)
+	MarkWinningBid(key models.AuctionKey, winner string, amount uint64, token string) (*models.Bid, error)
+	GetBidCount(chainID uint64) (int64, error)
 	GetBidAll() ([]models.Bid, error)
 }
 
@@ -36,10 +38,22 @@ func (r *bidRepository) Create(bid *models.Bid) error {
 	return nil
 }
 
+// CreateBatch 批量创建竞拍记录，同一出价事件（交易哈希+日志索引）已存在的跳过
+func (r *bidRepository) CreateBatch(bids []*models.Bid) error {
+	if len(bids) == 0 {
+		return nil
+	}
+	if err := r.db.Omit("Auction").Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(bids, batchSize).Error; err != nil {
+		log.Error().Err(err).Int("count", len(bids)).Msg("批量创建竞拍记录失败")
+		return err
+	}
+	return nil
+}
+
 // GetByAuctionID 根据拍卖ID查询竞拍记录
-func (r *bidRepository) GetByAuctionID(auctionID uint) ([]*models.Bid, error) {
+func (r *bidRepository) GetByAuctionID(key models.AuctionKey) ([]*models.Bid, error) {
 	var bids []*models.Bid
-	if err := r.db.Where("auction_id = ?", auctionID).Find(&bids).Error; err != nil {
+	if err := r.db.Scopes(byBidAuction(key)).Find(&bids).Error; err != nil {
 		log.Error().Err(err).Msg("查询竞拍记录失败")
 		return nil, err
 	}
@@ -47,28 +61,45 @@ func (r *bidRepository) GetByAuctionID(auctionID uint) ([]*models.Bid, error) {
 }
 
 // GetHighestBidByAuctionID 根据拍卖ID查询最高竞拍
-func (r *bidRepository) GetHighestBidByAuctionID(auctionID uint) (*models.Bid, error) {
+func (r *bidRepository) GetHighestBidByAuctionID(key models.AuctionKey) (*models.Bid, error) {
 	var bid models.Bid
-	if err := r.db.Where("auction_id = ?", auctionID).Order("amount DESC").First(&bid).Error; err != nil {
+	if err := r.db.Scopes(byBidAuction(key)).Order("amount DESC").First(&bid).Error; err != nil {
 		log.Error().Err(err).Msg("查询最高竞拍失败")
 		return nil, err
 	}
 	return &bid, nil
 }
 
-// MarkWinningBid 标记获胜竞拍，拍卖结束时更新
-func (r *bidRepository) MarkWinningBid(bidID uint) error {
-	if err := r.db.Model(&models.Bid{}).Where("auction_id = ?", bidID).Update("is_winning", true).Error; err != nil {
-		log.Error().Err(err).Msg("标记获胜竞拍失败")
-		return err
+// MarkWinningBid 按EndAuction事件的获胜者、成交价与货币标记唯一的获胜竞拍（同一出价者重复出相同价格时取最后一次），
+// 拍卖的其他出价取消标记；未同步到对应出价时返回nil
+func (r *bidRepository) MarkWinningBid(key models.AuctionKey, winner string, amount uint64, token string) (*models.Bid, error) {
+	if err := r.db.Model(&models.Bid{}).Scopes(byBidAuction(key)).Where("is_winning = ?", true).Update("is_winning", false).Error; err != nil {
+		log.Error().Err(err).Str("auction", key.String()).Msg("重置获胜竞拍失败")
+		return nil, err
 	}
-	return nil
+
+	var bids []*models.Bid
+	if err := r.db.Scopes(byBidAuction(key)).
+		Where("bidder_address = ? AND amount = ? AND token_address = ?", winner, amount, token).
+		Order("id DESC").Limit(1).Find(&bids).Error; err != nil {
+		log.Error().Err(err).Str("auction", key.String()).Msg("查询获胜竞拍失败")
+		return nil, err
+	}
+	if len(bids) == 0 {
+		return nil, nil
+	}
+	if err := r.db.Model(&models.Bid{}).Where("id = ?", bids[0].ID).Update("is_winning", true).Error; err != nil {
+		log.Error().Err(err).Str("auction", key.String()).Msg("标记获胜竞拍失败")
+		return nil, err
+	}
+	bids[0].IsWinning = true
+	return bids[0], nil
 }
 
-// GetBidCount 获取出价总数
-func (r *bidRepository) GetBidCount() (int64, error) {
+// GetBidCount 获取出价总数，chainID为0时统计全部链
+func (r *bidRepository) GetBidCount(chainID uint64) (int64, error) {
 	var count int64
-	if err := r.db.Model(&models.Bid{}).Count(&count).Error; err != nil {
+	if err := r.db.Model(&models.Bid{}).Scopes(byChain("chain_id", chainID)).Count(&count).Error; err != nil {
 		log.Error().Err(err).Msg("获取出价总数失败")
 		return 0, err
 	}
@@ -84,3 +115,10 @@ func (r *bidRepository) GetBidAll() ([]models.Bid, error) {
 	}
 	return bids, nil
 }
+
+// byBidAuction 按拍卖标识过滤出价
+func byBidAuction(key models.AuctionKey) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("chain_id = ? AND contract_address = ? AND auction_id = ?", key.ChainID, key.Contract, key.ID)
+	}
+}
@@ -0,0 +1,89 @@
+package repository_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/internal/testutil"
+)
+
+// TestMarkWinningBid 按获胜者、成交价与货币标记唯一的获胜竞拍，重复结算时先清除旧标记，其他拍卖不受影响
+func TestMarkWinningBid(t *testing.T) {
+	const (
+		contract = "0x00000000000000000000000000000000000000A1"
+		alice    = "0x1000000000000000000000000000000000000001"
+		bob      = "0x2000000000000000000000000000000000000002"
+		eth      = "0x0000000000000000000000000000000000000000"
+		usdc     = "0x3000000000000000000000000000000000000003"
+	)
+	key := models.AuctionKey{ChainID: 1, Contract: contract, ID: 1}
+	other := models.AuctionKey{ChainID: 1, Contract: contract, ID: 2}
+
+	tests := []struct {
+		name    string
+		winner  string
+		amount  uint64
+		token   string
+		wantID  uint   // 0表示没有匹配的竞拍
+		setWins []uint // 之前结算已标记为获胜的竞拍
+	}{
+		{"唯一匹配", bob, 20, eth, 3, nil},
+		{"重复出相同价格取最后一次", alice, 10, eth, 4, nil},
+		{"货币不同不匹配", alice, 10, usdc, 0, nil},
+		{"重复结算清除旧标记", bob, 20, eth, 3, []uint{1}},
+		{"没有匹配时清除旧标记", bob, 30, eth, 0, []uint{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.InitDB(t)
+			bids := []*models.Bid{
+				{ID: 1, BidderAddress: alice, Amount: 10, TokenAddress: eth},
+				{ID: 2, BidderAddress: bob, Amount: 20, TokenAddress: usdc},
+				{ID: 3, BidderAddress: bob, Amount: 20, TokenAddress: eth},
+				{ID: 4, BidderAddress: alice, Amount: 10, TokenAddress: eth},
+			}
+			for _, b := range bids {
+				b.ChainID, b.ContractAddress, b.AuctionID = key.ChainID, key.Contract, key.ID
+			}
+			// 其他拍卖已标记的获胜竞拍
+			bids = append(bids, &models.Bid{ID: 5, ChainID: other.ChainID, ContractAddress: other.Contract, AuctionID: other.ID,
+				BidderAddress: bob, Amount: 20, TokenAddress: eth, IsWinning: true})
+			for _, b := range bids {
+				for _, id := range tt.setWins {
+					if b.ID == id {
+						b.IsWinning = true
+					}
+				}
+				if err := repository.DB.Create(b).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := repository.NewBidRepository().MarkWinningBid(key, tt.winner, tt.amount, tt.token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantID == 0 {
+				if got != nil {
+					t.Fatalf("MarkWinningBid = %+v，期望没有匹配", got)
+				}
+			} else if got == nil || got.ID != tt.wantID || !got.IsWinning {
+				t.Fatalf("MarkWinningBid = %+v，期望竞拍%d", got, tt.wantID)
+			}
+
+			var winners []uint
+			if err := repository.DB.Model(&models.Bid{}).Where("is_winning = ?", true).Order("id").Pluck("id", &winners).Error; err != nil {
+				t.Fatal(err)
+			}
+			want := []uint{5}
+			if tt.wantID != 0 {
+				want = []uint{tt.wantID, 5}
+			}
+			if !slices.Equal(winners, want) {
+				t.Errorf("获胜竞拍 = %v，期望 %v", winners, want)
+			}
+		})
+	}
+}
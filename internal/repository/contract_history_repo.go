@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContractHistoryRepository interface {
+	Create(history *models.ContractHistory) error
+	List(chainID uint64, contractAddress string, pageParams utils.PageParams) ([]models.ContractHistory, int64, error)
+	GetUpgrades(chainID uint64, contractAddress string) ([]models.ContractHistory, error)
+}
+
+type contractHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewContractHistoryRepository() ContractHistoryRepository {
+	return &contractHistoryRepository{db: DB}
+}
+
+func NewContractHistoryRepositoryWithTx(tx *gorm.DB) ContractHistoryRepository {
+	return &contractHistoryRepository{db: tx}
+}
+
+// Create 记录合约历史，同一日志重复处理时忽略
+func (r *contractHistoryRepository) Create(history *models.ContractHistory) error {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(history).Error; err != nil {
+		log.Error().Err(err).Str("tx_hash", history.TxHash).Msg("记录合约历史失败")
+		return err
+	}
+	return nil
+}
+
+// List 按区块倒序查询合约历史，chainID为0、contractAddress为空时不按其过滤
+func (r *contractHistoryRepository) List(chainID uint64, contractAddress string, pageParams utils.PageParams) ([]models.ContractHistory, int64, error) {
+	var histories []models.ContractHistory
+	var total int64
+
+	query := r.db.Model(&models.ContractHistory{}).Scopes(byChain("chain_id", chainID))
+	if contractAddress != "" {
+		query = query.Where("contract_address = ?", contractAddress)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		log.Error().Err(err).Msg("统计合约历史失败")
+		return nil, 0, err
+	}
+
+	if err := query.Order("block_number DESC, log_index DESC").
+		Scopes(utils.Paginate(pageParams)).
+		Find(&histories).Error; err != nil {
+		log.Error().Err(err).Msg("查询合约历史失败")
+		return nil, 0, err
+	}
+	return histories, total, nil
+}
+
+// GetUpgrades 按区块顺序查询合约的升级记录（启动时恢复ABI版本的生效区块）
+func (r *contractHistoryRepository) GetUpgrades(chainID uint64, contractAddress string) ([]models.ContractHistory, error) {
+	var histories []models.ContractHistory
+	if err := r.db.Where("chain_id = ? AND contract_address = ? AND event_type = ?", chainID, contractAddress, models.ContractEventUpgraded).
+		Order("block_number ASC, log_index ASC").
+		Find(&histories).Error; err != nil {
+		log.Error().Err(err).Str("contract", contractAddress).Msg("查询合约升级记录失败")
+		return nil, err
+	}
+	return histories, nil
+}
@@ -12,14 +12,13 @@ import (
 // DB 全局数据库连接
 var DB *gorm.DB
 
+// batchSize 批量插入时每条INSERT语句的行数
+const batchSize = 200
+
 // InitDB 初始化数据库连接
 func InitDB(cfg *config.MySQLConfig) {
-	var err error
-	DB, err = gorm.Open(mysql.Open(cfg.DSN), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info), // 打印SQL日志（开发环境）
-	})
-	if err != nil {
-		log.Fatal().Err(err).Msg("数据库连接失败")
+	if err := Open(mysql.Open(cfg.DSN), cfg); err != nil {
+		log.Fatal().Err(err).Msg("数据库初始化失败")
 	}
 
 	// 设置连接池
@@ -31,17 +30,45 @@ func InitDB(cfg *config.MySQLConfig) {
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
+	log.Info().Msg("数据库连接成功")
+}
+
+// Open 使用指定驱动连接数据库并迁移表结构（InitDB使用MySQL，测试可使用SQLite），cfg提供数据迁移所需的配置
+func Open(dialector gorm.Dialector, cfg *config.MySQLConfig) error {
+	var err error
+	DB, err = gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info), // 打印SQL日志（开发环境）
+		// 外键由数据迁移在调整主键、唯一索引后创建（新表的外键可能引用旧库中尚未修改的主键）
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("数据库连接失败")
+		return err
+	}
+
 	// 自动迁移表结构
 	err = DB.AutoMigrate(
 		&models.NFT{},
 		&models.Auction{},
+		&models.AuctionStatusHistory{},
 		&models.Bid{},
+		&models.AuctionResult{},
+		&models.ContractHistory{},
+		&models.Transaction{},
+		&models.TransactionHash{},
+		&models.AdminOperation{},
+		&models.AdminOperationEvent{},
+		&models.SyncProgress{},
 	)
 	if err != nil {
-		log.Fatal().Err(err).Msg("数据库表迁移失败")
+		log.Error().Err(err).Msg("数据库表迁移失败")
+		return err
 	}
-
-	log.Info().Msg("数据库连接成功")
+	if err := migrate(DB, cfg); err != nil {
+		log.Error().Err(err).Msg("数据迁移失败")
+		return err
+	}
+	return nil
 }
 
 // CloseDB 关闭数据库连接池
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// migrate 执行AutoMigrate无法完成的数据迁移，每一步都可重复执行
+// AutoMigrate只新增列与索引，不会修改主键、删除旧索引或重建列数变化的外键；外键统一在调整主键后创建。
+// 这些结构调整依赖MySQL的information_schema与ALTER TABLE语法，仅在MySQL上执行
+func migrate(db *gorm.DB, cfg *config.MySQLConfig) error {
+	steps := []struct {
+		name      string
+		mysqlOnly bool
+		run       func(db *gorm.DB) error
+	}{
+		{"登记交易被替换的历史哈希", false, backfillTransactionHashes},
+		{"删除引用旧主键与旧索引的外键", true, dropLegacyForeignKeys},
+		{"回填升级前数据的链ID与拍卖合约地址", false, func(db *gorm.DB) error {
+			return backfillChainScope(db, cfg.LegacyChainID, cfg.LegacyContract)
+		}},
+		{"拍卖主键改为链ID+合约地址+拍卖ID", true, migrateAuctionPrimaryKey},
+		{"删除NFT的TokenID唯一索引", true, dropNFTTokenIndex},
+		{"NFT唯一索引改为链ID+合约地址+TokenID", true, migrateNFTChainTokenIndex},
+		{"创建外键", true, createForeignKeys},
+	}
+	mysql := db.Dialector.Name() == "mysql"
+	for _, step := range steps {
+		if step.mysqlOnly && !mysql {
+			continue
+		}
+		if err := step.run(db); err != nil {
+			log.Error().Err(err).Str("step", step.name).Msg("数据迁移失败")
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillTransactionHashes 把交易记录中JSON格式的被替换哈希登记到transaction_hashes表
+func backfillTransactionHashes(db *gorm.DB) error {
+	var txs []models.Transaction
+	return db.Select("id", "replaced_hashes").Where("replacements > 0").
+		FindInBatches(&txs, batchSize, func(tx *gorm.DB, _ int) error {
+			var hashes []models.TransactionHash
+			for _, t := range txs {
+				for _, hash := range t.ReplacedHashes {
+					hashes = append(hashes, models.TransactionHash{TransactionID: t.ID, Hash: hash})
+				}
+			}
+			if len(hashes) == 0 {
+				return nil
+			}
+			return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&hashes).Error
+		}).Error
+}
+
+// backfillChainScope 支持多链、多合约前写入的记录chain_id为0、拍卖相关记录contract_address为空，按配置回填
+// chainID为0（未配置且单链配置也未填写链ID）时无法确定所在链，跳过并提示
+func backfillChainScope(db *gorm.DB, chainID uint64, contract string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if chainID == 0 {
+			var legacy int64
+			if err := tx.Model(&models.Auction{}).Where("chain_id = ?", 0).Count(&legacy).Error; err != nil {
+				return err
+			}
+			if legacy > 0 {
+				log.Warn().Int64("auctions", legacy).Msg("存在未记录链ID的历史数据，请配置mysql.legacyChainID后重启以回填")
+			}
+		} else {
+			for _, model := range []interface{}{
+				&models.NFT{}, &models.Auction{}, &models.AuctionStatusHistory{}, &models.Bid{}, &models.AuctionResult{},
+				&models.ContractHistory{}, &models.Transaction{}, &models.AdminOperation{},
+			} {
+				if err := tx.Model(model).Where("chain_id = ?", 0).UpdateColumn("chain_id", chainID).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if contract == "" {
+			return nil
+		}
+		contract = common.HexToAddress(contract).Hex()
+		for _, model := range []interface{}{&models.Auction{}, &models.AuctionStatusHistory{}, &models.Bid{}, &models.AuctionResult{}} {
+			if err := tx.Model(model).Where("contract_address = ?", "").UpdateColumn("contract_address", contract).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// foreignKeys 模型关联对应的外键及其列数，升级前的同名外键只引用拍卖ID、TokenID或链ID+TokenID
+var foreignKeys = []struct {
+	model   interface{}
+	table   string
+	name    string
+	columns int
+}{
+	{&models.Bid{}, "bids", "fk_bids_auction", 3},
+	{&models.AuctionResult{}, "auction_results", "fk_auction_results_auction", 3},
+	{&models.Auction{}, "auctions", "fk_auctions_nft", 3},
+	{&models.AdminOperation{}, "admin_operation_events", "fk_admin_operations_events", 1},
+}
+
+// dropLegacyForeignKeys 删除列数与模型不一致的外键，否则无法修改其引用的主键与唯一索引
+func dropLegacyForeignKeys(db *gorm.DB) error {
+	for _, fk := range foreignKeys {
+		columns, err := keyColumns(db, fk.table, fk.name)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 || len(columns) == fk.columns {
+			continue
+		}
+		if err := db.Migrator().DropConstraint(fk.model, fk.name); err != nil {
+			return err
+		}
+		log.Info().Str("table", fk.table).Str("constraint", fk.name).Strs("columns", columns).Msg("已删除旧外键")
+	}
+	return nil
+}
+
+// migrateAuctionPrimaryKey 升级前拍卖以自增ID为主键，改为链ID+合约地址+合约内拍卖ID（AUTO_INCREMENT列必须是索引首列，需先去掉）
+func migrateAuctionPrimaryKey(db *gorm.DB) error {
+	want := []string{"chain_id", "contract_address", "id"}
+	columns, err := keyColumns(db, "auctions", "PRIMARY")
+	if err != nil || slices.Equal(columns, want) {
+		return err
+	}
+	if err := db.Exec("ALTER TABLE auctions MODIFY id bigint unsigned NOT NULL, DROP PRIMARY KEY, ADD PRIMARY KEY (chain_id, contract_address, id)").Error; err != nil {
+		return err
+	}
+	log.Info().Strs("from", columns).Strs("to", want).Msg("已修改拍卖表主键")
+	return nil
+}
+
+// dropNFTTokenIndex 升级前TokenID全局唯一，各链的TokenID可能重复，唯一约束改为idx_nft_chain_token
+func dropNFTTokenIndex(db *gorm.DB) error {
+	if !db.Migrator().HasIndex(&models.NFT{}, "idx_nfts_token_id") {
+		return nil
+	}
+	if err := db.Migrator().DropIndex(&models.NFT{}, "idx_nfts_token_id"); err != nil {
+		return err
+	}
+	log.Info().Msg("已删除NFT的TokenID唯一索引")
+	return nil
+}
+
+// migrateNFTChainTokenIndex 不同NFT合约的TokenID可能重复，idx_nft_chain_token由链ID+TokenID改为链ID+合约地址+TokenID
+// AutoMigrate遇到同名索引不会修改其列，需删除后按模型重建（引用该索引的拍卖外键已由dropLegacyForeignKeys删除）
+func migrateNFTChainTokenIndex(db *gorm.DB) error {
+	want := []string{"chain_id", "contract_address", "token_id"}
+	columns, err := keyColumns(db, "nfts", "idx_nft_chain_token")
+	if err != nil || len(columns) == 0 || slices.Equal(columns, want) {
+		return err
+	}
+	if err := db.Migrator().DropIndex(&models.NFT{}, "idx_nft_chain_token"); err != nil {
+		return err
+	}
+	if err := db.Migrator().CreateIndex(&models.NFT{}, "idx_nft_chain_token"); err != nil {
+		return err
+	}
+	log.Info().Strs("from", columns).Strs("to", want).Msg("已修改NFT唯一索引")
+	return nil
+}
+
+// createForeignKeys 按模型创建缺少的外键（新建的表与删除的旧外键）
+func createForeignKeys(db *gorm.DB) error {
+	for _, fk := range foreignKeys {
+		if db.Migrator().HasConstraint(fk.model, fk.name) {
+			continue
+		}
+		if err := db.Migrator().CreateConstraint(fk.model, fk.name); err != nil {
+			return err
+		}
+		log.Info().Str("table", fk.table).Str("constraint", fk.name).Msg("已创建外键")
+	}
+	return nil
+}
+
+// keyColumns 查询MySQL主键、唯一键或外键包含的列（按顺序），约束不存在时返回空
+func keyColumns(db *gorm.DB, table, constraint string) ([]string, error) {
+	var columns []string
+	err := db.Raw(`SELECT COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND CONSTRAINT_NAME = ?
+		ORDER BY ORDINAL_POSITION`, table, constraint).Scan(&columns).Error
+	return columns, err
+}
@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/glebarez/sqlite"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"gorm.io/gorm/logger"
+)
+
+// TestBackfillChainScope 重启迁移时按配置回填升级前记录的链ID与拍卖合约地址，已有链ID的记录不变
+func TestBackfillChainScope(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db")
+	open := func(cfg *config.MySQLConfig) {
+		t.Helper()
+		if err := repository.Open(sqlite.Open(dsn), cfg); err != nil {
+			t.Fatal(err)
+		}
+		repository.DB.Logger = logger.Discard
+	}
+
+	open(&config.MySQLConfig{})
+	legacy := []interface{}{
+		&models.NFT{TokenID: 1, ContractAddress: "0xnft", OwnerAddress: "0xowner", Name: "legacy"},
+		&models.Auction{ID: 1, CreatorAddress: "0xseller", NFTTokenID: 1},
+		&models.Bid{AuctionID: 1, BidderAddress: "0xbidder", Amount: 10},
+		&models.Auction{ChainID: 1, ContractAddress: "0x00000000000000000000000000000000000000A1", ID: 1, CreatorAddress: "0xseller"},
+	}
+	for _, row := range legacy {
+		if err := repository.DB.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	repository.CloseDB()
+
+	const contract = "0x00000000000000000000000000000000000000b2"
+	open(&config.MySQLConfig{LegacyChainID: 11155111, LegacyContract: contract})
+	t.Cleanup(repository.CloseDB)
+
+	var nft models.NFT
+	if err := repository.DB.First(&nft).Error; err != nil {
+		t.Fatal(err)
+	}
+	if nft.ChainID != 11155111 {
+		t.Errorf("NFT chain_id = %d", nft.ChainID)
+	}
+
+	var auctions []models.Auction
+	if err := repository.DB.Order("chain_id").Find(&auctions).Error; err != nil {
+		t.Fatal(err)
+	}
+	want := []models.AuctionKey{
+		{ChainID: 1, Contract: "0x00000000000000000000000000000000000000A1", ID: 1},
+		{ChainID: 11155111, Contract: common.HexToAddress(contract).Hex(), ID: 1},
+	}
+	if len(auctions) != len(want) {
+		t.Fatalf("拍卖数量 = %d", len(auctions))
+	}
+	for i, a := range auctions {
+		if a.Key() != want[i] {
+			t.Errorf("拍卖 = %+v，期望 %+v", a.Key(), want[i])
+		}
+	}
+
+	var bid models.Bid
+	if err := repository.DB.First(&bid).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := bid.AuctionKey(); got != want[1] {
+		t.Errorf("出价所属拍卖 = %+v，期望 %+v", got, want[1])
+	}
+}
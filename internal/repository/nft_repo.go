@@ -1,15 +1,21 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/rs/zerolog/log"
 	"github.com/ydh2333/NFTAuction-project/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type NFTRepository interface {
 	Create(nft *models.NFT) error
-	GetNFTByTokenID(tokenID uint) (*models.NFT, error)
-	GetNFTByOwnerAddress(OwnerAddress string) ([]NftDetail, error)
+	CreateBatch(nfts []*models.NFT) error
+	GetNFTByTokenID(chainID uint64, contract string, tokenID uint) (*models.NFT, error)
+	GetNFTByOwnerAddress(chainID uint64, OwnerAddress string) ([]NftDetail, error)
+	GetMetadataPending(chainID uint64, contract string, now time.Time, limit int) ([]*models.NFT, error)
+	UpdateMetadata(nft *models.NFT) error
 }
 
 type nftRepository struct {
@@ -20,6 +26,10 @@ func NewNFTRepository() NFTRepository {
 	return &nftRepository{db: DB}
 }
 
+func NewNFTRepositoryWithTx(tx *gorm.DB) NFTRepository {
+	return &nftRepository{db: tx}
+}
+
 func (r *nftRepository) Create(nft *models.NFT) error {
 	if err := r.db.Create(nft).Error; err != nil {
 		log.Error().Err(err).Msg("创建NFT失败")
@@ -28,19 +38,59 @@ func (r *nftRepository) Create(nft *models.NFT) error {
 	return nil
 }
 
-// GetNFTByTokenID 根据TokenID查询NFT
-func (r *nftRepository) GetNFTByTokenID(tokenID uint) (*models.NFT, error) {
+// CreateBatch 批量创建NFT，链ID+合约地址+TokenID已存在的跳过（回填可重复执行）
+func (r *nftRepository) CreateBatch(nfts []*models.NFT) error {
+	if len(nfts) == 0 {
+		return nil
+	}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(nfts, batchSize).Error; err != nil {
+		log.Error().Err(err).Int("count", len(nfts)).Msg("批量创建NFT失败")
+		return err
+	}
+	return nil
+}
+
+// GetNFTByTokenID 根据链ID、NFT合约地址与TokenID查询NFT
+func (r *nftRepository) GetNFTByTokenID(chainID uint64, contract string, tokenID uint) (*models.NFT, error) {
 	var nft models.NFT
 
-	if err := r.db.First(&nft).Where("token_id=?", tokenID).Error; err != nil {
-		log.Error().Err(err).Uint("nft_id", tokenID).Msg("查询NFT失败")
+	if err := r.db.Where("chain_id = ? AND contract_address = ? AND token_id = ?", chainID, contract, tokenID).First(&nft).Error; err != nil {
+		log.Error().Err(err).Uint64("chain_id", chainID).Str("contract", contract).Uint("nft_id", tokenID).Msg("查询NFT失败")
 		return nil, err
 	}
 
 	return &nft, nil
 }
 
+// GetMetadataPending 查询到期需要重新获取元数据的NFT，按重试时间排序
+func (r *nftRepository) GetMetadataPending(chainID uint64, contract string, now time.Time, limit int) ([]*models.NFT, error) {
+	var nfts []*models.NFT
+	err := r.db.Where("chain_id = ? AND contract_address = ? AND metadata_pending = ?", chainID, contract, true).
+		Where("metadata_retry_at IS NULL OR metadata_retry_at <= ?", now).
+		Order("metadata_retry_at, id").
+		Limit(limit).
+		Find(&nfts).Error
+	if err != nil {
+		log.Error().Err(err).Uint64("chain_id", chainID).Msg("查询待补元数据的NFT失败")
+		return nil, err
+	}
+	return nfts, nil
+}
+
+// UpdateMetadata 更新NFT元数据与重试状态
+func (r *nftRepository) UpdateMetadata(nft *models.NFT) error {
+	err := r.db.Model(nft).
+		Select("name", "description", "image_url", "validation_warnings", "metadata_pending", "metadata_attempts", "metadata_retry_at").
+		Updates(nft).Error
+	if err != nil {
+		log.Error().Err(err).Uint("nft_id", nft.TokenID).Msg("更新NFT元数据失败")
+		return err
+	}
+	return nil
+}
+
 type NftDetail struct {
+	ChainID    uint64
 	ImageURL   string
 	Name       string
 	TokenID    string
@@ -48,13 +98,15 @@ type NftDetail struct {
 	Status     models.AuctionStatus
 }
 
-// GetNFTByOwnerAddress 查询个人NFT拍卖列表
-func (r *nftRepository) GetNFTByOwnerAddress(OwnerAddress string) ([]NftDetail, error) {
+// GetNFTByOwnerAddress 查询个人NFT拍卖列表，chainID为0时查询全部链
+// 已取消的拍卖不关联到NFT（由AuctionRepository.GetCancelledByCreator单独查询）
+func (r *nftRepository) GetNFTByOwnerAddress(chainID uint64, OwnerAddress string) ([]NftDetail, error) {
 	var nftDetails []NftDetail
 	err := r.db.Table("nfts").
-		Joins("LEFT JOIN auctions ON nfts.token_id = auctions.nft_token_id").
+		Joins("LEFT JOIN auctions ON nfts.chain_id = auctions.chain_id AND nfts.contract_address = auctions.nft_contract AND nfts.token_id = auctions.nft_token_id AND auctions.status <> ?", models.AuctionStatusCancelled).
 		Where("nfts.owner_address = ?", OwnerAddress).
-		Select("nfts.image_url, nfts.name, nfts.token_id, auctions.start_price, auctions.status").
+		Scopes(byChain("nfts.chain_id", chainID)).
+		Select("nfts.chain_id, nfts.image_url, nfts.name, nfts.token_id, auctions.start_price, auctions.status").
 		Scan(&nftDetails).Error
 
 	if err != nil {
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SyncProgressRepository interface {
+	Get(name string) (uint64, bool, error)
+	Save(name string, block uint64) error
+}
+
+type syncProgressRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncProgressRepository() SyncProgressRepository {
+	return &syncProgressRepository{db: DB}
+}
+
+func NewSyncProgressRepositoryWithTx(tx *gorm.DB) SyncProgressRepository {
+	return &syncProgressRepository{db: tx}
+}
+
+// Get 查询同步进度，未记录时返回false
+func (r *syncProgressRepository) Get(name string) (uint64, bool, error) {
+	var progress models.SyncProgress
+	if err := r.db.Where("name = ?", name).First(&progress).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, false, nil
+		}
+		log.Error().Err(err).Str("name", name).Msg("查询同步进度失败")
+		return 0, false, err
+	}
+	return progress.Block, true, nil
+}
+
+// Save 保存同步进度
+func (r *syncProgressRepository) Save(name string, block uint64) error {
+	progress := &models.SyncProgress{Name: name, Block: block, UpdatedAt: time.Now()}
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"block", "updated_at"}),
+	}).Create(progress).Error; err != nil {
+		log.Error().Err(err).Str("name", name).Uint64("block", block).Msg("保存同步进度失败")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TransactionRepository interface {
+	Create(tx *models.Transaction) error
+	Update(id uint, fields map[string]interface{}) error
+	Replace(id uint, fields map[string]interface{}, replacedHash string) error
+	GetPendingByFrom(chainID uint64, fromAddress string) ([]models.Transaction, error)
+	GetMaxPendingNonce(chainID uint64, fromAddress string) (uint64, bool, error)
+	GetPendingByAuction(purpose string, key models.AuctionKey) (*models.Transaction, error)
+	GetByHash(hash string) (*models.Transaction, error)
+	List(filter TransactionFilter, pageParams utils.PageParams) ([]models.Transaction, int64, string, error)
+}
+
+// TransactionFilter 交易列表过滤条件（零值表示不过滤）
+type TransactionFilter struct {
+	ChainID     uint64
+	Purpose     string
+	AuctionID   uint64
+	Status      models.TransactionStatus
+	FromAddress string
+	ToAddress   string    // 目标合约地址（不同拍卖合约部署的拍卖ID会重复）
+	Since       time.Time // 首次发送时间下限
+	Until       time.Time // 首次发送时间上限
+}
+
+// filterTransactions 封装交易过滤范围
+func filterTransactions(filter TransactionFilter) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if filter.ChainID != 0 {
+			tx = tx.Where("chain_id = ?", filter.ChainID)
+		}
+		if filter.Purpose != "" {
+			tx = tx.Where("purpose = ?", filter.Purpose)
+		}
+		if filter.AuctionID != 0 {
+			tx = tx.Where("auction_id = ?", filter.AuctionID)
+		}
+		if filter.Status != "" {
+			tx = tx.Where("status = ?", filter.Status)
+		}
+		if filter.FromAddress != "" {
+			tx = tx.Where("from_address = ?", filter.FromAddress)
+		}
+		if filter.ToAddress != "" {
+			tx = tx.Where("to_address = ?", filter.ToAddress)
+		}
+		if !filter.Since.IsZero() {
+			tx = tx.Where("submitted_at >= ?", filter.Since)
+		}
+		if !filter.Until.IsZero() {
+			tx = tx.Where("submitted_at <= ?", filter.Until)
+		}
+		return tx
+	}
+}
+
+type transactionRepository struct {
+	db *gorm.DB
+}
+
+func NewTransactionRepository() TransactionRepository {
+	return &transactionRepository{db: DB}
+}
+
+// Create 记录已发送的交易
+func (r *transactionRepository) Create(tx *models.Transaction) error {
+	if err := r.db.Create(tx).Error; err != nil {
+		log.Error().Err(err).Str("hash", tx.Hash).Msg("记录交易失败")
+		return err
+	}
+	return nil
+}
+
+// Update 更新交易字段（状态、替换哈希、回执信息等）
+func (r *transactionRepository) Update(id uint, fields map[string]interface{}) error {
+	if err := r.db.Model(&models.Transaction{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+		log.Error().Err(err).Uint("tx_id", id).Msg("更新交易失败")
+		return err
+	}
+	return nil
+}
+
+// Replace 记录加价替换：更新交易字段并登记被替换的哈希，两者在同一事务中完成
+func (r *transactionRepository) Replace(id uint, fields map[string]interface{}, replacedHash string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Transaction{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.TransactionHash{TransactionID: id, Hash: replacedHash}).Error
+	})
+	if err != nil {
+		log.Error().Err(err).Uint("tx_id", id).Str("replaced_hash", replacedHash).Msg("记录交易替换失败")
+		return err
+	}
+	return nil
+}
+
+// GetPendingByFrom 查询某条链上发送地址下仍在等待上链的交易
+func (r *transactionRepository) GetPendingByFrom(chainID uint64, fromAddress string) ([]models.Transaction, error) {
+	var txs []models.Transaction
+	if err := r.db.Where("chain_id = ? AND from_address = ? AND status IN ?", chainID, fromAddress,
+		[]models.TransactionStatus{models.TransactionStatusPending, models.TransactionStatusTimeout}).
+		Order("nonce ASC").Find(&txs).Error; err != nil {
+		log.Error().Err(err).Str("from", fromAddress).Msg("查询待上链交易失败")
+		return nil, err
+	}
+	return txs, nil
+}
+
+// GetMaxPendingNonce 查询某条链上发送地址下待上链交易的最大nonce，用于重启后恢复nonce
+func (r *transactionRepository) GetMaxPendingNonce(chainID uint64, fromAddress string) (uint64, bool, error) {
+	var result struct {
+		MaxNonce *uint64
+	}
+	if err := r.db.Model(&models.Transaction{}).
+		Select("MAX(nonce) AS max_nonce").
+		Where("chain_id = ? AND from_address = ? AND status IN ?", chainID, fromAddress,
+			[]models.TransactionStatus{models.TransactionStatusPending, models.TransactionStatusTimeout}).
+		Scan(&result).Error; err != nil {
+		log.Error().Err(err).Str("from", fromAddress).Msg("查询最大nonce失败")
+		return 0, false, err
+	}
+	if result.MaxNonce == nil {
+		return 0, false, nil
+	}
+	return *result.MaxNonce, true, nil
+}
+
+// GetPendingByAuction 查询拍卖某用途下仍在等待上链的最新交易，不存在时返回nil
+func (r *transactionRepository) GetPendingByAuction(purpose string, key models.AuctionKey) (*models.Transaction, error) {
+	var txs []models.Transaction
+	if err := r.db.Where("purpose = ? AND chain_id = ? AND to_address = ? AND auction_id = ? AND status IN ?", purpose, key.ChainID, key.Contract, key.ID,
+		[]models.TransactionStatus{models.TransactionStatusPending, models.TransactionStatusTimeout}).
+		Order("id DESC").Limit(1).Find(&txs).Error; err != nil {
+		log.Error().Err(err).Str("auction", key.String()).Msg("查询拍卖待上链交易失败")
+		return nil, err
+	}
+	if len(txs) == 0 {
+		return nil, nil
+	}
+	return &txs[0], nil
+}
+
+// GetByHash 根据交易哈希查询，同时匹配被加价替换的历史哈希（均为唯一索引上的等值查询）
+func (r *transactionRepository) GetByHash(hash string) (*models.Transaction, error) {
+	var tx models.Transaction
+	err := r.db.Where("hash = ?", hash).First(&tx).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = r.db.Where("id = (?)", r.db.Model(&models.TransactionHash{}).Select("transaction_id").Where("hash = ?", hash)).
+			First(&tx).Error
+	}
+	if err != nil {
+		log.Error().Err(err).Str("hash", hash).Msg("查询交易失败")
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// List 按条件分页查询交易（按发送时间倒序），同时返回总数和总燃气花费（wei）
+func (r *transactionRepository) List(filter TransactionFilter, pageParams utils.PageParams) ([]models.Transaction, int64, string, error) {
+	var (
+		txs   []models.Transaction
+		total int64
+		sum   struct {
+			TotalGasCost *string
+		}
+	)
+	if err := r.db.Model(&models.Transaction{}).Scopes(filterTransactions(filter)).Count(&total).Error; err != nil {
+		log.Error().Err(err).Msg("统计交易数量失败")
+		return nil, 0, "", err
+	}
+	if err := r.db.Model(&models.Transaction{}).Scopes(filterTransactions(filter)).
+		Select("SUM(gas_cost) AS total_gas_cost").Scan(&sum).Error; err != nil {
+		log.Error().Err(err).Msg("统计燃气花费失败")
+		return nil, 0, "", err
+	}
+	if err := r.db.Scopes(filterTransactions(filter), utils.Paginate(pageParams)).
+		Order("submitted_at DESC").Find(&txs).Error; err != nil {
+		log.Error().Err(err).Msg("查询交易列表失败")
+		return nil, 0, "", err
+	}
+
+	totalGasCost := "0"
+	if sum.TotalGasCost != nil {
+		totalGasCost = *sum.TotalGasCost
+	}
+	return txs, total, totalGasCost, nil
+}
@@ -0,0 +1,121 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+// 熔断器状态
+const (
+	StateClosed   = "closed"    // 正常放行
+	StateOpen     = "open"      // 熔断，拒绝全部请求
+	StateHalfOpen = "half_open" // 放行少量试探请求
+)
+
+// breaker 按连续失败次数熔断
+type breaker struct {
+	threshold   int // <=0表示不熔断
+	openTimeout time.Duration
+	halfOpenMax int
+
+	mu          sync.Mutex
+	state       string
+	consecutive int
+	openedAt    time.Time
+	probes      int // 半开状态下已放行、尚未结束的试探请求数
+}
+
+func newBreaker(threshold int, openTimeout time.Duration, halfOpenMax int) *breaker {
+	return &breaker{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		halfOpenMax: halfOpenMax,
+		state:       StateClosed,
+	}
+}
+
+// allow 是否放行，拒绝时返回距离下次试探的时间
+func (b *breaker) allow() (time.Duration, bool) {
+	if b.threshold <= 0 {
+		return 0, true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		if wait := time.Until(b.openedAt.Add(b.openTimeout)); wait > 0 {
+			return wait, false
+		}
+		b.state, b.probes = StateHalfOpen, 0
+	}
+	if b.state == StateHalfOpen {
+		if b.probes >= b.halfOpenMax {
+			return b.openTimeout, false
+		}
+		b.probes++
+	}
+	return 0, true
+}
+
+// available 不改变状态地判断当前是否可能放行
+func (b *breaker) available() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case StateOpen:
+		return !time.Now().Before(b.openedAt.Add(b.openTimeout))
+	case StateHalfOpen:
+		return b.probes < b.halfOpenMax
+	}
+	return true
+}
+
+// cancel 放行后请求未实际发出（如限流拒绝），归还半开试探名额
+func (b *breaker) cancel() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+// record 记录请求结果，返回是否因此进入熔断
+func (b *breaker) record(failed bool) bool {
+	if b.threshold <= 0 {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.consecutive = 0
+		if b.state == StateHalfOpen {
+			b.state, b.probes = StateClosed, 0
+		}
+		return false
+	}
+
+	b.consecutive++
+	if b.state == StateHalfOpen || b.consecutive >= b.threshold {
+		opened := b.state != StateOpen
+		b.state, b.openedAt, b.probes = StateOpen, time.Now(), 0
+		return opened
+	}
+	return false
+}
+
+func (b *breaker) snapshot(s *Snapshot) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s.State, s.Consecutive = b.state, b.consecutive
+	if b.state != StateClosed && !b.openedAt.IsZero() {
+		openedAt, retryAt := b.openedAt, b.openedAt.Add(b.openTimeout)
+		s.OpenedAt, s.RetryAt = &openedAt, &retryAt
+	}
+}
@@ -0,0 +1,197 @@
+// Package resilience 上游依赖（RPC节点、NFT元数据网关）的令牌桶限流与熔断
+//
+// 每个上游对应一个Guard：请求前先检查熔断器，熔断期间直接拒绝（快速失败，由调用方稍后重试）；
+// 再从令牌桶取令牌，需要等待的时间超过MaxWait时同样拒绝。连续失败达到阈值后熔断，
+// 熔断OpenTimeout后进入半开状态放行少量试探请求，试探成功恢复正常，失败则继续熔断。
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+)
+
+var (
+	// ErrCircuitOpen 上游处于熔断状态，请求被拒绝
+	ErrCircuitOpen = errors.New("上游已熔断")
+	// ErrRateLimited 等待令牌超时，请求被拒绝
+	ErrRateLimited = errors.New("上游请求超出限流")
+)
+
+// RejectedError 被限流或熔断拒绝的请求，RetryAfter为建议的重试等待时间
+type RejectedError struct {
+	Upstream   string
+	Reason     error // ErrCircuitOpen或ErrRateLimited
+	RetryAfter time.Duration
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("%s: %v，%s后重试", e.Upstream, e.Reason, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *RejectedError) Unwrap() error {
+	return e.Reason
+}
+
+// IsRejected 错误是否为限流或熔断拒绝
+func IsRejected(err error) bool {
+	return errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrRateLimited)
+}
+
+// RetryAfter 被拒绝请求的建议重试等待时间，非拒绝错误返回false
+func RetryAfter(err error) (time.Duration, bool) {
+	var rejected *RejectedError
+	if errors.As(err, &rejected) {
+		return rejected.RetryAfter, true
+	}
+	return 0, false
+}
+
+// Guard 单个上游的限流器与熔断器
+type Guard struct {
+	name    string
+	limiter *limiter
+	breaker *breaker
+
+	mu       sync.Mutex
+	requests uint64 // 放行的请求数
+	failures uint64 // 放行后失败的请求数
+	limited  uint64 // 因限流拒绝的请求数
+	shed     uint64 // 因熔断拒绝的请求数
+}
+
+// Snapshot 上游状态快照
+type Snapshot struct {
+	Name          string     `json:"name"`
+	State         string     `json:"state"` // closed / open / half_open
+	Consecutive   int        `json:"consecutive_failures"`
+	OpenedAt      *time.Time `json:"opened_at,omitempty"`
+	RetryAt       *time.Time `json:"retry_at,omitempty"`
+	RatePerSecond float64    `json:"rate_per_second"`
+	Burst         int        `json:"burst"`
+	Tokens        float64    `json:"tokens"`
+	Requests      uint64     `json:"requests"`
+	Failures      uint64     `json:"failures"`
+	RateLimited   uint64     `json:"rate_limited"`
+	Shed          uint64     `json:"shed"`
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]*Guard)
+)
+
+// For 返回名为name的Guard，不存在时按cfg创建（同名上游共享限流与熔断状态）
+func For(name string, cfg config.GuardConfig) *Guard {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if g, ok := registry[name]; ok {
+		return g
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = 1
+	}
+	g := &Guard{
+		name:    name,
+		limiter: newLimiter(cfg.RatePerSecond, cfg.Burst, cfg.MaxWait),
+		breaker: newBreaker(cfg.FailureThreshold, cfg.OpenTimeout, cfg.HalfOpenRequests),
+	}
+	registry[name] = g
+	return g
+}
+
+// Snapshots 全部上游的状态，按名称排序（运维接口使用）
+func Snapshots() []Snapshot {
+	registryMu.Lock()
+	guards := make([]*Guard, 0, len(registry))
+	for _, g := range registry {
+		guards = append(guards, g)
+	}
+	registryMu.Unlock()
+
+	snapshots := make([]Snapshot, 0, len(guards))
+	for _, g := range guards {
+		snapshots = append(snapshots, g.Snapshot())
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots
+}
+
+// Name 上游名称
+func (g *Guard) Name() string {
+	return g.name
+}
+
+// Available 熔断器当前是否放行（不占用半开试探名额，用于在多个上游间挑选）
+func (g *Guard) Available() bool {
+	return g.breaker.available()
+}
+
+// Acquire 申请一次请求：熔断或限流时返回*RejectedError；放行时返回done，请求结束后必须调用，
+// failed表示该次失败应计入熔断（业务错误如合约回滚不应计入）
+func (g *Guard) Acquire(ctx context.Context) (func(failed bool), error) {
+	if retryAfter, ok := g.breaker.allow(); !ok {
+		g.mu.Lock()
+		g.shed++
+		g.mu.Unlock()
+		return nil, &RejectedError{Upstream: g.name, Reason: ErrCircuitOpen, RetryAfter: retryAfter}
+	}
+	if retryAfter, err := g.limiter.wait(ctx); err != nil {
+		g.breaker.cancel()
+		if !errors.Is(err, ErrRateLimited) {
+			return nil, err
+		}
+		g.mu.Lock()
+		g.limited++
+		g.mu.Unlock()
+		return nil, &RejectedError{Upstream: g.name, Reason: ErrRateLimited, RetryAfter: retryAfter}
+	}
+
+	g.mu.Lock()
+	g.requests++
+	g.mu.Unlock()
+	var once sync.Once
+	return func(failed bool) {
+		once.Do(func() {
+			if failed {
+				g.mu.Lock()
+				g.failures++
+				g.mu.Unlock()
+			}
+			if opened := g.breaker.record(failed); opened {
+				log.Warn().Str("upstream", g.name).Msg("上游连续失败，已熔断")
+			}
+		})
+	}, nil
+}
+
+// Do 在Guard保护下执行fn，fn返回的任何错误都计入熔断
+func (g *Guard) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	done, err := g.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn(ctx)
+	done(err != nil)
+	return err
+}
+
+// Snapshot 当前状态
+func (g *Guard) Snapshot() Snapshot {
+	s := Snapshot{Name: g.name}
+	g.breaker.snapshot(&s)
+	g.limiter.snapshot(&s)
+	g.mu.Lock()
+	s.Requests, s.Failures, s.RateLimited, s.Shed = g.requests, g.failures, g.limited, g.shed
+	g.mu.Unlock()
+	return s
+}
@@ -0,0 +1,183 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ydh2333/NFTAuction-project/config"
+)
+
+func TestBreakerTransitions(t *testing.T) {
+	const openTimeout = time.Minute
+
+	// 操作：allow请求放行、ok/fail记录结果、cancel归还试探名额、expire使熔断超时
+	type step struct {
+		op      string
+		allowed bool   // allow的期望结果
+		state   string // 操作后的期望状态
+	}
+	tests := []struct {
+		name      string
+		threshold int
+		halfOpen  int
+		steps     []step
+	}{
+		{"未配置阈值不熔断", 0, 1, []step{
+			{op: "fail", state: StateClosed}, {op: "fail", state: StateClosed}, {op: "allow", allowed: true, state: StateClosed},
+		}},
+		{"连续失败达到阈值熔断", 2, 1, []step{
+			{op: "fail", state: StateClosed}, {op: "fail", state: StateOpen}, {op: "allow", allowed: false, state: StateOpen},
+		}},
+		{"成功清零连续失败", 2, 1, []step{
+			{op: "fail", state: StateClosed}, {op: "ok", state: StateClosed}, {op: "fail", state: StateClosed},
+		}},
+		{"超时后半开试探成功恢复", 1, 1, []step{
+			{op: "fail", state: StateOpen}, {op: "expire", state: StateOpen},
+			{op: "allow", allowed: true, state: StateHalfOpen}, {op: "allow", allowed: false, state: StateHalfOpen},
+			{op: "ok", state: StateClosed}, {op: "allow", allowed: true, state: StateClosed},
+		}},
+		{"半开试探失败重新熔断", 3, 1, []step{
+			{op: "fail", state: StateClosed}, {op: "fail", state: StateClosed}, {op: "fail", state: StateOpen},
+			{op: "expire", state: StateOpen}, {op: "allow", allowed: true, state: StateHalfOpen},
+			{op: "fail", state: StateOpen}, {op: "allow", allowed: false, state: StateOpen},
+		}},
+		{"半开放行多个试探", 1, 2, []step{
+			{op: "fail", state: StateOpen}, {op: "expire", state: StateOpen},
+			{op: "allow", allowed: true, state: StateHalfOpen}, {op: "allow", allowed: true, state: StateHalfOpen},
+			{op: "allow", allowed: false, state: StateHalfOpen},
+		}},
+		{"取消的试探归还名额", 1, 1, []step{
+			{op: "fail", state: StateOpen}, {op: "expire", state: StateOpen},
+			{op: "allow", allowed: true, state: StateHalfOpen}, {op: "cancel", state: StateHalfOpen},
+			{op: "allow", allowed: true, state: StateHalfOpen},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(tt.threshold, openTimeout, tt.halfOpen)
+			for i, s := range tt.steps {
+				switch s.op {
+				case "allow":
+					if _, ok := b.allow(); ok != s.allowed {
+						t.Fatalf("第%d步 allow = %v，期望 %v", i, ok, s.allowed)
+					}
+				case "ok", "fail":
+					b.record(s.op == "fail")
+				case "cancel":
+					b.cancel()
+				case "expire":
+					b.openedAt = b.openedAt.Add(-openTimeout)
+				}
+				var snapshot Snapshot
+				b.snapshot(&snapshot)
+				if snapshot.State != s.state {
+					t.Fatalf("第%d步（%s）后状态 = %s，期望 %s", i, s.op, snapshot.State, s.state)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	t.Run("令牌用完后拒绝并给出等待时间", func(t *testing.T) {
+		l := newLimiter(10, 2, 0)
+		for i := 0; i < 2; i++ {
+			if _, err := l.wait(context.Background()); err != nil {
+				t.Fatalf("第%d个令牌: %v", i, err)
+			}
+		}
+		delay, err := l.wait(context.Background())
+		if !errors.Is(err, ErrRateLimited) || delay <= 0 || delay > 100*time.Millisecond {
+			t.Fatalf("wait = (%s, %v)，期望约100ms后重试的ErrRateLimited", delay, err)
+		}
+	})
+
+	t.Run("等待不超过maxWait时放行", func(t *testing.T) {
+		l := newLimiter(100, 1, time.Second)
+		if _, err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		start := time.Now()
+		if _, err := l.wait(context.Background()); err != nil {
+			t.Fatalf("应等待令牌后放行: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 5*time.Millisecond {
+			t.Errorf("未等待令牌补充，耗时%s", elapsed)
+		}
+	})
+
+	t.Run("上下文截止时间短于等待时间时拒绝", func(t *testing.T) {
+		l := newLimiter(1, 1, time.Minute)
+		if _, err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := l.wait(ctx); !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("wait = %v，期望ErrRateLimited", err)
+		}
+	})
+
+	t.Run("等待中取消归还令牌", func(t *testing.T) {
+		l := newLimiter(1, 1, time.Minute)
+		if _, err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		if _, err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("wait = %v，期望context.Canceled", err)
+		}
+		var snapshot Snapshot
+		l.snapshot(&snapshot)
+		if snapshot.Tokens < -0.1 {
+			t.Errorf("取消后令牌未归还，剩余%.2f", snapshot.Tokens)
+		}
+	})
+
+	t.Run("按流逝时间补充且不超过容量", func(t *testing.T) {
+		l := newLimiter(10, 3, 0)
+		for i := 0; i < 3; i++ {
+			if _, err := l.wait(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		l.last = l.last.Add(-time.Hour)
+		var snapshot Snapshot
+		l.snapshot(&snapshot)
+		if snapshot.Tokens != 3 {
+			t.Errorf("补充后令牌 = %.2f，期望3", snapshot.Tokens)
+		}
+	})
+}
+
+// TestGuardAcquire 熔断后拒绝请求，拒绝与失败计入统计
+func TestGuardAcquire(t *testing.T) {
+	g := For("test:guard", config.GuardConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	done, err := g.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	done(true)
+	done(true) // 重复调用只记录一次
+
+	_, err = g.Acquire(context.Background())
+	if !IsRejected(err) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("熔断后 Acquire = %v，期望ErrCircuitOpen", err)
+	}
+	if wait, ok := RetryAfter(err); !ok || wait <= 0 || wait > time.Minute {
+		t.Errorf("RetryAfter = (%s, %v)", wait, ok)
+	}
+	if g.Available() {
+		t.Error("熔断期间Available应为false")
+	}
+
+	s := g.Snapshot()
+	if s.State != StateOpen || s.Requests != 1 || s.Failures != 1 || s.Shed != 1 {
+		t.Errorf("Snapshot = %+v", s)
+	}
+}
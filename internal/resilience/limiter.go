@@ -0,0 +1,87 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter 令牌桶：按rate匀速补充令牌，最多累积burst个
+type limiter struct {
+	rate    float64 // 每秒补充的令牌数，<=0表示不限流
+	burst   float64
+	maxWait time.Duration
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int, maxWait time.Duration) *limiter {
+	if burst <= 0 {
+		burst = max(int(rate), 1)
+	}
+	return &limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		maxWait: maxWait,
+		tokens:  float64(burst),
+		last:    time.Now(),
+	}
+}
+
+// wait 取一个令牌，令牌不足时等待；所需等待超过maxWait（或上下文截止时间）时不取令牌并返回ErrRateLimited
+func (l *limiter) wait(ctx context.Context) (time.Duration, error) {
+	if l.rate <= 0 {
+		return 0, nil
+	}
+
+	l.mu.Lock()
+	l.refill(time.Now())
+	l.tokens--
+	delay := time.Duration(0)
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	limit := l.maxWait
+	if deadline, ok := ctx.Deadline(); ok {
+		limit = min(limit, time.Until(deadline))
+	}
+	if delay > limit {
+		l.tokens++ // 归还令牌
+		l.mu.Unlock()
+		return delay, ErrRateLimited
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return 0, nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return 0, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return 0, ctx.Err()
+	}
+}
+
+// refill 按流逝时间补充令牌（调用方持有mu）
+func (l *limiter) refill(now time.Time) {
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+}
+
+func (l *limiter) snapshot(s *Snapshot) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s.RatePerSecond, s.Burst = l.rate, int(l.burst)
+	if l.rate > 0 {
+		l.refill(time.Now())
+		s.Tokens = l.tokens
+	}
+}
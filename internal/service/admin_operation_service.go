@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rs/zerolog/log"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/signer"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/txmanager"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/utils"
+	"github.com/ydh2333/NFTAuction-project/utils/logger"
+	"gorm.io/gorm"
+)
+
+// 管理操作审计动作
+const (
+	AdminActionPropose  = "propose"
+	AdminActionApprove  = "approve"
+	AdminActionReject   = "reject"
+	AdminActionSubmit   = "submit"
+	AdminActionSent     = "sent"    // 交易已发送（系统动作）
+	AdminActionTimeout  = "timeout" // 等待回执超时，继续跟踪（系统动作）
+	AdminActionExecuted = "executed"
+	AdminActionFailed   = "failed"
+)
+
+// adminConfigs 各链的管理操作审批配置，按链ID索引（InitAdminOperations初始化）
+var adminConfigs = make(map[uint64]config.AdminConfig)
+
+// InitAdminOperations 设置一条链的管理操作审批配置，各链的管理员与审批人数相互独立
+func InitAdminOperations(chainID uint64, cfg config.AdminConfig) {
+	adminConfigs[chainID] = cfg
+}
+
+// adminConfigOn 指定链的审批配置，未配置的链没有管理员，全部签名都会被拒绝
+func adminConfigOn(chainID uint64) config.AdminConfig {
+	return adminConfigs[chainID]
+}
+
+// AdminSignature 管理员对操作的personal_sign签名
+type AdminSignature struct {
+	Admin     common.Address
+	IssuedAt  int64  // 签名时间（Unix秒），超过SignatureTTL失效
+	Signature []byte // 65字节签名
+}
+
+// AdminMessage 管理员需签名的消息，target为提议内容（ProposalTarget）或操作ID
+func AdminMessage(action, target string, issuedAt int64) string {
+	return fmt.Sprintf("NFTAuction admin operation\nAction: %s\nTarget: %s\nIssued At: %d", action, target, issuedAt)
+}
+
+// ProposalTarget 提议签名内容：目标链、操作类型与规范化后的参数，chainID为0表示默认链
+func ProposalTarget(chainID uint64, opType models.AdminOperationType, params models.AdminOperationParams) string {
+	data, _ := json.Marshal(params)
+	return fmt.Sprintf("chain %d %s %s", chainID, opType, data)
+}
+
+// OperationTarget 审批/驳回/提交签名内容：操作ID
+func OperationTarget(id uint) string {
+	return fmt.Sprintf("operation #%d", id)
+}
+
+// NormalizeAdminParams 校验并规范化操作参数（地址转为校验和格式，调用数据转为小写hex）
+func NormalizeAdminParams(opType models.AdminOperationType, params models.AdminOperationParams) (models.AdminOperationParams, error) {
+	var normalized models.AdminOperationParams
+	switch opType {
+	case models.AdminOperationSetPriceFeed:
+		if params.Token != "" && !common.IsHexAddress(params.Token) {
+			return normalized, errors.New("币种地址格式错误")
+		}
+		if !common.IsHexAddress(params.Feed) {
+			return normalized, errors.New("预言机地址格式错误")
+		}
+		normalized.Token = common.HexToAddress(params.Token).Hex()
+		normalized.Feed = common.HexToAddress(params.Feed).Hex()
+	case models.AdminOperationUpgrade:
+		if !common.IsHexAddress(params.Implementation) {
+			return normalized, errors.New("实现合约地址格式错误")
+		}
+		normalized.Implementation = common.HexToAddress(params.Implementation).Hex()
+		if params.CallData != "" {
+			data, err := hexutil.Decode(params.CallData)
+			if err != nil {
+				return normalized, errors.New("调用数据格式错误")
+			}
+			normalized.CallData = hexutil.Encode(data)
+		}
+	default:
+		return normalized, fmt.Errorf("不支持的操作类型: %s", opType)
+	}
+	return normalized, nil
+}
+
+type AdminOperationService interface {
+	Propose(ctx context.Context, chainID uint64, opType models.AdminOperationType, params models.AdminOperationParams, sig AdminSignature) (*models.AdminOperation, error)
+	Approve(id uint, sig AdminSignature) (*models.AdminOperation, error)
+	Reject(id uint, reason string, sig AdminSignature) (*models.AdminOperation, error)
+	Submit(ctx context.Context, id uint, sig AdminSignature) (*models.AdminOperation, error)
+	GetOperation(id uint) (*models.AdminOperation, error)
+	ListOperations(chainID uint64, status models.AdminOperationStatus, pageParams utils.PageParams) ([]models.AdminOperation, int64, error)
+}
+
+type adminOperationService struct {
+	opRepo repository.AdminOperationRepository
+}
+
+func NewAdminOperationService() AdminOperationService {
+	return &adminOperationService{
+		opRepo: repository.NewAdminOperationRepository(),
+	}
+}
+
+// Propose 提议管理操作：校验管理员签名和链上参数，提议人计为第一个审批
+func (s *adminOperationService) Propose(ctx context.Context, chainID uint64, opType models.AdminOperationType, params models.AdminOperationParams, sig AdminSignature) (*models.AdminOperation, error) {
+	contract, err := auctionContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+	cfg := adminConfigOn(contract.ChainID().Uint64())
+	params, err = NormalizeAdminParams(opType, params)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	if err := s.verify(cfg, AdminActionPropose, ProposalTarget(chainID, opType, params), sig); err != nil {
+		return nil, err
+	}
+
+	validation, err := s.validate(ctx, cfg, contract, opType, params)
+	if err != nil {
+		return nil, badRequest("链上校验失败: %v", err)
+	}
+
+	op := &models.AdminOperation{
+		ChainID:           contract.ChainID().Uint64(),
+		OptTime:           time.Now(),
+		Type:              opType,
+		Params:            params,
+		Status:            models.AdminOperationProposed,
+		Proposer:          sig.Admin.Hex(),
+		Approvals:         []string{sig.Admin.Hex()},
+		RequiredApprovals: s.requiredApprovals(cfg),
+		Validation:        validation,
+	}
+	if len(op.Approvals) >= op.RequiredApprovals {
+		op.Status = models.AdminOperationApproved
+	}
+	event := s.newEvent(AdminActionPropose, sig, validation)
+	if err := s.opRepo.Create(op, event); err != nil {
+		return nil, logger.WrapError(err, "创建管理操作失败")
+	}
+	log.Info().Uint("operation_id", op.ID).Uint64("chain_id", op.ChainID).Str("type", string(opType)).Str("proposer", op.Proposer).Msg("管理操作已提议")
+	return op, nil
+}
+
+// Approve 审批管理操作，审批人数满足后进入approved
+func (s *adminOperationService) Approve(id uint, sig AdminSignature) (*models.AdminOperation, error) {
+	current, err := s.GetOperation(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verify(adminConfigOn(current.ChainID), AdminActionApprove, OperationTarget(id), sig); err != nil {
+		return nil, err
+	}
+	return s.transition(id, func(op *models.AdminOperation) (*models.AdminOperationEvent, error) {
+		if op.Status != models.AdminOperationProposed && op.Status != models.AdminOperationApproved {
+			return nil, badRequest("操作状态为%s，不能审批", op.Status)
+		}
+		for _, approved := range op.Approvals {
+			if strings.EqualFold(approved, sig.Admin.Hex()) {
+				return nil, badRequest("管理员%s已审批", sig.Admin.Hex())
+			}
+		}
+		op.Approvals = append(op.Approvals, sig.Admin.Hex())
+		if len(op.Approvals) >= op.RequiredApprovals {
+			op.Status = models.AdminOperationApproved
+		}
+		return s.newEvent(AdminActionApprove, sig, fmt.Sprintf("%d/%d", len(op.Approvals), op.RequiredApprovals)), nil
+	})
+}
+
+// Reject 驳回未提交的管理操作
+func (s *adminOperationService) Reject(id uint, reason string, sig AdminSignature) (*models.AdminOperation, error) {
+	current, err := s.GetOperation(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verify(adminConfigOn(current.ChainID), AdminActionReject, OperationTarget(id), sig); err != nil {
+		return nil, err
+	}
+	return s.transition(id, func(op *models.AdminOperation) (*models.AdminOperationEvent, error) {
+		if op.Status != models.AdminOperationProposed && op.Status != models.AdminOperationApproved {
+			return nil, badRequest("操作状态为%s，不能驳回", op.Status)
+		}
+		op.Status = models.AdminOperationRejected
+		return s.newEvent(AdminActionReject, sig, reason), nil
+	})
+}
+
+// Submit 重新校验链上参数后以后端签名地址发送交易，后台等待上链并更新状态
+func (s *adminOperationService) Submit(ctx context.Context, id uint, sig AdminSignature) (*models.AdminOperation, error) {
+	current, err := s.GetOperation(id)
+	if err != nil {
+		return nil, err
+	}
+	cfg := adminConfigOn(current.ChainID)
+	if err := s.verify(cfg, AdminActionSubmit, OperationTarget(id), sig); err != nil {
+		return nil, err
+	}
+	contract, err := auctionContract(current.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 后端签名地址必须是合约管理员，否则交易必然回滚
+	admin, err := contract.ContractAdmin(ctx)
+	if err != nil {
+		return nil, logger.WrapError(err, "查询合约管理员失败")
+	}
+	if sender := contract.Sender(); sender != admin {
+		return nil, badRequest("后端签名地址%s不是合约管理员%s", sender.Hex(), admin.Hex())
+	}
+
+	// 链上校验与发送交易都在行锁之外进行：先提交submitting状态占住操作（并发提交、审批与驳回都会被拒绝），
+	// 发送后再记录交易哈希
+	validation, err := s.validate(ctx, cfg, contract, current.Type, current.Params)
+	if err != nil {
+		return nil, badRequest("链上校验失败: %v", err)
+	}
+	op, err := s.transition(id, func(op *models.AdminOperation) (*models.AdminOperationEvent, error) {
+		if op.Status != models.AdminOperationApproved {
+			return nil, badRequest("操作状态为%s，审批%d/%d，不能提交", op.Status, len(op.Approvals), op.RequiredApprovals)
+		}
+		op.Validation = validation
+		op.Status = models.AdminOperationSubmitting
+		return s.newEvent(AdminActionSubmit, sig, validation), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	record, sendErr := s.send(ctx, contract, op)
+	op, err = s.transition(id, func(op *models.AdminOperation) (*models.AdminOperationEvent, error) {
+		now := time.Now()
+		if sendErr != nil {
+			// 交易未被节点接受，退回approved以便重新提交
+			op.Status = models.AdminOperationApproved
+			op.Error = sendErr.Error()
+			return &models.AdminOperationEvent{OptTime: now, Action: AdminActionFailed, Detail: "发送交易失败: " + sendErr.Error()}, nil
+		}
+		op.Status = models.AdminOperationSubmitted
+		op.TxHash = record.Hash
+		op.Error = ""
+		op.SubmittedAt = &now
+		return &models.AdminOperationEvent{OptTime: now, Action: AdminActionSent, Detail: record.Hash}, nil
+	})
+	if sendErr != nil {
+		return nil, logger.WrapError(sendErr, "发送管理操作交易失败")
+	}
+	if err != nil {
+		// 交易已发送，记录哈希失败时仍继续跟踪结果
+		log.Error().Err(err).Uint("operation_id", id).Str("hash", record.Hash).Msg("记录管理操作交易哈希失败")
+		go s.track(contract, id, record)
+		return nil, err
+	}
+
+	go s.track(contract, op.ID, record)
+	return op, nil
+}
+
+// ResumeAdminOperations 启动时恢复跟踪已发送但未得到结果的管理操作交易（需在各链合约初始化后调用）
+func ResumeAdminOperations() {
+	s := &adminOperationService{opRepo: repository.NewAdminOperationRepository()}
+	txRepo := repository.NewTransactionRepository()
+
+	submitting, err := s.opRepo.GetByStatus(models.AdminOperationSubmitting)
+	if err != nil {
+		log.Error().Err(err).Msg("查询发送中的管理操作失败")
+	}
+	for _, op := range submitting {
+		// 发送过程中进程退出，无法确定交易是否已发送，需人工核对交易记录
+		log.Warn().Uint("operation_id", op.ID).Str("type", string(op.Type)).Msg("管理操作停留在发送中状态，请人工核对")
+	}
+
+	submitted, err := s.opRepo.GetByStatus(models.AdminOperationSubmitted)
+	if err != nil {
+		log.Error().Err(err).Msg("查询已发送的管理操作失败")
+		return
+	}
+	for _, op := range submitted {
+		contract, err := auctionContract(op.ChainID)
+		if err != nil {
+			log.Error().Err(err).Uint("operation_id", op.ID).Uint64("chain_id", op.ChainID).Msg("恢复跟踪管理操作失败")
+			continue
+		}
+		record, err := txRepo.GetByHash(op.TxHash)
+		if err != nil {
+			log.Error().Err(err).Uint("operation_id", op.ID).Str("hash", op.TxHash).Msg("恢复跟踪管理操作失败")
+			continue
+		}
+		log.Info().Uint("operation_id", op.ID).Str("hash", record.Hash).Msg("恢复跟踪管理操作交易")
+		go s.track(contract, op.ID, record)
+	}
+}
+
+func (s *adminOperationService) GetOperation(id uint) (*models.AdminOperation, error) {
+	op, err := s.opRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, badRequest("管理操作%d不存在", id)
+		}
+		return nil, logger.WrapError(err, "查询管理操作失败")
+	}
+	return op, nil
+}
+
+func (s *adminOperationService) ListOperations(chainID uint64, status models.AdminOperationStatus, pageParams utils.PageParams) ([]models.AdminOperation, int64, error) {
+	return s.opRepo.List(chainID, status, pageParams)
+}
+
+// verify 校验签名人为该链配置的管理员、签名未过期且与消息匹配
+func (s *adminOperationService) verify(cfg config.AdminConfig, action, target string, sig AdminSignature) error {
+	if !s.isAdmin(cfg, sig.Admin) {
+		return badRequest("%s不是管理员", sig.Admin.Hex())
+	}
+	ttl := cfg.SignatureTTL
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	issuedAt := time.Unix(sig.IssuedAt, 0)
+	if time.Since(issuedAt) > ttl || time.Until(issuedAt) > time.Minute {
+		return badRequest("签名已过期或签名时间无效")
+	}
+	recovered, err := signer.RecoverMessageSigner([]byte(AdminMessage(action, target, sig.IssuedAt)), sig.Signature)
+	if err != nil || recovered != sig.Admin {
+		return badRequest("管理员签名无效")
+	}
+	return nil
+}
+
+// isAdmin 是否为配置的管理员
+func (s *adminOperationService) isAdmin(cfg config.AdminConfig, address common.Address) bool {
+	for _, admin := range cfg.Addresses {
+		if common.IsHexAddress(admin) && common.HexToAddress(admin) == address {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredApprovals 所需审批人数，不超过管理员人数
+func (s *adminOperationService) requiredApprovals(cfg config.AdminConfig) int {
+	required := cfg.RequiredApprovals
+	if required < 1 {
+		required = 1
+	}
+	if required > len(cfg.Addresses) {
+		required = len(cfg.Addresses)
+	}
+	return required
+}
+
+// validate 链上校验操作参数，返回校验结果描述
+func (s *adminOperationService) validate(ctx context.Context, cfg config.AdminConfig, contract *blockchain.AuctionContract, opType models.AdminOperationType, params models.AdminOperationParams) (string, error) {
+	switch opType {
+	case models.AdminOperationSetPriceFeed:
+		info, err := contract.ValidatePriceFeed(ctx, common.HexToAddress(params.Feed), cfg.FeedMaxStaleness)
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(info)
+		return string(data), nil
+	case models.AdminOperationUpgrade:
+		if err := contract.ValidateImplementation(ctx, common.HexToAddress(params.Implementation)); err != nil {
+			return "", err
+		}
+		return "proxiableUUID=" + blockchain.ERC1967ImplementationSlot.Hex(), nil
+	default:
+		return "", fmt.Errorf("不支持的操作类型: %s", opType)
+	}
+}
+
+// send 发送管理操作交易
+func (s *adminOperationService) send(ctx context.Context, contract *blockchain.AuctionContract, op *models.AdminOperation) (*models.Transaction, error) {
+	switch op.Type {
+	case models.AdminOperationSetPriceFeed:
+		return contract.SetPriceETHFeed(ctx, common.HexToAddress(op.Params.Token), common.HexToAddress(op.Params.Feed))
+	case models.AdminOperationUpgrade:
+		callData, _ := hexutil.Decode(op.Params.CallData)
+		if op.Params.CallData == "" {
+			callData = nil
+		}
+		return contract.UpgradeToAndCall(ctx, common.HexToAddress(op.Params.Implementation), callData)
+	default:
+		return nil, fmt.Errorf("不支持的操作类型: %s", op.Type)
+	}
+}
+
+// track 等待交易上链并记录执行结果；等待回执超时不代表交易失败（之后仍可能上链），保持submitted状态继续跟踪
+func (s *adminOperationService) track(contract *blockchain.AuctionContract, id uint, record *models.Transaction) {
+	var waitErr error
+	for {
+		_, waitErr = contract.WaitMined(context.Background(), record)
+		if !errors.Is(waitErr, txmanager.ErrReceiptTimeout) {
+			break
+		}
+		log.Warn().Uint("operation_id", id).Str("hash", record.Hash).Msg("等待管理操作交易回执超时，继续跟踪")
+		_, err := s.opRepo.Transition(id, func(op *models.AdminOperation) (*models.AdminOperationEvent, error) {
+			op.TxHash = record.Hash // 加价替换后哈希会变化
+			return &models.AdminOperationEvent{OptTime: time.Now(), Action: AdminActionTimeout, Detail: record.Hash}, nil
+		})
+		if err != nil {
+			log.Error().Err(err).Uint("operation_id", id).Msg("记录管理操作超时失败")
+		}
+	}
+
+	_, err := s.opRepo.Transition(id, func(op *models.AdminOperation) (*models.AdminOperationEvent, error) {
+		now := time.Now()
+		op.TxHash = record.Hash
+		if waitErr != nil {
+			op.Status = models.AdminOperationFailed
+			op.Error = waitErr.Error()
+			return &models.AdminOperationEvent{OptTime: now, Action: AdminActionFailed, Detail: waitErr.Error()}, nil
+		}
+		op.Status = models.AdminOperationExecuted
+		op.ExecutedAt = &now
+		return &models.AdminOperationEvent{OptTime: now, Action: AdminActionExecuted, Detail: record.Hash}, nil
+	})
+	if err != nil {
+		log.Error().Err(err).Uint("operation_id", id).Msg("更新管理操作结果失败")
+		return
+	}
+	log.Info().Uint("operation_id", id).Str("hash", record.Hash).AnErr("error", waitErr).Msg("管理操作交易已完成")
+}
+
+// transition 修改管理操作状态，操作不存在时返回参数错误
+func (s *adminOperationService) transition(id uint, fn func(op *models.AdminOperation) (*models.AdminOperationEvent, error)) (*models.AdminOperation, error) {
+	op, err := s.opRepo.Transition(id, fn)
+	if err != nil {
+		var appErr *logger.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, badRequest("管理操作%d不存在", id)
+		}
+		return nil, logger.WrapError(err, "更新管理操作失败")
+	}
+	return op, nil
+}
+
+// newEvent 创建审计记录
+func (s *adminOperationService) newEvent(action string, sig AdminSignature, detail string) *models.AdminOperationEvent {
+	return &models.AdminOperationEvent{
+		OptTime:   time.Now(),
+		Action:    action,
+		Actor:     sig.Admin.Hex(),
+		Signature: hexutil.Encode(sig.Signature),
+		Detail:    detail,
+	}
+}
@@ -1,25 +1,55 @@
 package service
 
 import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain"
 	"github.com/ydh2333/NFTAuction-project/internal/models"
 	"github.com/ydh2333/NFTAuction-project/internal/repository"
 )
 
 type AuctionDetailService interface {
-	GetAuctionDetail(auctionId uint) ([]*models.Bid, error)
+	GetAuctionDetail(chainID uint64, contract common.Address, auctionId uint64) ([]*models.Bid, error)
+	GetAuctionResult(chainID uint64, contract common.Address, auctionId uint64) (*models.AuctionResult, error)
 }
 
 type auctionDetailService struct {
-	bidRepo repository.BidRepository
+	bidRepo    repository.BidRepository
+	resultRepo repository.AuctionResultRepository
 }
 
 func NewAuctionDetailService() AuctionDetailService {
 	return &auctionDetailService{
-		bidRepo: repository.NewBidRepository(),
+		bidRepo:    repository.NewBidRepository(),
+		resultRepo: repository.NewAuctionResultRepository(),
 	}
 }
 
-func (a *auctionDetailService) GetAuctionDetail(auctionId uint) ([]*models.Bid, error) {
+// GetAuctionDetail 查询拍卖的出价记录，chainID为0时使用默认链，contract为零地址时使用该链当前部署
+func (a *auctionDetailService) GetAuctionDetail(chainID uint64, contract common.Address, auctionId uint64) ([]*models.Bid, error) {
 
-	return a.bidRepo.GetByAuctionID(auctionId)
+	return a.bidRepo.GetByAuctionID(defaultAuctionKey(chainID, contract, auctionId))
+}
+
+// GetAuctionResult 查询拍卖的成交结果，未成交时返回nil；chainID为0时使用默认链，contract为零地址时使用该链当前部署
+func (a *auctionDetailService) GetAuctionResult(chainID uint64, contract common.Address, auctionId uint64) (*models.AuctionResult, error) {
+	return a.resultRepo.GetByAuction(defaultAuctionKey(chainID, contract, auctionId))
+}
+
+// defaultAuctionKey 补全拍卖标识：chainID为0时使用默认链，contract为零地址时使用该链当前部署的拍卖合约
+func defaultAuctionKey(chainID uint64, contract common.Address, auctionID uint64) models.AuctionKey {
+	chainID = chainOrDefault(chainID)
+	if contract == (common.Address{}) {
+		if current, err := blockchain.AuctionOn(chainID); err == nil {
+			contract = current.Address()
+		}
+	}
+	return models.AuctionKey{ChainID: chainID, Contract: contract.Hex(), ID: auctionID}
+}
+
+// chainOrDefault chainID为0时返回默认链ID
+func chainOrDefault(chainID uint64) uint64 {
+	if chainID == 0 {
+		return blockchain.DefaultChainID()
+	}
+	return chainID
 }
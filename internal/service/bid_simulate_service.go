@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/reverts"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/utils/logger"
+)
+
+// BidSimulateParams 出价模拟参数
+type BidSimulateParams struct {
+	ChainID      uint64         // 为0时使用默认链
+	Contract     common.Address // 拍卖合约地址，零地址表示该链当前部署
+	AuctionID    uint64
+	Bidder       common.Address
+	Amount       *big.Int
+	TokenAddress common.Address // 零地址表示ETH
+}
+
+// BidSimulateResult 出价模拟结果
+type BidSimulateResult struct {
+	*blockchain.CallResult
+	MinBid      string `json:"min_bid"`       // 根据已索引数据计算的最低可接受出价
+	MinBidToken string `json:"min_bid_token"` // 最低出价对应的币种（零地址为ETH）
+}
+
+type BidSimulateService interface {
+	SimulateBid(ctx context.Context, params BidSimulateParams) (*BidSimulateResult, error)
+}
+
+type bidSimulateService struct {
+	auctionRepo repository.AuctionRepository
+}
+
+func NewBidSimulateService() BidSimulateService {
+	return &bidSimulateService{
+		auctionRepo: repository.NewAuctionRepository(),
+	}
+}
+
+// SimulateBid 在最新区块模拟出价，返回回滚原因及最低可接受出价
+func (s *bidSimulateService) SimulateBid(ctx context.Context, params BidSimulateParams) (*BidSimulateResult, error) {
+	contract, err := auctionContractAt(params.ChainID, params.Contract)
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := getIndexedAuction(s.auctionRepo, contract.AuctionKey(params.AuctionID))
+	if err != nil {
+		return nil, err
+	}
+
+	// 已有出价时需高于最高价（同币种），否则不低于起拍价
+	result := &BidSimulateResult{}
+	if auction.HighestBid > 0 {
+		result.MinBid = new(big.Int).SetUint64(auction.HighestBid + 1).String()
+		result.MinBidToken = auction.TokenAddress
+	} else {
+		result.MinBid = new(big.Int).SetUint64(auction.StartPrice).String()
+		result.MinBidToken = auction.StartTokenAddress
+	}
+
+	callResult, err := contract.SimulatePlaceBid(ctx, params.Bidder, new(big.Int).SetUint64(auction.ID), params.Amount, params.TokenAddress)
+	if err != nil {
+		return nil, logger.WrapError(err, "模拟出价失败")
+	}
+	result.CallResult = callResult
+
+	// 合约未返回原因时，补充已索引状态下的可能原因
+	if !callResult.Success && callResult.Revert.Kind == reverts.KindUnknown && strings.EqualFold(result.MinBidToken, params.TokenAddress.Hex()) {
+		minBid, _ := new(big.Int).SetString(result.MinBid, 10)
+		if params.Amount.Cmp(minBid) < 0 {
+			callResult.Revert.Reason = "出价低于最低可接受出价" + result.MinBid
+		}
+	}
+	return result, nil
+}
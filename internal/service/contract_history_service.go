@@ -0,0 +1,25 @@
+package service
+
+import (
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/utils"
+)
+
+type ContractHistoryService interface {
+	GetContractHistory(chainID uint64, contractAddress string, pageParams utils.PageParams) ([]models.ContractHistory, int64, error)
+}
+
+type contractHistoryService struct {
+	historyRepo repository.ContractHistoryRepository
+}
+
+func NewContractHistoryService() ContractHistoryService {
+	return &contractHistoryService{
+		historyRepo: repository.NewContractHistoryRepository(),
+	}
+}
+
+func (s *contractHistoryService) GetContractHistory(chainID uint64, contractAddress string, pageParams utils.PageParams) ([]models.ContractHistory, int64, error) {
+	return s.historyRepo.List(chainID, contractAddress, pageParams)
+}
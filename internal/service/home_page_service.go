@@ -9,13 +9,13 @@ import (
 )
 
 type HomePageService interface {
-	PlatformStatistics() (int, int)
+	PlatformStatistics(chainID uint64) (int, int)
 	SearchAuctionsList(
 		params repository.AuctionSearchParams,
 		sortParams repository.SortParams,
 		pageParams utils.PageParams,
 	) ([]repository.AuctionDetail, error)
-	GetTop5HotAuctions() ([]repository.AuctionDetail, error)
+	GetTop5HotAuctions(chainID uint64) ([]repository.AuctionDetail, error)
 }
 
 type homePageService struct {
@@ -30,14 +30,15 @@ func NewHomePageService() HomePageService {
 	}
 }
 
-func (h *homePageService) PlatformStatistics() (int, int) {
-	auctionCount, err := h.auctionRepo.GetAuctionCount()
+// PlatformStatistics 拍卖与出价总数，chainID为0时统计全部链
+func (h *homePageService) PlatformStatistics(chainID uint64) (int, int) {
+	auctionCount, err := h.auctionRepo.GetAuctionCount(chainID)
 	log.Info().Int64("auctionCount", auctionCount).Msg("获取拍卖总数量")
 	if err != nil {
 		log.Error().Err(err).Msg("获取拍卖总数量失败")
 		return 0, 0
 	}
-	bidCount, err := h.bidRepo.GetBidCount()
+	bidCount, err := h.bidRepo.GetBidCount(chainID)
 	if err != nil {
 		return 0, 0
 	}
@@ -52,9 +53,10 @@ func (h *homePageService) SearchAuctionsList(
 	return h.auctionRepo.SearchAuctions(params, sortParams, pageParams)
 }
 
-func (h *homePageService) GetTop5HotAuctions() ([]repository.AuctionDetail, error) {
+// GetTop5HotAuctions 热门拍卖，chainID为0时在全部链中排行
+func (h *homePageService) GetTop5HotAuctions(chainID uint64) ([]repository.AuctionDetail, error) {
 	// 1. 从redis中获取热门拍卖ID
-	auctionIDs, err := redis.GetTop5HotAuctions()
+	auctionKeys, err := redis.GetTop5HotAuctions(chainID)
 	if err != nil {
 		log.Error().Err(err).Msg("获取热门拍卖ID失败")
 		return nil, err
@@ -62,7 +64,7 @@ func (h *homePageService) GetTop5HotAuctions() ([]repository.AuctionDetail, erro
 
 	// 2. 根据热门拍卖ID获取拍卖详情
 	auctionRepo := repository.NewAuctionRepository()
-	auctionDetails, err := auctionRepo.GetAuctionsByIDs(auctionIDs)
+	auctionDetails, err := auctionRepo.GetAuctionsByKeys(auctionKeys)
 	if err != nil {
 		log.Error().Err(err).Msg("获取热门拍卖失败")
 		return nil, err
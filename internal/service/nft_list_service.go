@@ -1,21 +1,40 @@
 package service
 
 import (
+	"github.com/ydh2333/NFTAuction-project/internal/models"
 	"github.com/ydh2333/NFTAuction-project/internal/repository"
 )
 
 type NFTListService interface {
-	GetNFTList(OwnerAddress string) ([]repository.NftDetail, error)
+	GetNFTList(chainID uint64, OwnerAddress string) ([]repository.NftDetail, error)
+	GetCancelledAuctions(chainID uint64, OwnerAddress string) ([]*models.Auction, error)
+	GetAuctionResults(chainID uint64, address string) ([]*models.AuctionResult, error)
 }
 
 type nftListService struct {
-	nftRepo repository.NFTRepository
+	nftRepo     repository.NFTRepository
+	auctionRepo repository.AuctionRepository
+	resultRepo  repository.AuctionResultRepository
 }
 
 func NewNFTListService() NFTListService {
-	return &nftListService{nftRepo: repository.NewNFTRepository()}
+	return &nftListService{
+		nftRepo:     repository.NewNFTRepository(),
+		auctionRepo: repository.NewAuctionRepository(),
+		resultRepo:  repository.NewAuctionResultRepository(),
+	}
 }
 
-func (s *nftListService) GetNFTList(OwnerAddress string) ([]repository.NftDetail, error) {
-	return s.nftRepo.GetNFTByOwnerAddress(OwnerAddress)
+func (s *nftListService) GetNFTList(chainID uint64, OwnerAddress string) ([]repository.NftDetail, error) {
+	return s.nftRepo.GetNFTByOwnerAddress(chainID, OwnerAddress)
+}
+
+// GetCancelledAuctions 用户创建的已取消拍卖，chainID为0时查询全部链
+func (s *nftListService) GetCancelledAuctions(chainID uint64, OwnerAddress string) ([]*models.Auction, error) {
+	return s.auctionRepo.GetCancelledByCreator(chainID, OwnerAddress)
+}
+
+// GetAuctionResults 用户作为卖家或获胜者的成交结果，chainID为0时查询全部链
+func (s *nftListService) GetAuctionResults(chainID uint64, address string) ([]*models.AuctionResult, error) {
+	return s.resultRepo.GetByParticipant(chainID, address)
 }
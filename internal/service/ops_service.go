@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/rpcpool"
+	"github.com/ydh2333/NFTAuction-project/internal/monitor"
+	"github.com/ydh2333/NFTAuction-project/internal/resilience"
+)
+
+// ErrMonitorDisabled 未启用热钱包监控
+var ErrMonitorDisabled = errors.New("未启用热钱包监控")
+
+type OpsService interface {
+	GetWalletStatus(chainID uint64) (*monitor.WalletStatus, error)
+	GetRPCStats() []rpcpool.PoolStats
+	GetUpstreams() []resilience.Snapshot
+}
+
+type opsService struct{}
+
+func NewOpsService() OpsService {
+	return &opsService{}
+}
+
+// GetWalletStatus 获取指定链（chainID为0时为默认链）热钱包最近一次检查的状态
+func (s *opsService) GetWalletStatus(chainID uint64) (*monitor.WalletStatus, error) {
+	wallet := monitor.WalletFor(chainID)
+	if wallet == nil {
+		return nil, ErrMonitorDisabled
+	}
+	status := wallet.Status()
+	return &status, nil
+}
+
+// GetRPCStats 获取各RPC节点组的健康状态与请求统计
+func (s *opsService) GetRPCStats() []rpcpool.PoolStats {
+	return rpcpool.AllStats()
+}
+
+// GetUpstreams 获取各上游（RPC节点、元数据网关）的限流与熔断状态
+func (s *opsService) GetUpstreams() []resilience.Snapshot {
+	return resilience.Snapshots()
+}
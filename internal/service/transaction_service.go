@@ -0,0 +1,41 @@
+package service
+
+import (
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/utils"
+)
+
+// TransactionList 交易列表及燃气花费汇总
+type TransactionList struct {
+	List         []models.Transaction `json:"list"`
+	Total        int64                `json:"total"`
+	TotalGasCost string               `json:"total_gas_cost"` // 符合条件交易的燃气总花费（wei）
+}
+
+type TransactionService interface {
+	GetTransaction(hash string) (*models.Transaction, error)
+	ListTransactions(filter repository.TransactionFilter, pageParams utils.PageParams) (*TransactionList, error)
+}
+
+type transactionService struct {
+	txRepo repository.TransactionRepository
+}
+
+func NewTransactionService() TransactionService {
+	return &transactionService{
+		txRepo: repository.NewTransactionRepository(),
+	}
+}
+
+func (s *transactionService) GetTransaction(hash string) (*models.Transaction, error) {
+	return s.txRepo.GetByHash(hash)
+}
+
+func (s *transactionService) ListTransactions(filter repository.TransactionFilter, pageParams utils.PageParams) (*TransactionList, error) {
+	txs, total, totalGasCost, err := s.txRepo.List(filter, pageParams)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionList{List: txs, Total: total, TotalGasCost: totalGasCost}, nil
+}
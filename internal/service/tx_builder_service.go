@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain"
+	"github.com/ydh2333/NFTAuction-project/internal/blockchain/reverts"
+	"github.com/ydh2333/NFTAuction-project/internal/models"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"github.com/ydh2333/NFTAuction-project/utils/logger"
+	"gorm.io/gorm"
+)
+
+// CreateAuctionTxParams 创建拍卖交易参数
+type CreateAuctionTxParams struct {
+	ChainID     uint64 // 为0时使用默认链
+	Seller      common.Address
+	Duration    *big.Int // 拍卖时长（秒）
+	StartPrice  *big.Int
+	NFTContract common.Address
+	NFTID       *big.Int
+}
+
+// PlaceBidTxParams 出价交易参数
+type PlaceBidTxParams struct {
+	ChainID      uint64         // 为0时使用默认链
+	Contract     common.Address // 拍卖合约地址，零地址表示该链当前部署
+	Bidder       common.Address
+	AuctionID    uint64
+	Amount       *big.Int
+	TokenAddress common.Address // 零地址表示ETH
+}
+
+// EndAuctionTxParams 结束拍卖交易参数
+type EndAuctionTxParams struct {
+	ChainID   uint64         // 为0时使用默认链
+	Contract  common.Address // 拍卖合约地址，零地址表示该链当前部署
+	From      common.Address
+	AuctionID uint64
+}
+
+// TxBundle 待钱包签名的交易集合，按顺序先签名Prerequisites再签名Transaction
+type TxBundle struct {
+	Prerequisites []*blockchain.UnsignedTx `json:"prerequisites"` // 前置授权交易
+	Transaction   *blockchain.UnsignedTx   `json:"transaction"`   // 目标合约调用，前置交易未执行时gas为0
+}
+
+type TxBuilderService interface {
+	BuildCreateAuction(ctx context.Context, params CreateAuctionTxParams) (*TxBundle, error)
+	BuildPlaceBid(ctx context.Context, params PlaceBidTxParams) (*TxBundle, error)
+	BuildEndAuction(ctx context.Context, params EndAuctionTxParams) (*TxBundle, error)
+}
+
+type txBuilderService struct {
+	auctionRepo repository.AuctionRepository
+	nftRepo     repository.NFTRepository
+}
+
+func NewTxBuilderService() TxBuilderService {
+	return &txBuilderService{
+		auctionRepo: repository.NewAuctionRepository(),
+		nftRepo:     repository.NewNFTRepository(),
+	}
+}
+
+// badRequest 参数校验失败
+func badRequest(msg string, args ...interface{}) *logger.AppError {
+	err := logger.NewErrorf(msg, args...)
+	err.Code = 400
+	return err
+}
+
+// auctionContract 按链ID取拍卖合约，chainID为0时使用默认链
+func auctionContract(chainID uint64) (*blockchain.AuctionContract, error) {
+	contract, err := blockchain.AuctionOn(chainID)
+	if err != nil {
+		if chainID != 0 {
+			return nil, badRequest("未配置链%d", chainID)
+		}
+		return nil, logger.NewErrorf("拍卖合约未初始化")
+	}
+	return contract, nil
+}
+
+// auctionContractAt 按链ID与合约地址取拍卖合约部署，address为零地址时使用该链当前部署
+func auctionContractAt(chainID uint64, address common.Address) (*blockchain.AuctionContract, error) {
+	if address == (common.Address{}) {
+		return auctionContract(chainID)
+	}
+	contract, err := blockchain.AuctionAt(chainID, address)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrUnknownContract) || chainID != 0 {
+			return nil, badRequest("%s", err.Error())
+		}
+		return nil, logger.NewErrorf("拍卖合约未初始化")
+	}
+	return contract, nil
+}
+
+// BuildCreateAuction 构造createAuction交易：校验NFT已索引、卖家持有且未在拍卖中，必要时附带ERC721授权
+func (s *txBuilderService) BuildCreateAuction(ctx context.Context, params CreateAuctionTxParams) (*TxBundle, error) {
+	contract, err := auctionContract(params.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	chainID := contract.ChainID().Uint64()
+	if params.Duration.Sign() <= 0 {
+		return nil, badRequest("拍卖时长必须大于0")
+	}
+	if params.StartPrice.Sign() <= 0 {
+		return nil, badRequest("起拍价必须大于0")
+	}
+	if !params.NFTID.IsUint64() {
+		return nil, badRequest("NFT ID超出范围")
+	}
+
+	if _, err := s.nftRepo.GetNFTByTokenID(chainID, params.NFTContract.Hex(), uint(params.NFTID.Uint64())); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, badRequest("NFT %s:%s未被索引", params.NFTContract.Hex(), params.NFTID)
+		}
+		return nil, logger.WrapError(err, "查询NFT失败")
+	}
+
+	owner, err := contract.NFTOwner(ctx, params.NFTContract, params.NFTID)
+	if err != nil {
+		var revert *reverts.Error
+		if errors.As(err, &revert) {
+			return nil, badRequest("查询NFT持有人失败: %s", revert.Reason)
+		}
+		return nil, logger.WrapError(err, "查询NFT持有人失败")
+	}
+	if owner != params.Seller {
+		return nil, badRequest("卖家%s不是NFT持有人", params.Seller.Hex())
+	}
+
+	auctions, err := s.auctionRepo.GetOpenByNFT(chainID, params.NFTContract.Hex(), uint(params.NFTID.Uint64()))
+	if err != nil {
+		return nil, logger.WrapError(err, "查询NFT拍卖记录失败")
+	}
+	if len(auctions) > 0 {
+		return nil, badRequest("NFT已在拍卖%d中", auctions[0].ID)
+	}
+
+	bundle := &TxBundle{}
+	approval, err := contract.NFTApprovalTx(ctx, params.Seller, params.NFTContract, params.NFTID)
+	if err != nil {
+		return nil, logger.WrapError(err, "构造NFT授权交易失败")
+	}
+	if approval != nil {
+		bundle.Prerequisites = append(bundle.Prerequisites, approval)
+	}
+
+	data, err := contract.PackCreateAuction(params.Seller, params.Duration, params.StartPrice, params.NFTContract, params.NFTID)
+	if err != nil {
+		return nil, logger.WrapError(err, "打包createAuction失败")
+	}
+	return s.finish(ctx, contract, bundle, "createAuction：创建拍卖", params.Seller, data, nil)
+}
+
+// BuildPlaceBid 构造placeBid交易：校验拍卖进行中、出价高于当前最高价，ERC20支付时附带授权
+func (s *txBuilderService) BuildPlaceBid(ctx context.Context, params PlaceBidTxParams) (*TxBundle, error) {
+	contract, err := auctionContractAt(params.ChainID, params.Contract)
+	if err != nil {
+		return nil, err
+	}
+	if params.Amount.Sign() <= 0 {
+		return nil, badRequest("出价金额必须大于0")
+	}
+
+	auction, err := getIndexedAuction(s.auctionRepo, contract.AuctionKey(params.AuctionID))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if auction.Settled || auction.Status == models.AuctionStatusEnded || auction.Status == models.AuctionStatusCancelled {
+		return nil, badRequest("拍卖%d已结束", auction.ID)
+	}
+	if now.Before(auction.StartTime) {
+		return nil, badRequest("拍卖%d尚未开始", auction.ID)
+	}
+	if !now.Before(auction.EndTime) {
+		return nil, badRequest("拍卖%d已过结束时间", auction.ID)
+	}
+	if strings.EqualFold(auction.CreatorAddress, params.Bidder.Hex()) {
+		return nil, badRequest("卖家不能对自己的拍卖出价")
+	}
+	// 合约按预言机价格换算不同币种，后端只能校验同币种下的金额
+	if auction.HighestBid > 0 && strings.EqualFold(auction.TokenAddress, params.TokenAddress.Hex()) &&
+		params.Amount.Cmp(new(big.Int).SetUint64(auction.HighestBid)) <= 0 {
+		return nil, badRequest("出价必须高于当前最高价%d", auction.HighestBid)
+	}
+	if strings.EqualFold(auction.StartTokenAddress, params.TokenAddress.Hex()) &&
+		params.Amount.Cmp(new(big.Int).SetUint64(auction.StartPrice)) < 0 {
+		return nil, badRequest("出价不能低于起拍价%d", auction.StartPrice)
+	}
+
+	bundle := &TxBundle{}
+	if params.TokenAddress == (common.Address{}) {
+		balance, err := contract.ETHBalance(ctx, params.Bidder)
+		if err != nil {
+			return nil, logger.WrapError(err, "查询ETH余额失败")
+		}
+		if balance.Cmp(params.Amount) < 0 {
+			return nil, badRequest("ETH余额不足")
+		}
+	} else {
+		balance, err := contract.ERC20Balance(ctx, params.TokenAddress, params.Bidder)
+		if err != nil {
+			return nil, logger.WrapError(err, "查询ERC20余额失败")
+		}
+		if balance.Cmp(params.Amount) < 0 {
+			return nil, badRequest("ERC20余额不足")
+		}
+		approval, err := contract.ERC20ApprovalTx(ctx, params.Bidder, params.TokenAddress, params.Amount)
+		if err != nil {
+			return nil, logger.WrapError(err, "构造ERC20授权交易失败")
+		}
+		if approval != nil {
+			bundle.Prerequisites = append(bundle.Prerequisites, approval)
+		}
+	}
+
+	auctionID := new(big.Int).SetUint64(auction.ID)
+	data, err := contract.PackPlaceBid(auctionID, params.Amount, params.TokenAddress)
+	if err != nil {
+		return nil, logger.WrapError(err, "打包placeBid失败")
+	}
+	value := blockchain.BidValue(params.Amount, params.TokenAddress)
+	return s.finish(ctx, contract, bundle, "placeBid：拍卖出价", params.Bidder, data, value)
+}
+
+// BuildEndAuction 构造endAuction交易：校验拍卖已过结束时间且尚未结算
+func (s *txBuilderService) BuildEndAuction(ctx context.Context, params EndAuctionTxParams) (*TxBundle, error) {
+	contract, err := auctionContractAt(params.ChainID, params.Contract)
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := getIndexedAuction(s.auctionRepo, contract.AuctionKey(params.AuctionID))
+	if err != nil {
+		return nil, err
+	}
+	if auction.Settled || auction.Status == models.AuctionStatusCancelled {
+		return nil, badRequest("拍卖%d已结束", auction.ID)
+	}
+	if time.Now().Before(auction.EndTime) {
+		return nil, badRequest("拍卖%d尚未到结束时间", auction.ID)
+	}
+
+	data, err := contract.PackEndAuction(new(big.Int).SetUint64(auction.ID))
+	if err != nil {
+		return nil, logger.WrapError(err, "打包endAuction失败")
+	}
+	return s.finish(ctx, contract, &TxBundle{}, "endAuction：结束拍卖", params.From, data, nil)
+}
+
+// getIndexedAuction 查询已索引的拍卖，不存在时返回参数错误
+func getIndexedAuction(auctionRepo repository.AuctionRepository, key models.AuctionKey) (*models.Auction, error) {
+	auction, err := auctionRepo.GetByID(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, badRequest("拍卖%d不存在", key.ID)
+		}
+		return nil, logger.WrapError(err, "查询拍卖失败")
+	}
+	return auction, nil
+}
+
+// finish 构造目标合约调用；没有前置交易时估算燃气，交易会回滚时在Transaction.Revert中返回原因
+func (s *txBuilderService) finish(ctx context.Context, contract *blockchain.AuctionContract, bundle *TxBundle, description string, from common.Address, data []byte, value *big.Int) (*TxBundle, error) {
+	estimate := len(bundle.Prerequisites) == 0
+	tx, err := contract.NewUnsignedTx(ctx, description, from, contract.Address(), data, value, estimate)
+	if err != nil {
+		return nil, logger.WrapError(err, "估算燃气失败")
+	}
+	bundle.Transaction = tx
+	return bundle, nil
+}
@@ -0,0 +1,33 @@
+// Package testutil 测试用的数据库与Redis：SQLite临时库与进程内miniredis，无需外部服务
+package testutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/ydh2333/NFTAuction-project/config"
+	"github.com/ydh2333/NFTAuction-project/internal/redis"
+	"github.com/ydh2333/NFTAuction-project/internal/repository"
+	"gorm.io/gorm/logger"
+)
+
+// InitDB 在测试临时目录创建SQLite库并迁移表结构，设置为repository.DB，测试结束时关闭
+func InitDB(t testing.TB) {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	if err := repository.Open(sqlite.Open(dsn), &config.MySQLConfig{}); err != nil {
+		t.Fatalf("初始化测试数据库失败: %v", err)
+	}
+	repository.DB.Logger = logger.Discard
+	t.Cleanup(repository.CloseDB)
+}
+
+// InitRedis 启动进程内Redis并初始化redis包的客户端，返回服务以便测试直接检查或推进时间
+func InitRedis(t testing.TB) *miniredis.Miniredis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	redis.Init(&config.RedisConfig{Addr: server.Addr(), PoolSize: 4})
+	return server
+}